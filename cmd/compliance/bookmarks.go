@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
+)
+
+// bookmarks are the controls a user has marked in the browser, by program, saved to a JSON file
+// so that they persist between sessions
+type bookmarks struct {
+	path     string
+	programs map[string][]string // Control IDs by program name, in the order they were bookmarked
+}
+
+// defaultBookmarksPath returns the bookmarks file in the user's configuration directory
+func defaultBookmarksPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "mcp-compliance", "bookmarks.json")
+}
+
+// loadBookmarks reads the bookmarks file. A missing file has no bookmarks, and an empty path
+// keeps bookmarks in memory only.
+func loadBookmarks(path string) (*bookmarks, error) {
+	b := &bookmarks{path: path, programs: make(map[string][]string)}
+	if path == "" {
+		return b, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return b, nil
+	}
+	if err != nil {
+		return nil, fedramp.WrapError(fedramp.ErrInternal, err, "failed to read bookmarks from %s", path)
+	}
+	if err := json.Unmarshal(data, &b.programs); err != nil {
+		return nil, fedramp.WrapError(fedramp.ErrInvalidArgument, err, "invalid bookmarks file %s", path)
+	}
+	return b, nil
+}
+
+// has reports whether a control is bookmarked
+func (b *bookmarks) has(program, controlID string) bool {
+	return slices.Contains(b.programs[program], controlID)
+}
+
+// list returns the bookmarked control IDs of a program
+func (b *bookmarks) list(program string) []string {
+	return b.programs[program]
+}
+
+// toggle adds or removes a bookmark, saves the bookmarks and reports whether the control is now
+// bookmarked
+func (b *bookmarks) toggle(program, controlID string) (bool, error) {
+	ids := b.programs[program]
+	marked := !slices.Contains(ids, controlID)
+	if marked {
+		ids = append(ids, controlID)
+	} else {
+		ids = slices.DeleteFunc(ids, func(id string) bool { return id == controlID })
+	}
+	b.programs[program] = ids
+	return marked, b.save()
+}
+
+// save writes the bookmarks file, creating its directory if needed
+func (b *bookmarks) save() error {
+	if b.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(b.programs, "", "  ")
+	if err != nil {
+		return fedramp.WrapError(fedramp.ErrInternal, err, "failed to marshal bookmarks")
+	}
+	if err := os.MkdirAll(filepath.Dir(b.path), 0755); err != nil {
+		return fedramp.WrapError(fedramp.ErrInternal, err, "failed to create %s", filepath.Dir(b.path))
+	}
+	if err := os.WriteFile(b.path, data, 0644); err != nil {
+		return fedramp.WrapError(fedramp.ErrInternal, err, "failed to write bookmarks to %s", b.path)
+	}
+	return nil
+}
@@ -0,0 +1,416 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
+)
+
+// pane is one of the three columns of the browser
+type pane int
+
+const (
+	paneFamilies pane = iota
+	paneControls
+	paneDetail
+)
+
+// browserHelp is the key reference shown on the status line
+const browserHelp = "↑↓ move  ←→ pane  / search  b bookmark  y copy  PgUp/PgDn scroll  q quit"
+
+// browser is the state of the interactive control browser: families on the left, the controls
+// of the selected family or search in the middle and the selected control on the right
+type browser struct {
+	program   string
+	families  []fedramp.ControlFamily
+	bookmarks *bookmarks
+
+	focus        pane
+	family       int // Selected row of the family pane, where row 0 is the bookmarks
+	control      int // Selected row of the control pane
+	detailOffset int // First line of the control shown in the detail pane
+
+	searching bool   // Whether keys are being typed into the search query
+	query     string // Incremental search across the program, which replaces the family's controls
+
+	status    string // Message shown on the status line until the next key
+	clipboard string // Text to copy to the clipboard after the key is handled
+	quit      bool
+}
+
+// runBrowse opens the interactive control browser
+func runBrowse(ctx context.Context, env *environment, args []string) error {
+	fs, common := newFlagSet(env, "browse", "")
+	bookmarksPath := fs.String("bookmarks", defaultBookmarksPath(), "File to keep bookmarked controls in")
+	if err := parseNoArguments(fs, args); err != nil {
+		return err
+	}
+	program, err := resolveProgram(ctx, env, common.program)
+	if err != nil {
+		return err
+	}
+
+	families, err := env.service.ListControlFamilies(ctx, program)
+	if err != nil {
+		return err
+	}
+	marks, err := loadBookmarks(*bookmarksPath)
+	if err != nil {
+		return err
+	}
+
+	term, err := openTerminal(env.stdout)
+	if err != nil {
+		return err
+	}
+	defer term.Close()
+
+	// Redraw after every key and whenever the terminal is resized
+	b := newBrowser(program, families, marks)
+	events := term.events()
+	for !b.quit {
+		width, height := term.size()
+		term.draw(b.render(width, height))
+
+		event := <-events
+		if event.err != nil {
+			return fedramp.WrapError(fedramp.ErrInternal, event.err, "failed to read from the terminal")
+		}
+		for _, key := range event.keys {
+			b.handleKey(key, height)
+		}
+		if b.clipboard != "" {
+			term.copy(b.clipboard)
+			b.clipboard = ""
+		}
+	}
+	return nil
+}
+
+// newBrowser creates a browser showing the first family of a program
+func newBrowser(program string, families []fedramp.ControlFamily, marks *bookmarks) *browser {
+	b := &browser{program: program, families: families, bookmarks: marks, focus: paneControls}
+	if len(families) > 0 {
+		b.family = 1
+	}
+	return b
+}
+
+// controls returns the controls listed in the middle pane: the search matches across the program,
+// the bookmarked controls, or the controls of the selected family
+func (b *browser) controls() []fedramp.Control {
+	if b.query != "" {
+		query := strings.ToLower(b.query)
+		var matches []fedramp.Control
+		for _, family := range b.families {
+			for _, control := range family.Controls {
+				text := strings.ToLower(fedramp.ControlLabel(control.ID) + " " + control.ID + " " + control.Title + " " + control.FullText)
+				if strings.Contains(text, query) {
+					matches = append(matches, control)
+				}
+			}
+		}
+		return matches
+	}
+
+	if b.family == 0 {
+		var marked []fedramp.Control
+		for _, id := range b.bookmarks.list(b.program) {
+			for _, family := range b.families {
+				for _, control := range family.Controls {
+					if control.ID == id {
+						marked = append(marked, control)
+					}
+				}
+			}
+		}
+		return marked
+	}
+	return b.families[b.family-1].Controls
+}
+
+// selected returns the selected control, if any
+func (b *browser) selected() (fedramp.Control, bool) {
+	controls := b.controls()
+	if b.control < 0 || b.control >= len(controls) {
+		return fedramp.Control{}, false
+	}
+	return controls[b.control], true
+}
+
+// handleKey updates the browser for a key press. height is the terminal height, used to page
+// through the detail pane.
+func (b *browser) handleKey(key string, height int) {
+	b.status = ""
+	if b.searching {
+		b.handleSearchKey(key)
+		return
+	}
+
+	page := max(height-4, 1)
+	switch key {
+	case "q", keyCtrlC:
+		b.quit = true
+	case keyUp, "k":
+		b.move(-1)
+	case keyDown, "j":
+		b.move(1)
+	case keyHome, "g":
+		b.move(-1 << 30)
+	case keyEnd, "G":
+		b.move(1 << 30)
+	case keyLeft, "h", keyBackTab:
+		b.focus = max(b.focus-1, paneFamilies)
+	case keyRight, "l", keyTab, keyEnter:
+		b.focus = min(b.focus+1, paneDetail)
+	case keyPageDown, keyCtrlD, " ":
+		b.detailOffset += page
+	case keyPageUp, keyCtrlU:
+		b.detailOffset = max(b.detailOffset-page, 0)
+	case "/":
+		b.searching = true
+		b.focus = paneControls
+	case keyEscape:
+		if b.query != "" {
+			b.query = ""
+			b.control, b.detailOffset = 0, 0
+		}
+	case "b":
+		b.toggleBookmark()
+	case "y", "c":
+		b.copySelected()
+	}
+}
+
+// handleSearchKey edits the search query, updating the matches as each key is typed
+func (b *browser) handleSearchKey(key string) {
+	switch key {
+	case keyEnter:
+		b.searching = false
+	case keyEscape, keyCtrlC:
+		b.searching = false
+		b.query = ""
+	case keyBackspace:
+		if b.query != "" {
+			_, size := utf8.DecodeLastRuneInString(b.query)
+			b.query = b.query[:len(b.query)-size]
+		}
+	default:
+		if utf8.RuneCountInString(key) != 1 {
+			return
+		}
+		b.query += key
+	}
+	b.control, b.detailOffset = 0, 0
+}
+
+// move moves the selection of the focused pane, or scrolls the detail pane
+func (b *browser) move(delta int) {
+	switch b.focus {
+	case paneFamilies:
+		b.family = clamp(b.family+delta, 0, len(b.families))
+		b.query = ""
+		b.control, b.detailOffset = 0, 0
+	case paneControls:
+		b.control = clamp(b.control+delta, 0, max(len(b.controls())-1, 0))
+		b.detailOffset = 0
+	case paneDetail:
+		b.detailOffset = max(b.detailOffset+delta, 0)
+	}
+}
+
+// toggleBookmark bookmarks or unbookmarks the selected control
+func (b *browser) toggleBookmark() {
+	control, ok := b.selected()
+	if !ok {
+		return
+	}
+	marked, err := b.bookmarks.toggle(b.program, control.ID)
+	switch {
+	case err != nil:
+		b.status = "Error: " + err.Error()
+	case marked:
+		b.status = "Bookmarked " + fedramp.ControlLabel(control.ID)
+	default:
+		b.status = "Removed bookmark for " + fedramp.ControlLabel(control.ID)
+		if b.family == 0 && b.query == "" {
+			b.control = clamp(b.control, 0, max(len(b.controls())-1, 0))
+		}
+	}
+}
+
+// copySelected copies the selected control to the clipboard as Markdown
+func (b *browser) copySelected() {
+	control, ok := b.selected()
+	if !ok {
+		return
+	}
+	d := fedramp.NewDocument(fedramp.FormatMarkdown)
+	d.Control(control, 1, nil)
+	b.clipboard = d.String()
+	b.status = "Copied " + fedramp.ControlLabel(control.ID) + " as Markdown"
+}
+
+// render returns the lines of the screen for a terminal of the given size
+func (b *browser) render(width, height int) []string {
+	if width < 40 || height < 5 {
+		return []string{fit("Terminal too small", width)}
+	}
+
+	familyWidth := min(26, width/5)
+	controlWidth := min(40, width/3)
+	detailWidth := width - familyWidth - controlWidth - 6
+	bodyHeight := height - 2
+
+	familyLines := b.renderFamilies(familyWidth, bodyHeight)
+	controlLines := b.renderControls(controlWidth, bodyHeight)
+	detailLines := b.renderDetail(detailWidth, bodyHeight)
+
+	lines := make([]string, 0, height)
+	title := fmt.Sprintf(" %s — %d families", b.program, len(b.families))
+	lines = append(lines, "\x1b[7m"+fit(title, width)+"\x1b[0m")
+	for i := 0; i < bodyHeight; i++ {
+		lines = append(lines, " "+familyLines[i]+" │ "+controlLines[i]+" │ "+detailLines[i])
+	}
+
+	switch {
+	case b.searching:
+		lines = append(lines, fit("/"+b.query+"█", width))
+	case b.status != "":
+		lines = append(lines, fit(b.status, width))
+	default:
+		lines = append(lines, "\x1b[2m"+fit(browserHelp, width)+"\x1b[0m")
+	}
+	return lines
+}
+
+// renderFamilies returns the lines of the family pane, starting with the bookmarks
+func (b *browser) renderFamilies(width, height int) []string {
+	rows := []string{fmt.Sprintf("★ Bookmarks (%d)", len(b.bookmarks.list(b.program)))}
+	for _, family := range b.families {
+		rows = append(rows, strings.ToUpper(family.ID)+" "+family.Title)
+	}
+	selected := b.family
+	if b.query != "" {
+		selected = -1
+	}
+	return b.renderList(rows, selected, b.focus == paneFamilies, width, height)
+}
+
+// renderControls returns the lines of the control pane
+func (b *browser) renderControls(width, height int) []string {
+	controls := b.controls()
+	rows := make([]string, 0, len(controls))
+	for _, control := range controls {
+		mark := "  "
+		if b.bookmarks.has(b.program, control.ID) {
+			mark = "★ "
+		}
+		rows = append(rows, mark+fedramp.ControlLabel(control.ID)+" "+control.Title)
+	}
+	if len(rows) == 0 {
+		switch {
+		case b.query != "":
+			rows = append(rows, "No matches")
+		case b.family == 0:
+			rows = append(rows, "Press b to bookmark a control")
+		}
+		return b.renderList(rows, -1, false, width, height)
+	}
+	return b.renderList(rows, b.control, b.focus == paneControls, width, height)
+}
+
+// renderList returns the lines of a list pane, scrolled so that the selected row is visible.
+// The selected row is shown in reverse video when the pane has focus and in bold otherwise.
+func (b *browser) renderList(rows []string, selected int, focused bool, width, height int) []string {
+	offset := 0
+	if selected >= height {
+		offset = selected - height + 1
+	}
+
+	lines := make([]string, height)
+	for i := range lines {
+		row := offset + i
+		if row >= len(rows) {
+			lines[i] = fit("", width)
+			continue
+		}
+		line := fit(rows[row], width)
+		switch {
+		case row == selected && focused:
+			line = "\x1b[7m" + line + "\x1b[0m"
+		case row == selected:
+			line = "\x1b[1m" + line + "\x1b[0m"
+		}
+		lines[i] = line
+	}
+	return lines
+}
+
+// renderDetail returns the lines of the detail pane: the selected control as wrapped text
+func (b *browser) renderDetail(width, height int) []string {
+	var text []string
+	if control, ok := b.selected(); ok {
+		d := fedramp.NewDocument(fedramp.FormatText)
+		d.Control(control, 1, nil)
+		for _, line := range strings.Split(d.String(), "\n") {
+			text = append(text, wrap(line, width)...)
+		}
+	}
+
+	// Keep at least one line of the control on screen when scrolling past the end
+	b.detailOffset = clamp(b.detailOffset, 0, max(len(text)-1, 0))
+
+	lines := make([]string, height)
+	for i := range lines {
+		line := ""
+		if b.detailOffset+i < len(text) {
+			line = text[b.detailOffset+i]
+		}
+		lines[i] = fit(line, width)
+	}
+	return lines
+}
+
+// wrap breaks a line into lines no wider than width at spaces, keeping its indentation
+func wrap(line string, width int) []string {
+	if utf8.RuneCountInString(line) <= width {
+		return []string{line}
+	}
+	indent := line[:len(line)-len(strings.TrimLeft(line, " "))]
+	var lines []string
+	current := indent
+	for _, word := range strings.Fields(line) {
+		switch {
+		case strings.TrimSpace(current) == "":
+			current += word
+		case utf8.RuneCountInString(current)+1+utf8.RuneCountInString(word) <= width:
+			current += " " + word
+		default:
+			lines = append(lines, current)
+			current = indent + "  " + word
+		}
+	}
+	return append(lines, current)
+}
+
+// fit truncates or pads text to exactly width characters
+func fit(text string, width int) string {
+	count := utf8.RuneCountInString(text)
+	if count > width {
+		runes := []rune(text)
+		if width <= 1 {
+			return string(runes[:width])
+		}
+		return string(runes[:width-1]) + "…"
+	}
+	return text + strings.Repeat(" ", width-count)
+}
+
+// clamp limits a value to a range
+func clamp(value, low, high int) int {
+	return max(low, min(value, high))
+}
@@ -0,0 +1,1058 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/adapters"
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/services/fedramp_implementation"
+)
+
+// runPrograms lists the available compliance programs
+func runPrograms(ctx context.Context, env *environment, args []string) error {
+	fs, common := newFlagSet(env, "programs", "")
+	if _, err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	format, err := parseOutputFormat(common.format)
+	if err != nil {
+		return err
+	}
+
+	programs, err := env.service.ListCompliancePrograms(ctx)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case formatJSON:
+		return writeJSON(env.stdout, programs)
+	case formatMarkdown:
+		return writeDocument(env.stdout, format, func(d *fedramp.Document) {
+			d.Heading(1, "Compliance Programs")
+			for _, program := range programs {
+				d.Item(0, "", program)
+			}
+			d.EndList()
+		})
+	default:
+		rows := make([][]string, 0, len(programs))
+		for _, program := range programs {
+			rows = append(rows, []string{program})
+		}
+		return writeTable(env.stdout, []string{"PROGRAM"}, rows)
+	}
+}
+
+// runFamilies lists the control families of a program with their control counts
+func runFamilies(ctx context.Context, env *environment, args []string) error {
+	fs, common := newFlagSet(env, "families", "")
+	includeWithdrawn := fs.Bool("include-withdrawn", false, "Count controls that have been withdrawn from the catalog")
+	if err := parseNoArguments(fs, args); err != nil {
+		return err
+	}
+	format, err := parseOutputFormat(common.format)
+	if err != nil {
+		return err
+	}
+	program, err := resolveProgram(ctx, env, common.program)
+	if err != nil {
+		return err
+	}
+
+	families, err := env.service.ListControlFamilies(ctx, program, fedramp.IncludeWithdrawn(*includeWithdrawn))
+	if err != nil {
+		return err
+	}
+
+	// Summarize each family by its ID, title and control count
+	type FamilySummary struct {
+		ID           string `json:"id"`
+		Title        string `json:"title"`
+		ControlCount int    `json:"controlCount"`
+	}
+
+	summaries := make([]FamilySummary, 0, len(families))
+	for _, family := range families {
+		summaries = append(summaries, FamilySummary{ID: family.ID, Title: family.Title, ControlCount: len(family.Controls)})
+	}
+
+	switch format {
+	case formatJSON:
+		return writeJSON(env.stdout, summaries)
+	case formatMarkdown:
+		return writeDocument(env.stdout, format, func(d *fedramp.Document) {
+			d.Heading(1, "Control Families in "+program)
+			for _, family := range summaries {
+				d.Item(0, strings.ToUpper(family.ID), fmt.Sprintf("%s (%d controls)", family.Title, family.ControlCount))
+			}
+			d.EndList()
+		})
+	default:
+		rows := make([][]string, 0, len(summaries))
+		for _, family := range summaries {
+			rows = append(rows, []string{strings.ToUpper(family.ID), family.Title, fmt.Sprint(family.ControlCount)})
+		}
+		return writeTable(env.stdout, []string{"FAMILY", "TITLE", "CONTROLS"}, rows)
+	}
+}
+
+// runControl shows one or more controls. A single control is shown in full in every format;
+// several controls are listed one per row in the table format.
+func runControl(ctx context.Context, env *environment, args []string) error {
+	fs, common := newFlagSet(env, "control", "<id|range>...")
+	fields := fs.String("fields", "", "Comma-separated list of control fields to show ("+strings.Join(fedramp.ControlFields, ", ")+")")
+	includeWithdrawn := fs.Bool("include-withdrawn", false, "Include withdrawn controls matched by ranges and wildcards")
+	controlIDs, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(controlIDs) == 0 {
+		return fedramp.NewError(fedramp.ErrInvalidArgument, "at least one control ID is required, e.g. compliance control AC-2")
+	}
+	format, err := parseOutputFormat(common.format)
+	if err != nil {
+		return err
+	}
+	selected, err := fedramp.ParseFields(*fields)
+	if err != nil {
+		return err
+	}
+	program, err := resolveProgram(ctx, env, common.program)
+	if err != nil {
+		return err
+	}
+
+	selection, err := env.service.GetControls(ctx, program, controlIDs, fedramp.IncludeWithdrawn(*includeWithdrawn))
+	if err != nil {
+		return err
+	}
+	if len(selection.Unknown) > 0 {
+		if len(controlIDs) == 1 {
+			// Look the control up on its own for an error that suggests the controls the caller may have meant
+			if _, err := env.service.GetControl(ctx, program, controlIDs[0]); err != nil {
+				return err
+			}
+		}
+		return fedramp.NewError(fedramp.ErrNotFound, "no control in %s matches %s", program, strings.Join(selection.Unknown, ", "))
+	}
+
+	if len(selection.Controls) == 1 {
+		return writeControl(env.stdout, format, selection.Controls[0], selected)
+	}
+
+	switch format {
+	case formatJSON:
+		projected, err := fedramp.ProjectControls(selection.Controls, selected)
+		if err != nil {
+			return err
+		}
+		return writeJSON(env.stdout, projected)
+	case formatMarkdown:
+		return writeDocument(env.stdout, format, func(d *fedramp.Document) {
+			d.Heading(1, "Controls in "+program)
+			d.Controls(selection.Controls, 2, selected)
+		})
+	default:
+		return writeControlTable(env.stdout, selection.Controls)
+	}
+}
+
+// writeControl writes a single control, with a notice explaining where the requirements of a
+// withdrawn control went
+func writeControl(w io.Writer, format outputFormat, control fedramp.Control, fields []string) error {
+	if format != formatJSON {
+		return writeDocument(w, format, func(d *fedramp.Document) {
+			d.Control(control, 1, fields)
+		})
+	}
+
+	projected, err := fedramp.ProjectControl(control, fields)
+	if err != nil {
+		return err
+	}
+	if notice := control.WithdrawnNotice(); notice != "" {
+		projected["notice"] = notice
+	}
+	return writeJSON(w, projected)
+}
+
+// writeControlTable writes controls one per row
+func writeControlTable(w io.Writer, controls []fedramp.Control) error {
+	rows := make([][]string, 0, len(controls))
+	for _, control := range controls {
+		rows = append(rows, []string{fedramp.ControlLabel(control.ID), controlTitle(control)})
+	}
+	return writeTable(w, []string{"CONTROL", "TITLE"}, rows)
+}
+
+// runFamily shows the controls of a family
+func runFamily(ctx context.Context, env *environment, args []string) error {
+	fs, common := newFlagSet(env, "family", "<family>")
+	fields := fs.String("fields", "", "Comma-separated list of control fields to show ("+strings.Join(fedramp.ControlFields, ", ")+")")
+	includeWithdrawn := fs.Bool("include-withdrawn", false, "Include controls that have been withdrawn from the catalog")
+	familyID, err := parseOneArgument(fs, args, "family ID", "compliance family AC")
+	if err != nil {
+		return err
+	}
+	format, err := parseOutputFormat(common.format)
+	if err != nil {
+		return err
+	}
+	selected, err := fedramp.ParseFields(*fields)
+	if err != nil {
+		return err
+	}
+	program, err := resolveProgram(ctx, env, common.program)
+	if err != nil {
+		return err
+	}
+
+	family, err := env.service.GetControlFamily(ctx, program, familyID, fedramp.IncludeWithdrawn(*includeWithdrawn))
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case formatJSON:
+		projected, err := fedramp.ProjectControls(family.Controls, selected)
+		if err != nil {
+			return err
+		}
+		return writeJSON(env.stdout, map[string]any{"id": family.ID, "title": family.Title, "controls": projected})
+	case formatMarkdown:
+		return writeDocument(env.stdout, format, func(d *fedramp.Document) {
+			d.Heading(1, strings.ToUpper(family.ID)+" "+family.Title)
+			d.Controls(family.Controls, 2, selected)
+		})
+	default:
+		return writeControlTable(env.stdout, family.Controls)
+	}
+}
+
+// runSearch searches for controls by keyword
+func runSearch(ctx context.Context, env *environment, args []string) error {
+	fs, common := newFlagSet(env, "search", "<query>")
+	fields := fs.String("fields", "id,title", "Comma-separated list of control fields to show ("+strings.Join(fedramp.ControlFields, ", ")+")")
+	includeWithdrawn := fs.Bool("include-withdrawn", false, "Include controls that have been withdrawn from the catalog")
+	words, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	query := strings.Join(words, " ")
+	if strings.TrimSpace(query) == "" {
+		return fedramp.NewError(fedramp.ErrInvalidArgument, "a search query is required, e.g. compliance search account")
+	}
+	format, err := parseOutputFormat(common.format)
+	if err != nil {
+		return err
+	}
+	selected, err := fedramp.ParseFields(*fields)
+	if err != nil {
+		return err
+	}
+	program, err := resolveProgram(ctx, env, common.program)
+	if err != nil {
+		return err
+	}
+
+	controls, err := env.service.SearchControls(ctx, program, query, fedramp.IncludeWithdrawn(*includeWithdrawn))
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case formatJSON:
+		projected, err := fedramp.ProjectControls(controls, selected)
+		if err != nil {
+			return err
+		}
+		return writeJSON(env.stdout, projected)
+	case formatMarkdown:
+		return writeDocument(env.stdout, format, func(d *fedramp.Document) {
+			d.Heading(1, fmt.Sprintf("Controls matching %q in %s", query, program))
+			if len(controls) == 0 {
+				d.Paragraph("No results.")
+			}
+			d.Controls(controls, 2, selected)
+		})
+	default:
+		return writeControlTable(env.stdout, controls)
+	}
+}
+
+// runEvidenceGuidance shows the evidence expected for each determination statement of a control
+func runEvidenceGuidance(ctx context.Context, env *environment, args []string) error {
+	fs, common := newFlagSet(env, "evidence-guidance", "<id>")
+	controlID, err := parseOneArgument(fs, args, "control ID", "compliance evidence-guidance AC-2")
+	if err != nil {
+		return err
+	}
+	format, err := parseOutputFormat(common.format)
+	if err != nil {
+		return err
+	}
+	program, err := resolveProgram(ctx, env, common.program)
+	if err != nil {
+		return err
+	}
+
+	guidance, err := env.service.GetControlEvidenceGuidance(ctx, program, controlID)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case formatJSON:
+		// Match the response of the get_control_evidence_guidance tool
+		type EvidenceGuidanceResponse struct {
+			ControlID string `json:"controlId"`
+			Program   string `json:"program"`
+			fedramp.EvidenceGuidance
+		}
+		return writeJSON(env.stdout, EvidenceGuidanceResponse{
+			ControlID:        fedramp.NormalizeControlID(controlID),
+			Program:          program,
+			EvidenceGuidance: guidance,
+		})
+	case formatMarkdown:
+		return writeDocument(env.stdout, format, func(d *fedramp.Document) {
+			d.Heading(1, "Evidence Guidance for "+fedramp.ControlLabel(controlID))
+			d.EvidenceGuidance(guidance)
+		})
+	default:
+		rows := make([][]string, 0, len(guidance.Determinations))
+		for _, determination := range guidance.Determinations {
+			rows = append(rows, []string{
+				determination.Label,
+				strings.Join(determination.Methods, ", "),
+				strings.Join(determination.ArtifactTypes, "; "),
+				determination.Statement,
+			})
+		}
+		return writeTable(env.stdout, []string{"DETERMINATION", "METHODS", "ARTIFACTS", "STATEMENT"}, rows)
+	}
+}
+
+// runDiff compares the controls and parameter values of two programs
+func runDiff(ctx context.Context, env *environment, args []string) error {
+	fs, common := newFlagSet(env, "diff", "<from-program> <to-program>")
+	programs, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(programs) != 2 {
+		return fedramp.NewError(fedramp.ErrInvalidArgument, "diff compares two programs, e.g. compliance diff moderate high")
+	}
+	format, err := parseOutputFormat(common.format)
+	if err != nil {
+		return err
+	}
+	from, err := resolveProgram(ctx, env, programs[0])
+	if err != nil {
+		return err
+	}
+	to, err := resolveProgram(ctx, env, programs[1])
+	if err != nil {
+		return err
+	}
+
+	diff, err := env.service.DiffPrograms(ctx, from, to)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case formatJSON:
+		return writeJSON(env.stdout, diff)
+	case formatMarkdown:
+		return writeDocument(env.stdout, format, func(d *fedramp.Document) {
+			d.Heading(1, fmt.Sprintf("Changes from %s to %s", diff.From, diff.To))
+			writeDiffSection(d, fmt.Sprintf("Only in %s", diff.To), diff.Added)
+			writeDiffSection(d, fmt.Sprintf("Only in %s", diff.From), diff.Removed)
+			d.Heading(2, fmt.Sprintf("Parameter Values Changed (%d)", len(diff.Changed)))
+			for _, change := range diff.Changed {
+				d.Item(0, fedramp.ControlLabel(change.ID), change.Title)
+				for _, param := range change.Parameters {
+					d.Item(1, param.ID, parameterChangeText(param))
+				}
+			}
+			d.EndList()
+		})
+	default:
+		var rows [][]string
+		for _, control := range diff.Added {
+			rows = append(rows, []string{"added", fedramp.ControlLabel(control.ID), control.Title, ""})
+		}
+		for _, control := range diff.Removed {
+			rows = append(rows, []string{"removed", fedramp.ControlLabel(control.ID), control.Title, ""})
+		}
+		for _, change := range diff.Changed {
+			for _, param := range change.Parameters {
+				rows = append(rows, []string{"changed", fedramp.ControlLabel(change.ID), change.Title, param.ID + ": " + parameterChangeText(param)})
+			}
+		}
+		return writeTable(env.stdout, []string{"CHANGE", "CONTROL", "TITLE", "DETAIL"}, rows)
+	}
+}
+
+// writeDiffSection adds a list of controls added or removed between programs
+func writeDiffSection(d *fedramp.Document, heading string, controls []fedramp.ControlSummary) {
+	d.Heading(2, fmt.Sprintf("%s (%d)", heading, len(controls)))
+	for _, control := range controls {
+		d.Item(0, fedramp.ControlLabel(control.ID), control.Title)
+	}
+	d.EndList()
+}
+
+// parameterChangeText describes how the values of a parameter changed
+func parameterChangeText(param fedramp.ParameterChange) string {
+	values := func(v []string) string {
+		if len(v) == 0 {
+			return "(not set)"
+		}
+		return strings.Join(v, ", ")
+	}
+	return values(param.From) + " → " + values(param.To)
+}
+
+// runExport writes a whole program, or one family, to stdout or a file
+func runExport(ctx context.Context, env *environment, args []string) error {
+	fs, common := newFlagSet(env, "export", "")
+	familyID := fs.String("family", "", "Only export this control family, e.g. AC")
+	output := fs.String("output", "", "File to write to instead of stdout")
+	includeWithdrawn := fs.Bool("include-withdrawn", false, "Include controls that have been withdrawn from the catalog")
+	if err := parseNoArguments(fs, args); err != nil {
+		return err
+	}
+	format, err := parseOutputFormat(common.format)
+	if err != nil {
+		return err
+	}
+	programName, err := resolveProgram(ctx, env, common.program)
+	if err != nil {
+		return err
+	}
+
+	program, err := env.service.GetProgram(ctx, programName)
+	if err != nil {
+		return err
+	}
+	if *familyID != "" {
+		family, err := env.service.GetControlFamily(ctx, programName, *familyID, fedramp.IncludeWithdrawn(true))
+		if err != nil {
+			return err
+		}
+		program.Families = []fedramp.ControlFamily{family}
+	}
+	if !*includeWithdrawn {
+		for i, family := range program.Families {
+			program.Families[i] = fedramp.ActiveFamily(family)
+		}
+	}
+
+	w := env.stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return fedramp.WrapError(fedramp.ErrInternal, err, "failed to create %s", *output)
+		}
+		defer file.Close()
+		w = file
+	}
+
+	switch format {
+	case formatJSON:
+		err = writeJSON(w, program)
+	case formatMarkdown:
+		err = writeDocument(w, format, func(d *fedramp.Document) {
+			d.Heading(1, program.Name)
+			for _, family := range program.Families {
+				d.Heading(2, strings.ToUpper(family.ID)+" "+family.Title)
+				d.Controls(family.Controls, 3, nil)
+			}
+		})
+	default:
+		var rows [][]string
+		for _, family := range program.Families {
+			for _, control := range family.Controls {
+				rows = append(rows, []string{strings.ToUpper(family.ID), fedramp.ControlLabel(control.ID), controlTitle(control)})
+			}
+		}
+		err = writeTable(w, []string{"FAMILY", "CONTROL", "TITLE"}, rows)
+	}
+	if err != nil {
+		return err
+	}
+
+	if *output != "" {
+		fmt.Fprintf(env.stderr, "Exported %s to %s\n", program.Name, *output)
+	}
+	return nil
+}
+
+// parseNoArguments parses the flags of a subcommand that takes no positional arguments
+func parseNoArguments(fs *flag.FlagSet, args []string) error {
+	arguments, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(arguments) > 0 {
+		return fedramp.NewError(fedramp.ErrInvalidArgument, "%s takes no arguments, got %q", fs.Name(), strings.Join(arguments, " "))
+	}
+	return nil
+}
+
+// parseOneArgument parses the flags of a subcommand that takes a single positional argument
+func parseOneArgument(fs *flag.FlagSet, args []string, name, example string) (string, error) {
+	arguments, err := parseFlags(fs, args)
+	if err != nil {
+		return "", err
+	}
+	if len(arguments) != 1 {
+		return "", fedramp.NewError(fedramp.ErrInvalidArgument, "expected one %s, e.g. %s", name, example)
+	}
+	return arguments[0], nil
+}
+
+// implementationService creates the implementation tracking service for a file of implementation
+// records, which also tracks the programs tailored by overlays
+func (env *environment) implementationService(store string, options ...fedramp_implementation.Option) *fedramp_implementation.Service {
+	if env.overlays != nil {
+		options = append(options, fedramp_implementation.WithOverlayRepository(env.overlays))
+	}
+	return fedramp_implementation.NewService(adapters.NewJSONImplementationRepository(store), options...)
+}
+
+// resolveProgram returns the full name of a program given its name in any case or just its last
+// word, e.g. "high" for "FedRAMP High". Only published programs have short names, so that "moderate"
+// does not pick a tailored program named "Acme Moderate". Names that match no program are returned
+// unchanged so that the service reports them.
+func resolveProgram(ctx context.Context, env *environment, name string) (string, error) {
+	programs, err := env.service.ListCompliancePrograms(ctx)
+	if err != nil {
+		return "", err
+	}
+	name = strings.TrimSpace(name)
+	for _, program := range programs {
+		if strings.EqualFold(program, name) {
+			return program, nil
+		}
+	}
+	var overlays []fedramp.Overlay
+	if env.overlays != nil {
+		if overlays, err = env.overlays.ListOverlays(); err != nil {
+			return "", err
+		}
+	}
+	for _, program := range programs {
+		if slices.ContainsFunc(overlays, func(overlay fedramp.Overlay) bool { return strings.EqualFold(overlay.Name, program) }) {
+			continue
+		}
+		words := strings.Fields(program)
+		if len(words) > 0 && strings.EqualFold(words[len(words)-1], name) {
+			return program, nil
+		}
+	}
+	return name, nil
+}
+
+// runExportSSP writes the OSCAL system security plan of a system, generated from its recorded
+// implementations, to stdout or a file. The markdown and table formats write a summary instead.
+func runExportSSP(ctx context.Context, env *environment, args []string) error {
+	fs, common := newFlagSet(env, "export-ssp", "")
+	format := fs.Lookup("format")
+	format.DefValue = string(formatJSON)
+	format.Value.Set(format.DefValue)
+	system := fs.String("system", "", "Name of the system, as used in its implementation records (required)")
+	description := fs.String("description", "", "Description of the system")
+	store := fs.String("store", adapters.DefaultImplementationStorePath(), "JSON file with the implementation records")
+	componentStore := fs.String("component-store", adapters.DefaultComponentStorePath(), "JSON file with the components that implement controls")
+	output := fs.String("output", "", "File to write to instead of stdout")
+	if err := parseNoArguments(fs, args); err != nil {
+		return err
+	}
+	outputFormat, err := parseOutputFormat(common.format)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(*system) == "" {
+		return fedramp.NewError(fedramp.ErrInvalidArgument, "--system is required")
+	}
+	programName, err := resolveProgram(ctx, env, common.program)
+	if err != nil {
+		return err
+	}
+
+	service := env.implementationService(*store, fedramp_implementation.WithComponentRepository(adapters.NewJSONComponentRepository(*componentStore)))
+	ssp, err := service.ExportSSP(ctx, programName, fedramp.SSPOptions{System: *system, Description: *description})
+	if err != nil {
+		return err
+	}
+
+	w := env.stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return fedramp.WrapError(fedramp.ErrInternal, err, "failed to create %s", *output)
+		}
+		defer file.Close()
+		w = file
+	}
+
+	if outputFormat == formatJSON {
+		err = writeJSON(w, ssp)
+	} else {
+		err = writeDocument(w, outputFormat, func(d *fedramp.Document) {
+			d.SSPSummary(ssp)
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	if *output != "" {
+		fmt.Fprintf(env.stderr, "Exported the system security plan of %s to %s\n", *system, *output)
+	}
+	return nil
+}
+
+// runSaveOverlay checks an overlay file against the programs it tailors and draws on, and saves it
+// to the overlay directory so that every command and the MCP server offer its tailored program
+func runSaveOverlay(ctx context.Context, env *environment, args []string) error {
+	fs, common := newFlagSet(env, "save-overlay", "<overlay.json>")
+	path, err := parseOneArgument(fs, args, "overlay file", "overlay.json")
+	if err != nil {
+		return err
+	}
+	format, err := parseOutputFormat(common.format)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fedramp.NewError(fedramp.ErrNotFound, "file %s not found", path)
+		}
+		return fedramp.WrapError(fedramp.ErrInternal, err, "failed to read %s", path)
+	}
+	var overlay fedramp.Overlay
+	if err := json.Unmarshal(data, &overlay); err != nil {
+		return fedramp.WrapError(fedramp.ErrInvalidArgument, err, "failed to parse the overlay in %s", path)
+	}
+
+	// Accept short program names, as --program does
+	if overlay.Base, err = resolveProgram(ctx, env, overlay.Base); err != nil {
+		return err
+	}
+	for i, addition := range overlay.Add {
+		if overlay.Add[i].From, err = resolveProgram(ctx, env, addition.From); err != nil {
+			return err
+		}
+	}
+
+	overlay, err = env.service.SaveOverlay(ctx, overlay)
+	if err != nil {
+		return err
+	}
+
+	if format == formatJSON {
+		return writeJSON(env.stdout, overlay)
+	}
+	return writeDocument(env.stdout, format, func(d *fedramp.Document) {
+		d.Overlay(overlay)
+	})
+}
+
+// runImportSSP records the implementations described by an OSCAL system security plan and reports
+// the controls missing from the plan or the program
+func runImportSSP(ctx context.Context, env *environment, args []string) error {
+	fs, common := newFlagSet(env, "import-ssp", "<ssp.json>")
+	system := fs.String("system", "", "Name of the system to record the implementations for (default: the system name in the SSP)")
+	store := fs.String("store", adapters.DefaultImplementationStorePath(), "JSON file with the implementation records")
+	dryRun := fs.Bool("dry-run", false, "Only report what would be imported, without changing any records")
+	path, err := parseOneArgument(fs, args, "SSP file", "ssp.json")
+	if err != nil {
+		return err
+	}
+	format, err := parseOutputFormat(common.format)
+	if err != nil {
+		return err
+	}
+	programName, err := resolveProgram(ctx, env, common.program)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fedramp.NewError(fedramp.ErrNotFound, "file %s not found", path)
+		}
+		return fedramp.WrapError(fedramp.ErrInternal, err, "failed to read %s", path)
+	}
+
+	service := env.implementationService(*store)
+	result, err := service.ImportSSP(ctx, programName, data, *system, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	if format == formatJSON {
+		return writeJSON(env.stdout, result)
+	}
+	return writeDocument(env.stdout, format, func(d *fedramp.Document) {
+		d.SSPImport(result)
+	})
+}
+
+// runImportComponentDefinition records the components of an OSCAL component definition and the
+// controls they implement
+func runImportComponentDefinition(ctx context.Context, env *environment, args []string) error {
+	fs, common := newFlagSet(env, "import-component-definition", "<component-definition.json>")
+	fs.Lookup("program").Usage = "Program of control implementations whose source is not a FedRAMP profile"
+	componentStore := fs.String("component-store", adapters.DefaultComponentStorePath(), "JSON file with the components that implement controls")
+	dryRun := fs.Bool("dry-run", false, "Only report what would be imported, without changing any components")
+	path, err := parseOneArgument(fs, args, "component definition file", "component-definition.json")
+	if err != nil {
+		return err
+	}
+	format, err := parseOutputFormat(common.format)
+	if err != nil {
+		return err
+	}
+	programName, err := resolveProgram(ctx, env, common.program)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fedramp.NewError(fedramp.ErrNotFound, "file %s not found", path)
+		}
+		return fedramp.WrapError(fedramp.ErrInternal, err, "failed to read %s", path)
+	}
+
+	service := env.implementationService(adapters.DefaultImplementationStorePath(), fedramp_implementation.WithComponentRepository(adapters.NewJSONComponentRepository(*componentStore)))
+	result, err := service.ImportComponentDefinition(ctx, data, programName, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	if format == formatJSON {
+		return writeJSON(env.stdout, result)
+	}
+	return writeDocument(env.stdout, format, func(d *fedramp.Document) {
+		d.ComponentImport(result)
+	})
+}
+
+// runExportComponentDefinition writes components, or every component, as an OSCAL component
+// definition to stdout or a file. The markdown and table formats write a summary instead.
+func runExportComponentDefinition(ctx context.Context, env *environment, args []string) error {
+	fs, common := newFlagSet(env, "export-component-definition", "[component...]")
+	format := fs.Lookup("format")
+	format.DefValue = string(formatJSON)
+	format.Value.Set(format.DefValue)
+	fs.Lookup("program").Usage = "Only include the controls of this program (default: every program)"
+	fs.Lookup("program").DefValue = ""
+	fs.Lookup("program").Value.Set("")
+	componentStore := fs.String("component-store", adapters.DefaultComponentStorePath(), "JSON file with the components that implement controls")
+	output := fs.String("output", "", "File to write to instead of stdout")
+	names, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	outputFormat, err := parseOutputFormat(common.format)
+	if err != nil {
+		return err
+	}
+	var programName string
+	if common.program != "" {
+		if programName, err = resolveProgram(ctx, env, common.program); err != nil {
+			return err
+		}
+	}
+
+	service := env.implementationService(adapters.DefaultImplementationStorePath(), fedramp_implementation.WithComponentRepository(adapters.NewJSONComponentRepository(*componentStore)))
+	definition, err := service.ExportComponentDefinition(ctx, names, programName)
+	if err != nil {
+		return err
+	}
+
+	w := env.stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return fedramp.WrapError(fedramp.ErrInternal, err, "failed to create %s", *output)
+		}
+		defer file.Close()
+		w = file
+	}
+
+	if outputFormat == formatJSON {
+		err = writeJSON(w, definition)
+	} else {
+		err = writeDocument(w, outputFormat, func(d *fedramp.Document) {
+			d.ComponentDefinitionSummary(definition)
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	if *output != "" {
+		fmt.Fprintf(env.stderr, "Exported %d components to %s\n", len(definition.ComponentDefinition.Components), *output)
+	}
+	return nil
+}
+
+// runImportCRM records the responsibilities described by a leveraged system's customer
+// responsibility matrix, exported from the FedRAMP CRM workbook as CSV
+func runImportCRM(ctx context.Context, env *environment, args []string) error {
+	fs, common := newFlagSet(env, "import-crm", "<crm.csv>")
+	system := fs.String("system", "", "Name of the system that leverages the authorization (required)")
+	leveraged := fs.String("leveraged-authorization", "", "Name of the leveraged authorization the CRM belongs to (required)")
+	store := fs.String("store", adapters.DefaultImplementationStorePath(), "JSON file with the implementation records")
+	dryRun := fs.Bool("dry-run", false, "Only report what would be imported, without changing any records")
+	path, err := parseOneArgument(fs, args, "CRM file", "crm.csv")
+	if err != nil {
+		return err
+	}
+	format, err := parseOutputFormat(common.format)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(*system) == "" {
+		return fedramp.NewError(fedramp.ErrInvalidArgument, "--system is required")
+	}
+	if strings.TrimSpace(*leveraged) == "" {
+		return fedramp.NewError(fedramp.ErrInvalidArgument, "--leveraged-authorization is required")
+	}
+	programName, err := resolveProgram(ctx, env, common.program)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fedramp.NewError(fedramp.ErrNotFound, "file %s not found", path)
+		}
+		return fedramp.WrapError(fedramp.ErrInternal, err, "failed to read %s", path)
+	}
+
+	service := env.implementationService(*store)
+	result, err := service.ImportCRM(ctx, programName, data, *system, *leveraged, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	if format == formatJSON {
+		return writeJSON(env.stdout, result)
+	}
+	return writeDocument(env.stdout, format, func(d *fedramp.Document) {
+		d.CRMImport(result)
+	})
+}
+
+// runExportCRM writes the customer responsibility matrix of a system as CSV in the FedRAMP CRM
+// workbook layout, to stdout or a file
+func runExportCRM(ctx context.Context, env *environment, args []string) error {
+	fs, common := newFlagSet(env, "export-crm", "")
+	format := fs.Lookup("format")
+	format.Usage = "Output format: csv"
+	format.DefValue = "csv"
+	format.Value.Set(format.DefValue)
+	system := fs.String("system", "", "Name of the system, as used in its implementation records (required)")
+	store := fs.String("store", adapters.DefaultImplementationStorePath(), "JSON file with the implementation records")
+	output := fs.String("output", "", "File to write to instead of stdout")
+	if err := parseNoArguments(fs, args); err != nil {
+		return err
+	}
+	if !strings.EqualFold(strings.TrimSpace(common.format), "csv") {
+		return fedramp.NewError(fedramp.ErrInvalidArgument, "unknown format %q for export-crm (valid formats: csv)", common.format)
+	}
+	if strings.TrimSpace(*system) == "" {
+		return fedramp.NewError(fedramp.ErrInvalidArgument, "--system is required")
+	}
+	programName, err := resolveProgram(ctx, env, common.program)
+	if err != nil {
+		return err
+	}
+
+	service := env.implementationService(*store)
+	crm, err := service.ExportCRM(ctx, *system, programName)
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		_, err = env.stdout.Write(crm)
+		return err
+	}
+	if err := os.WriteFile(*output, crm, 0o644); err != nil {
+		return fedramp.WrapError(fedramp.ErrInternal, err, "failed to write %s", *output)
+	}
+	fmt.Fprintf(env.stderr, "Exported the customer responsibility matrix of %s to %s\n", *system, *output)
+	return nil
+}
+
+// runGapAnalysis compares a system's implementation records with a program. Besides the common
+// formats it writes the ranked gaps as CSV with --format csv.
+func runGapAnalysis(ctx context.Context, env *environment, args []string) error {
+	fs, common := newFlagSet(env, "gap-analysis", "")
+	fs.Lookup("format").Usage = "Output format: json, markdown, table or csv"
+	system := fs.String("system", "", "Name of the system, as used in its implementation records (required)")
+	store := fs.String("store", adapters.DefaultImplementationStorePath(), "JSON file with the implementation records")
+	limit := fs.Int("limit", 0, "Maximum number of ranked gaps to list (default: all)")
+	if err := parseNoArguments(fs, args); err != nil {
+		return err
+	}
+	csvFormat := strings.EqualFold(strings.TrimSpace(common.format), "csv")
+	var format outputFormat
+	if !csvFormat {
+		var err error
+		if format, err = parseOutputFormat(common.format); err != nil {
+			return err
+		}
+	}
+	if strings.TrimSpace(*system) == "" {
+		return fedramp.NewError(fedramp.ErrInvalidArgument, "--system is required")
+	}
+	programName, err := resolveProgram(ctx, env, common.program)
+	if err != nil {
+		return err
+	}
+
+	service := env.implementationService(*store)
+	analysis, err := service.GetGapAnalysis(ctx, *system, programName)
+	if err != nil {
+		return err
+	}
+	if *limit > 0 && len(analysis.Gaps) > *limit {
+		analysis.Gaps = analysis.Gaps[:*limit]
+	}
+
+	switch {
+	case csvFormat:
+		return analysis.WriteCSV(env.stdout)
+	case format == formatJSON:
+		return writeJSON(env.stdout, analysis)
+	case format == formatMarkdown:
+		return writeDocument(env.stdout, format, func(d *fedramp.Document) {
+			d.GapAnalysis(analysis, 0)
+		})
+	}
+
+	fmt.Fprintf(env.stdout, "%s: %.1f%% ready (%d of %d controls addressed)\n\n", analysis.Program, analysis.Readiness, analysis.Addressed, analysis.Total)
+	var rows [][]string
+	for _, family := range analysis.Families {
+		row := []string{strings.ToUpper(family.ID), fmt.Sprintf("%.1f%%", family.Readiness)}
+		for _, status := range family.Statuses {
+			row = append(row, strconv.Itoa(status.Count))
+		}
+		rows = append(rows, row)
+	}
+	header := []string{"FAMILY", "READY"}
+	for _, status := range fedramp.GapStatuses {
+		header = append(header, strings.ToUpper(status))
+	}
+	if err := writeTable(env.stdout, header, rows); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(env.stdout)
+	rows = rows[:0]
+	for _, gap := range analysis.Gaps {
+		rows = append(rows, []string{fedramp.ControlLabel(gap.ID), gap.Title, gap.Status, strconv.Itoa(gap.FanOut), strconv.Itoa(len(gap.UnaddressedStatements))})
+	}
+	return writeTable(env.stdout, []string{"GAP", "TITLE", "STATUS", "FAN-OUT", "OPEN STATEMENTS"}, rows)
+}
+
+// runPolicyDocuments generates policy and procedure documents for control families from templates.
+// With --output-dir it writes each document to <family>-<kind>.md instead of stdout.
+func runPolicyDocuments(ctx context.Context, env *environment, args []string) error {
+	fs, common := newFlagSet(env, "policy-docs", "[family...]")
+	format := fs.Lookup("format")
+	format.DefValue = string(formatMarkdown)
+	format.Value.Set(format.DefValue)
+	system := fs.String("system", "", "Name of the system whose parameter values fill in the documents (default: the program's values)")
+	store := fs.String("store", adapters.DefaultImplementationStorePath(), "JSON file with the implementation records")
+	kind := fs.String("kind", "", "Kind of document to generate: policy or procedure (default: both)")
+	templateDir := os.Getenv("MCP_COMPLIANCE_TEMPLATE_DIR")
+	if templateDir == "" {
+		templateDir = adapters.DefaultTemplateDirectory()
+	}
+	templates := fs.String("templates", templateDir, "Directory with templates that replace the default ones")
+	outputDir := fs.String("output-dir", "", "Directory to write the documents to instead of stdout")
+	families, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	outputFormat, err := parseOutputFormat(common.format)
+	if err != nil {
+		return err
+	}
+	var kinds []fedramp.PolicyDocumentKind
+	if strings.TrimSpace(*kind) != "" {
+		parsed, err := fedramp.ParsePolicyDocumentKind(*kind)
+		if err != nil {
+			return err
+		}
+		kinds = append(kinds, parsed)
+	}
+	programName, err := resolveProgram(ctx, env, common.program)
+	if err != nil {
+		return err
+	}
+
+	service := env.implementationService(*store, fedramp_implementation.WithTemplateRepository(adapters.NewFileTemplateRepository(*templates)))
+	documents, err := service.GeneratePolicyDocuments(ctx, *system, programName, families, kinds, nil)
+	if err != nil {
+		return err
+	}
+
+	if *outputDir != "" {
+		if err := os.MkdirAll(*outputDir, 0o755); err != nil {
+			return fedramp.WrapError(fedramp.ErrInternal, err, "failed to create %s", *outputDir)
+		}
+		for _, document := range documents {
+			path := filepath.Join(*outputDir, fmt.Sprintf("%s-%s.md", strings.ToLower(document.FamilyID), document.Kind))
+			if err := os.WriteFile(path, []byte(document.Markdown), 0o644); err != nil {
+				return fedramp.WrapError(fedramp.ErrInternal, err, "failed to write %s", path)
+			}
+			fmt.Fprintf(env.stderr, "Wrote the %s to %s\n", document.Title, path)
+		}
+		return nil
+	}
+
+	switch outputFormat {
+	case formatJSON:
+		return writeJSON(env.stdout, documents)
+	case formatMarkdown:
+		for i, document := range documents {
+			if i > 0 {
+				fmt.Fprintln(env.stdout)
+			}
+			fmt.Fprint(env.stdout, document.Markdown)
+		}
+		return nil
+	}
+	var rows [][]string
+	for _, document := range documents {
+		rows = append(rows, []string{document.FamilyID, string(document.Kind), document.Title, document.Template})
+	}
+	return writeTable(env.stdout, []string{"FAMILY", "KIND", "TITLE", "TEMPLATE"}, rows)
+}
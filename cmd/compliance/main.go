@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/adapters"
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/ports"
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/services/fedramp_compliance"
+)
+
+// Exit codes returned by the CLI so that scripts can tell failures apart
+const (
+	exitOK                 = 0 // The command succeeded
+	exitError              = 1 // An unexpected internal error
+	exitUsage              = 2 // Unknown subcommand, bad flags or an invalid argument
+	exitNotFound           = 3 // The requested control, family or other object does not exist
+	exitProgramUnavailable = 4 // The requested program is unknown or its data cannot be loaded
+)
+
+// command is a CLI subcommand
+type command struct {
+	name    string
+	args    string // Positional arguments, for the usage text
+	summary string
+	run     func(ctx context.Context, env *environment, args []string) error
+}
+
+// environment is what every subcommand runs against
+type environment struct {
+	service  *fedramp_compliance.Service
+	overlays ports.OverlayRepository // Overlays that tailor programs, if any
+	stdout   io.Writer
+	stderr   io.Writer
+}
+
+// commands lists the subcommands in the order they are shown in the usage text
+var commands = []command{
+	{"programs", "", "List the available compliance programs", runPrograms},
+	{"families", "", "List the control families of a program", runFamilies},
+	{"control", "<id|range>...", "Show one or more controls, e.g. AC-2, AC-2(*) or \"AC-2 through AC-6\"", runControl},
+	{"family", "<family>", "Show the controls of a family, e.g. AC", runFamily},
+	{"search", "<query>", "Search for controls by keyword", runSearch},
+	{"evidence-guidance", "<id>", "Show the evidence expected for each determination statement of a control", runEvidenceGuidance},
+	{"diff", "<from-program> <to-program>", "Compare the controls and parameter values of two programs", runDiff},
+	{"export", "", "Export a whole program, or one family with --family", runExport},
+	{"save-overlay", "<overlay.json>", "Check an overlay that tailors a program and save it as a program of its own", runSaveOverlay},
+	{"export-ssp", "", "Export the OSCAL system security plan of a system from its implementation records", runExportSSP},
+	{"import-ssp", "<ssp.json>", "Import the implementations described by an OSCAL system security plan", runImportSSP},
+	{"import-component-definition", "<component-definition.json>", "Import the components of an OSCAL component definition and the controls they implement", runImportComponentDefinition},
+	{"export-component-definition", "[component...]", "Export components as an OSCAL component definition", runExportComponentDefinition},
+	{"import-crm", "<crm.csv>", "Import the responsibilities in a leveraged system's customer responsibility matrix (CRM)", runImportCRM},
+	{"export-crm", "", "Export the customer responsibility matrix (CRM) of a system as CSV", runExportCRM},
+	{"gap-analysis", "", "Report how far a system is from a program, with gaps ranked by related-control fan-out", runGapAnalysis},
+	{"policy-docs", "[family...]", "Generate policy and procedure documents for control families from templates", runPolicyDocuments},
+	{"browse", "", "Browse families and controls interactively, with search, bookmarks and copy as Markdown", runBrowse},
+}
+
+func main() {
+	overlayDir := os.Getenv("MCP_COMPLIANCE_OVERLAY_DIR")
+	if overlayDir == "" {
+		overlayDir = adapters.DefaultOverlayDirectory()
+	}
+	overlays := adapters.NewJSONOverlayRepository(overlayDir)
+	env := &environment{
+		service:  fedramp_compliance.NewService(fedramp_compliance.WithOverlayRepository(overlays)),
+		overlays: overlays,
+		stdout:   os.Stdout,
+		stderr:   os.Stderr,
+	}
+	os.Exit(run(context.Background(), env, os.Args[1:]))
+}
+
+// run runs the subcommand named by the first argument and returns the process exit code
+func run(ctx context.Context, env *environment, args []string) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		usage(env.stderr)
+		if len(args) == 0 {
+			return exitUsage
+		}
+		return exitOK
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			return exitCode(env, cmd.run(ctx, env, args[1:]))
+		}
+	}
+
+	names := make([]string, 0, len(commands))
+	for _, cmd := range commands {
+		names = append(names, cmd.name)
+	}
+	err := fedramp.NewError(fedramp.ErrInvalidArgument, "unknown command %q", args[0]).
+		WithSuggestions(fedramp.ClosestMatches(args[0], names)...)
+	fmt.Fprintf(env.stderr, "Error: %v\n\n", err)
+	usage(env.stderr)
+	return exitUsage
+}
+
+// exitCode reports an error on stderr and returns the exit code for its kind
+func exitCode(env *environment, err error) int {
+	if err == nil {
+		return exitOK
+	}
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+	fmt.Fprintf(env.stderr, "Error: %v\n", err)
+
+	switch fedramp.KindOf(err) {
+	case fedramp.ErrInvalidArgument:
+		return exitUsage
+	case fedramp.ErrNotFound:
+		return exitNotFound
+	case fedramp.ErrProgramUnavailable:
+		return exitProgramUnavailable
+	default:
+		return exitError
+	}
+}
+
+// usage writes the list of subcommands
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: compliance <command> [flags] [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	width := 0
+	for _, cmd := range commands {
+		width = max(width, len(cmd.name))
+	}
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-*s  %s\n", width, cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run 'compliance <command> -h' for the flags of a command. Every command accepts")
+	fmt.Fprintln(w, "--format json|markdown|table (default table).")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Exit codes:")
+	fmt.Fprintf(w, "  %d  success\n", exitOK)
+	fmt.Fprintf(w, "  %d  internal error\n", exitError)
+	fmt.Fprintf(w, "  %d  invalid usage or argument\n", exitUsage)
+	fmt.Fprintf(w, "  %d  control, family or other object not found\n", exitNotFound)
+	fmt.Fprintf(w, "  %d  program unknown or unavailable\n", exitProgramUnavailable)
+}
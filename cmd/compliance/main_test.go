@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/services/fedramp_compliance"
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		stdout string // Text the output must contain
+		stderr string // Text the errors must contain
+	}{
+		{"no command", nil, exitUsage, "", "Usage: compliance"},
+		{"help", []string{"help"}, exitOK, "", "Exit codes:"},
+		{"unknown command", []string{"contrl"}, exitUsage, "", `unknown command "contrl" (did you mean control?)`},
+		{"command help", []string{"control", "-h"}, exitOK, "", "Usage: compliance control [flags] <id|range>..."},
+		{"unknown flag", []string{"programs", "--verbose"}, exitUsage, "", "invalid flags for programs"},
+		{"unknown format", []string{"programs", "--format", "yaml"}, exitUsage, "", `unknown format "yaml"`},
+		{"programs", []string{"programs"}, exitOK, "FedRAMP Moderate", ""},
+		{"flags after arguments and a short program name", []string{"control", "AC-2", "--program", "moderate", "--format", "markdown"}, exitOK, "# AC-2 Account Management", ""},
+		{"missing argument", []string{"family"}, exitUsage, "", "expected one family"},
+		{"unexpected argument", []string{"families", "AC"}, exitUsage, "", `families takes no arguments, got "AC"`},
+		{"unknown control", []string{"control", "ZZ-99"}, exitNotFound, "", "Error:"},
+		{"unknown program", []string{"families", "--program", "FedRAMP Nope"}, exitProgramUnavailable, "", "Error:"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			env := &environment{service: fedramp_compliance.NewService(), stdout: &stdout, stderr: &stderr}
+			if code := run(context.Background(), env, tt.args); code != tt.code {
+				t.Errorf("exit code = %d, want %d; stderr: %s", code, tt.code, stderr.String())
+			}
+			if !strings.Contains(stdout.String(), tt.stdout) {
+				t.Errorf("stdout = %q, want it to contain %q", stdout.String(), tt.stdout)
+			}
+			if !strings.Contains(stderr.String(), tt.stderr) {
+				t.Errorf("stderr = %q, want it to contain %q", stderr.String(), tt.stderr)
+			}
+		})
+	}
+}
+
+func TestParseFlags(t *testing.T) {
+	tests := []struct {
+		args       []string
+		positional []string
+		program    string
+		format     string
+	}{
+		{nil, nil, "FedRAMP High", "table"},
+		{[]string{"AC-2", "AC-3"}, []string{"AC-2", "AC-3"}, "FedRAMP High", "table"},
+		{[]string{"--program", "moderate", "AC-2"}, []string{"AC-2"}, "moderate", "table"},
+		{[]string{"AC-2", "-format=json", "AC-3", "-program", "low"}, []string{"AC-2", "AC-3"}, "low", "json"},
+		{[]string{"AC-2", "--", "-format"}, []string{"AC-2", "-format"}, "FedRAMP High", "table"},
+	}
+	for _, tt := range tests {
+		fs, common := newFlagSet(&environment{stderr: io.Discard}, "test", "")
+		positional, err := parseFlags(fs, tt.args)
+		if err != nil {
+			t.Errorf("parseFlags(%q) returned error: %v", tt.args, err)
+			continue
+		}
+		if !slices.Equal(positional, tt.positional) || common.program != tt.program || common.format != tt.format {
+			t.Errorf("parseFlags(%q) = %q, program %q, format %q, want %q, %q, %q",
+				tt.args, positional, common.program, common.format, tt.positional, tt.program, tt.format)
+		}
+	}
+
+	fs, _ := newFlagSet(&environment{stderr: io.Discard}, "test", "")
+	if _, err := parseFlags(fs, []string{"-h"}); err != flag.ErrHelp {
+		t.Errorf("-h: error = %v, want flag.ErrHelp", err)
+	}
+	fs, _ = newFlagSet(&environment{stderr: io.Discard}, "test", "")
+	if _, err := parseFlags(fs, []string{"--program"}); fedramp.KindOf(err) != fedramp.ErrInvalidArgument {
+		t.Errorf("--program without a value: error = %v, want an invalid argument", err)
+	}
+}
+
+func TestParseOutputFormat(t *testing.T) {
+	tests := []struct {
+		input string
+		want  outputFormat
+		kind  fedramp.ErrorKind
+	}{
+		{"", formatTable, ""},
+		{"JSON", formatJSON, ""},
+		{" md ", formatMarkdown, ""},
+		{"table", formatTable, ""},
+		{"jsno", "", fedramp.ErrInvalidArgument},
+	}
+	for _, tt := range tests {
+		got, err := parseOutputFormat(tt.input)
+		if got != tt.want || (tt.kind == "" && err != nil) || (tt.kind != "" && fedramp.KindOf(err) != tt.kind) {
+			t.Errorf("parseOutputFormat(%q) = %q, %v, want %q, %s", tt.input, got, err, tt.want, tt.kind)
+		}
+	}
+}
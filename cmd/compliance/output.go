@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
+)
+
+// outputFormat is the format the CLI writes results in
+type outputFormat string
+
+const (
+	formatJSON     outputFormat = "json"
+	formatMarkdown outputFormat = "markdown"
+	formatTable    outputFormat = "table"
+)
+
+// outputFormats lists the names of the supported output formats
+var outputFormats = []string{string(formatJSON), string(formatMarkdown), string(formatTable)}
+
+// parseOutputFormat parses the name of an output format
+func parseOutputFormat(s string) (outputFormat, error) {
+	switch outputFormat(strings.ToLower(strings.TrimSpace(s))) {
+	case formatJSON:
+		return formatJSON, nil
+	case formatMarkdown, "md":
+		return formatMarkdown, nil
+	case formatTable, "":
+		return formatTable, nil
+	}
+	return "", fedramp.NewError(fedramp.ErrInvalidArgument, "unknown format %q (valid formats: %s)", s, strings.Join(outputFormats, ", ")).
+		WithSuggestions(fedramp.ClosestMatches(s, outputFormats)...)
+}
+
+// commonFlags are the flags every subcommand accepts
+type commonFlags struct {
+	program string
+	format  string
+}
+
+// newFlagSet creates the flag set of a subcommand with the common flags registered. The usage
+// text is written to stderr for -h and for flag errors.
+func newFlagSet(env *environment, cmd, args string) (*flag.FlagSet, *commonFlags) {
+	common := &commonFlags{}
+	fs := flag.NewFlagSet(cmd, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Usage = func() {
+		fmt.Fprintf(env.stderr, "Usage: compliance %s [flags] %s\n\nFlags:\n", cmd, args)
+		fs.SetOutput(env.stderr)
+		fs.PrintDefaults()
+		fs.SetOutput(io.Discard)
+	}
+	fs.StringVar(&common.program, "program", "FedRAMP High", "Program to query, e.g. \"FedRAMP High\" or \"moderate\"")
+	fs.StringVar(&common.format, "format", string(formatTable), "Output format: json, markdown or table")
+	return fs, common
+}
+
+// parseFlags parses the flags of a subcommand, which may appear before, between or after its
+// positional arguments, and returns the positional arguments
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if err == flag.ErrHelp {
+				return nil, err
+			}
+			return nil, fedramp.WrapError(fedramp.ErrInvalidArgument, err, "invalid flags for %s", fs.Name())
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// writeJSON writes a value as indented JSON
+func writeJSON(w io.Writer, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fedramp.WrapError(fedramp.ErrInternal, err, "failed to marshal result to JSON")
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+// writeDocument writes the document built by render, as Markdown or, for the table format, as
+// plain text
+func writeDocument(w io.Writer, format outputFormat, render func(d *fedramp.Document)) error {
+	documentFormat := fedramp.FormatMarkdown
+	if format == formatTable {
+		documentFormat = fedramp.FormatText
+	}
+	d := fedramp.NewDocument(documentFormat)
+	render(d)
+	_, err := io.WriteString(w, d.String())
+	return err
+}
+
+// writeTable writes rows as aligned columns under a header
+func writeTable(w io.Writer, header []string, rows [][]string) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = strings.Join(strings.Fields(cell), " ")
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
+
+// controlTitle returns the title of a control, marking withdrawn controls
+func controlTitle(control fedramp.Control) string {
+	if control.IsWithdrawn() {
+		return control.Title + " (withdrawn)"
+	}
+	return control.Title
+}
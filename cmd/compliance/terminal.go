@@ -0,0 +1,226 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
+)
+
+// Keys that are not printable characters, as returned by readKeys
+const (
+	keyUp        = "up"
+	keyDown      = "down"
+	keyLeft      = "left"
+	keyRight     = "right"
+	keyPageUp    = "pgup"
+	keyPageDown  = "pgdown"
+	keyHome      = "home"
+	keyEnd       = "end"
+	keyEnter     = "enter"
+	keyEscape    = "esc"
+	keyTab       = "tab"
+	keyBackTab   = "backtab"
+	keyBackspace = "backspace"
+	keyCtrlC     = "ctrl-c"
+	keyCtrlD     = "ctrl-d"
+	keyCtrlU     = "ctrl-u"
+)
+
+// escapeKeys maps the escape sequences sent by common terminals to key names
+var escapeKeys = map[string]string{
+	"\x1b[A":  keyUp,
+	"\x1b[B":  keyDown,
+	"\x1b[C":  keyRight,
+	"\x1b[D":  keyLeft,
+	"\x1bOA":  keyUp,
+	"\x1bOB":  keyDown,
+	"\x1bOC":  keyRight,
+	"\x1bOD":  keyLeft,
+	"\x1b[5~": keyPageUp,
+	"\x1b[6~": keyPageDown,
+	"\x1b[H":  keyHome,
+	"\x1b[F":  keyEnd,
+	"\x1b[1~": keyHome,
+	"\x1b[4~": keyEnd,
+	"\x1b[Z":  keyBackTab,
+}
+
+// terminal is an interactive terminal in raw mode, drawn on the alternate screen. It relies on
+// stty rather than a terminal library so that the CLI has no dependencies beyond the MCP SDK.
+type terminal struct {
+	in      *os.File
+	out     io.Writer
+	saved   string         // stty settings to restore on Close
+	resized chan os.Signal // Resize signals, once events has been called
+}
+
+// terminalEvent is a batch of keys read from the terminal, or a resize of the terminal, which has
+// no keys
+type terminalEvent struct {
+	keys []string
+	err  error
+}
+
+// openTerminal switches stdin to raw mode and stdout to the alternate screen
+func openTerminal(out io.Writer) (*terminal, error) {
+	info, err := os.Stdin.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return nil, fedramp.NewError(fedramp.ErrInvalidArgument, "browse needs an interactive terminal")
+	}
+
+	saved, err := stty("-g")
+	if err != nil {
+		return nil, fedramp.WrapError(fedramp.ErrInternal, err, "failed to read terminal settings")
+	}
+	if _, err := stty("raw", "-echo"); err != nil {
+		return nil, fedramp.WrapError(fedramp.ErrInternal, err, "failed to switch the terminal to raw mode")
+	}
+
+	// Switch to the alternate screen and hide the cursor
+	fmt.Fprint(out, "\x1b[?1049h\x1b[?25l")
+	return &terminal{in: os.Stdin, out: out, saved: strings.TrimSpace(saved)}, nil
+}
+
+// Close restores the terminal settings and the main screen
+func (t *terminal) Close() error {
+	if t.resized != nil {
+		signal.Stop(t.resized)
+	}
+	fmt.Fprint(t.out, "\x1b[?25h\x1b[?1049l")
+	_, err := stty(t.saved)
+	return err
+}
+
+// size returns the width and height of the terminal, falling back to 80x24
+func (t *terminal) size() (width, height int) {
+	output, err := stty("size")
+	if err == nil {
+		if _, err := fmt.Sscan(output, &height, &width); err == nil && width > 0 && height > 0 {
+			return width, height
+		}
+	}
+	return 80, 24
+}
+
+// draw replaces the screen with lines, which must already fit the terminal
+func (t *terminal) draw(lines []string) {
+	var screen strings.Builder
+	screen.WriteString("\x1b[H")
+	for i, line := range lines {
+		if i > 0 {
+			screen.WriteString("\r\n")
+		}
+		screen.WriteString(line)
+		screen.WriteString("\x1b[K")
+	}
+	screen.WriteString("\x1b[J")
+	io.WriteString(t.out, screen.String())
+}
+
+// copy puts text on the system clipboard with the OSC 52 escape sequence, which most terminal
+// emulators support, including over SSH
+func (t *terminal) copy(text string) {
+	fmt.Fprintf(t.out, "\x1b]52;c;%s\a", base64.StdEncoding.EncodeToString([]byte(text)))
+}
+
+// events starts reading keys and watching for resizes, and returns the channel both are sent on.
+// A read error is the last event sent. Resizes are only reported where the terminal signals them.
+func (t *terminal) events() <-chan terminalEvent {
+	events := make(chan terminalEvent, 1)
+	go func() {
+		for {
+			keys, err := t.readKeys()
+			events <- terminalEvent{keys: keys, err: err}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	t.resized = make(chan os.Signal, 1)
+	notifyResize(t.resized)
+	go func() {
+		for range t.resized {
+			events <- terminalEvent{}
+		}
+	}()
+	return events
+}
+
+// readKeys waits for input and returns the keys it contains. Printable characters are returned
+// as themselves; other keys by the names above.
+func (t *terminal) readKeys() ([]string, error) {
+	buf := make([]byte, 64)
+	n, err := t.in.Read(buf)
+	if err != nil {
+		return nil, err
+	}
+	return parseKeys(string(buf[:n])), nil
+}
+
+// parseKeys splits terminal input into keys
+func parseKeys(input string) []string {
+	var keys []string
+	for input != "" {
+		if input[0] == '\x1b' {
+			matched := false
+			for sequence, key := range escapeKeys {
+				if strings.HasPrefix(input, sequence) {
+					keys = append(keys, key)
+					input = input[len(sequence):]
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				// A lone escape, or a sequence we do not know, which is dropped as a whole
+				if len(input) == 1 || (input[1] != '[' && input[1] != 'O') {
+					keys = append(keys, keyEscape)
+					input = input[1:]
+				} else {
+					input = ""
+				}
+			}
+			continue
+		}
+
+		switch input[0] {
+		case 3:
+			keys = append(keys, keyCtrlC)
+		case 4:
+			keys = append(keys, keyCtrlD)
+		case 21:
+			keys = append(keys, keyCtrlU)
+		case '\r', '\n':
+			keys = append(keys, keyEnter)
+		case '\t':
+			keys = append(keys, keyTab)
+		case 8, 127:
+			keys = append(keys, keyBackspace)
+		default:
+			r, size := utf8.DecodeRuneInString(input)
+			if r >= ' ' {
+				keys = append(keys, string(r))
+			}
+			input = input[size:]
+			continue
+		}
+		input = input[1:]
+	}
+	return keys
+}
+
+// stty runs stty against the terminal on stdin and returns its output
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	output, err := cmd.Output()
+	return string(output), err
+}
@@ -0,0 +1,9 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package main
+
+import "os"
+
+// notifyResize does nothing where terminals do not signal resizes, so the browser adapts to a new
+// size at the next key press
+func notifyResize(c chan<- os.Signal) {}
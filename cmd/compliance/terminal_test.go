@@ -0,0 +1,32 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseKeys(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{"printable characters", "q/ac", []string{"q", "/", "a", "c"}},
+		{"multibyte character", "é", []string{"é"}},
+		{"cursor keys", "\x1b[A\x1b[B\x1bOC\x1bOD", []string{keyUp, keyDown, keyRight, keyLeft}},
+		{"paging keys", "\x1b[5~\x1b[6~\x1b[H\x1b[4~", []string{keyPageUp, keyPageDown, keyHome, keyEnd}},
+		{"control keys", "\x03\x04\x15\r\n\t\x7f\x08", []string{keyCtrlC, keyCtrlD, keyCtrlU, keyEnter, keyEnter, keyTab, keyBackspace, keyBackspace}},
+		{"back tab", "\x1b[Z", []string{keyBackTab}},
+		{"lone escape", "\x1b", []string{keyEscape}},
+		{"escape then a key", "\x1bq", []string{keyEscape, "q"}},
+		{"unknown sequence is dropped", "\x1b[99~", nil},
+		{"other control characters are dropped", "\x00a\x1f", []string{"a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseKeys(tt.input); !slices.Equal(got, tt.want) {
+				t.Errorf("parseKeys(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
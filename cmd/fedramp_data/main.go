@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
 	"github.com/grafana/hackathon-12-mcp-compliance/internal/services/fedramp_data"
 )
 
@@ -16,20 +17,29 @@ func main() {
 	outputFile := flag.String("output", "", "Path to the output JSON file")
 	programName := flag.String("program", "FedRAMP High", "Program name (e.g., FedRAMP High, FedRAMP Moderate)")
 	searchQuery := flag.String("search", "", "Search for controls by keyword (optional)")
+	controlID := flag.String("control", "", "Show a control by ID, e.g. AC-2 or AC-2(1) (optional)")
+	formatName := flag.String("format", "text", "Format for search results and controls (text or markdown)")
 	flag.Parse()
 
 	// Validate flags
 	if *inputFile == "" {
-		fmt.Println("Usage: fedramp-data -input <input-file> -output <output-file> [-program <program-name>] [-search <keyword>]")
+		fmt.Println("Usage: fedramp-data -input <input-file> -output <output-file> [-program <program-name>] [-search <keyword>] [-control <control-id>] [-format text|markdown]")
 		flag.PrintDefaults()
 		os.Exit(1)
 	}
 
-	// If we're just searching, we don't need an output file
-	if *searchQuery != "" && (*outputFile == "" || *outputFile == "/dev/null") {
-		// This is fine, we're just searching
+	format, err := fedramp.ParseFormat(*formatName)
+	if err != nil || format == fedramp.FormatJSON {
+		fmt.Printf("Error: unsupported format %q (expected text or markdown)\n", *formatName)
+		os.Exit(1)
+	}
+
+	// If we're just searching or showing a control, we don't need an output file
+	querying := *searchQuery != "" || *controlID != ""
+	if querying && (*outputFile == "" || *outputFile == "/dev/null") {
+		// This is fine, we're just querying
 	} else if *outputFile == "" {
-		fmt.Println("Error: Output file is required unless searching with -search flag")
+		fmt.Println("Error: Output file is required unless searching with -search or showing a control with -control")
 		flag.PrintDefaults()
 		os.Exit(1)
 	}
@@ -62,9 +72,20 @@ func main() {
 		fmt.Printf("Searching for controls matching '%s'...\n", *searchQuery)
 		results := service.SearchControls(programData, *searchQuery)
 		fmt.Printf("Found %d matching controls\n", len(results))
-		for _, control := range results {
-			fmt.Printf("- %s: %s\n", control.ID, control.Title)
+		document := fedramp.NewDocument(format)
+		document.Controls(results, 2, []string{"id", "title"})
+		fmt.Print(document.String())
+	}
+
+	// If a control ID is provided, show the control
+	if *controlID != "" {
+		control, found := service.GetControl(programData, *controlID)
+		if !found {
+			log.Fatalf("Control not found: %s", *controlID)
 		}
+		document := fedramp.NewDocument(format)
+		document.Control(control, 1, nil)
+		fmt.Print(document.String())
 	}
 
 	// Write the output if an output file is specified and it's not /dev/null
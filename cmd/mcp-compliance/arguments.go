@@ -0,0 +1,200 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// toolHandler returns a tool handler that validates the call's arguments against the tool's input
+// schema and binds them to a struct of type T before calling handle. Errors from binding or from
+// handle are reported to the client as tool errors by toolErrorResult, so handlers never panic on
+// missing or mistyped arguments.
+func toolHandler[T any](tool mcp.Tool, handle func(ctx context.Context, args T) (*mcp.CallToolResult, error)) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args T
+		if err := bindArguments(tool, request, &args); err != nil {
+			return toolErrorResult(err), nil
+		}
+
+		result, err := handle(ctx, args)
+		if err != nil {
+			return toolErrorResult(err), nil
+		}
+		return result, nil
+	}
+}
+
+// bindArguments validates the arguments of a tool call against the tool's input schema and
+// unmarshals them into target, which should be a pointer to a struct with json tags
+func bindArguments(tool mcp.Tool, request mcp.CallToolRequest, target any) error {
+	arguments := request.GetArguments()
+	if arguments == nil && request.GetRawArguments() != nil {
+		return fedramp.NewError(fedramp.ErrInvalidArgument, "arguments must be an object")
+	}
+
+	for _, name := range tool.InputSchema.Required {
+		if value, ok := arguments[name]; !ok || value == nil {
+			return fedramp.NewError(fedramp.ErrInvalidArgument, "missing required argument %q", name)
+		}
+	}
+
+	for name, value := range arguments {
+		property, ok := tool.InputSchema.Properties[name].(map[string]any)
+		if !ok {
+			return fedramp.NewError(fedramp.ErrInvalidArgument, "unknown argument %q", name).
+				WithSuggestions(fedramp.ClosestMatches(name, argumentNames(tool))...)
+		}
+		if value == nil {
+			continue
+		}
+		if err := validateArgument(name, value, property); err != nil {
+			return err
+		}
+	}
+
+	data, err := json.Marshal(arguments)
+	if err != nil {
+		return fedramp.WrapError(fedramp.ErrInternal, err, "failed to encode arguments")
+	}
+	if err := json.Unmarshal(data, target); err != nil {
+		return fedramp.WrapError(fedramp.ErrInvalidArgument, err, "invalid arguments")
+	}
+	return nil
+}
+
+// validateArgument checks a single argument against its JSON schema property
+func validateArgument(name string, value any, property map[string]any) error {
+	switch property["type"] {
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return fedramp.NewError(fedramp.ErrInvalidArgument, "argument %q must be a string, got %s", name, jsonType(value))
+		}
+		if enum, ok := property["enum"].([]string); ok && !slices.ContainsFunc(enum, func(e string) bool { return strings.EqualFold(e, s) }) {
+			return fedramp.NewError(fedramp.ErrInvalidArgument, "argument %q must be one of %s, got %q", name, strings.Join(enum, ", "), s).
+				WithSuggestions(fedramp.ClosestMatches(s, enum)...)
+		}
+	case "number", "integer":
+		n, ok := value.(float64)
+		if !ok {
+			return fedramp.NewError(fedramp.ErrInvalidArgument, "argument %q must be a number, got %s", name, jsonType(value))
+		}
+		if property["type"] == "integer" && n != math.Trunc(n) {
+			return fedramp.NewError(fedramp.ErrInvalidArgument, "argument %q must be a whole number, got %v", name, n)
+		}
+		if minimum, ok := property["minimum"].(float64); ok && n < minimum {
+			return fedramp.NewError(fedramp.ErrInvalidArgument, "argument %q must be at least %v, got %v", name, minimum, n)
+		}
+		if maximum, ok := property["maximum"].(float64); ok && n > maximum {
+			return fedramp.NewError(fedramp.ErrInvalidArgument, "argument %q must be at most %v, got %v", name, maximum, n)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fedramp.NewError(fedramp.ErrInvalidArgument, "argument %q must be a boolean, got %s", name, jsonType(value))
+		}
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			return fedramp.NewError(fedramp.ErrInvalidArgument, "argument %q must be an array, got %s", name, jsonType(value))
+		}
+		if minItems, ok := property["minItems"].(int); ok && len(items) < minItems {
+			return fedramp.NewError(fedramp.ErrInvalidArgument, "argument %q must have at least %d items, got %d", name, minItems, len(items))
+		}
+		if maxItems, ok := property["maxItems"].(int); ok && len(items) > maxItems {
+			return fedramp.NewError(fedramp.ErrInvalidArgument, "argument %q must have at most %d items, got %d", name, maxItems, len(items))
+		}
+		if itemProperty, ok := property["items"].(map[string]any); ok {
+			for i, item := range items {
+				if err := validateArgument(fmt.Sprintf("%s[%d]", name, i), item, itemProperty); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// argumentNames returns the names of the arguments a tool accepts
+func argumentNames(tool mcp.Tool) []string {
+	names := make([]string, 0, len(tool.InputSchema.Properties))
+	for name := range tool.InputSchema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// jsonType returns the JSON type name of a decoded JSON value
+func jsonType(value any) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// paginationArguments are the optional pagination arguments of list tools
+type paginationArguments struct {
+	Cursor   string `json:"cursor"`
+	PageSize int    `json:"pageSize"`
+}
+
+// pageRequest returns the page request described by the arguments
+func (a paginationArguments) pageRequest() (fedramp.PageRequest, error) {
+	// Validate the cursor up front so that a bad cursor is reported before any work is done
+	if _, err := fedramp.DecodeCursor(a.Cursor); err != nil {
+		return fedramp.PageRequest{}, err
+	}
+	return fedramp.PageRequest{Cursor: a.Cursor, PageSize: a.PageSize}, nil
+}
+
+// fieldsArguments is the optional field projection argument of tools that return controls
+type fieldsArguments struct {
+	Fields string `json:"fields"`
+}
+
+// fields returns the requested control fields, falling back to defaults
+func (a fieldsArguments) fields(defaults []string) ([]string, error) {
+	if strings.TrimSpace(a.Fields) == "" {
+		return defaults, nil
+	}
+	return fedramp.ParseFields(a.Fields)
+}
+
+// formatArguments is the optional response format argument of every tool
+type formatArguments struct {
+	Format string `json:"format"`
+}
+
+// format returns the requested response format, defaulting to JSON
+func (a formatArguments) format() (fedramp.Format, error) {
+	return fedramp.ParseFormat(a.Format)
+}
+
+// withdrawnArguments is the optional argument of listing tools that returns withdrawn controls
+type withdrawnArguments struct {
+	IncludeWithdrawn bool `json:"includeWithdrawn"`
+}
+
+// queryOptions returns the service query options described by the arguments
+func (a withdrawnArguments) queryOptions() []fedramp.QueryOption {
+	return []fedramp.QueryOption{fedramp.IncludeWithdrawn(a.IncludeWithdrawn)}
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestBindArgumentsPagination(t *testing.T) {
+	tool := mcp.NewTool("list_things", withPagination())
+
+	tests := []struct {
+		name      string
+		arguments map[string]any
+		want      int // page size, or 0 if the arguments are rejected
+	}{
+		{"whole number", map[string]any{"pageSize": 10.0}, 10},
+		{"maximum", map[string]any{"pageSize": float64(fedramp.MaxPageSize)}, fedramp.MaxPageSize},
+		{"fraction", map[string]any{"pageSize": 2.5}, 0},
+		{"zero", map[string]any{"pageSize": 0.0}, 0},
+		{"above the maximum", map[string]any{"pageSize": float64(fedramp.MaxPageSize + 1)}, 0},
+		{"string", map[string]any{"pageSize": "10"}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var args paginationArguments
+			err := bindArguments(tool, mcp.CallToolRequest{Params: mcp.CallToolParams{Name: tool.Name, Arguments: tt.arguments}}, &args)
+			if tt.want == 0 {
+				if fedramp.KindOf(err) != fedramp.ErrInvalidArgument {
+					t.Errorf("bindArguments(%v) error = %v, want an invalid argument", tt.arguments, err)
+				}
+				return
+			}
+			if err != nil || args.PageSize != tt.want {
+				t.Errorf("bindArguments(%v) = %d, %v, want %d", tt.arguments, args.PageSize, err, tt.want)
+			}
+		})
+	}
+}
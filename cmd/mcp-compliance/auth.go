@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"maps"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/adapters"
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/auth"
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/ports"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// authConfig configures authentication and authorization for the HTTP transport
+type authConfig struct {
+	mode        string
+	tokensFile  string
+	jwksFile    string
+	issuer      string
+	audience    string
+	groupsClaim string
+	policyFile  string
+}
+
+// enabled reports whether any authentication or authorization has been configured
+func (c authConfig) enabled() bool {
+	return c.mode != "none" || c.policyFile != ""
+}
+
+// newAuthenticator creates the authenticator selected by the configuration, or nil if authentication is disabled
+func newAuthenticator(config authConfig) (ports.Authenticator, error) {
+	switch config.mode {
+	case "none":
+		return nil, nil
+	case "token":
+		if config.tokensFile == "" {
+			return nil, fmt.Errorf("-auth-tokens-file is required for token authentication")
+		}
+		return adapters.NewStaticTokenAuthenticator(config.tokensFile)
+	case "jwt":
+		if config.jwksFile == "" {
+			return nil, fmt.Errorf("-auth-jwks-file is required for JWT authentication")
+		}
+		return adapters.NewJWTAuthenticator(adapters.JWTConfig{
+			JWKSFile:    config.jwksFile,
+			Issuer:      config.issuer,
+			Audience:    config.audience,
+			GroupsClaim: config.groupsClaim,
+			Leeway:      time.Minute,
+		})
+	default:
+		return nil, fmt.Errorf("unknown authentication mode %q (expected none, token or jwt)", config.mode)
+	}
+}
+
+// loadPolicy loads the authorization policy, or returns nil if no policy file is configured
+func loadPolicy(config authConfig) (*auth.Policy, error) {
+	if config.policyFile == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(config.policyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy file: %v", err)
+	}
+	policy, err := auth.ParsePolicy(data)
+	if err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// requireBearerToken wraps an HTTP handler so that requests must carry a valid bearer token.
+// The authenticated identity is added to the request context, from where it reaches tool,
+// resource and prompt handlers and the compliance service.
+func requireBearerToken(authenticator ports.Authenticator, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || strings.TrimSpace(token) == "" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="mcp-compliance"`)
+			http.Error(w, "missing bearer token", http.StatusUnauthorized)
+			return
+		}
+
+		identity, err := authenticator.Authenticate(r.Context(), strings.TrimSpace(token))
+		if err != nil {
+			log.Printf("Rejected request to %s: %v", r.URL.Path, err)
+			w.Header().Set("WWW-Authenticate", `Bearer realm="mcp-compliance", error="invalid_token"`)
+			http.Error(w, "invalid bearer token", http.StatusUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(auth.WithIdentity(r.Context(), identity)))
+	})
+}
+
+// authorizeTools returns a tool handler middleware that checks the caller may use a tool on
+// every program its arguments name before the tool runs
+func authorizeTools(policy *auth.Policy) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if policy == nil {
+				return next(ctx, request)
+			}
+
+			var programs []string
+			for _, name := range programArgumentNames {
+				if program, _ := request.GetArguments()[name].(string); strings.TrimSpace(program) != "" {
+					programs = append(programs, strings.TrimSpace(program))
+				}
+			}
+			// A call without a program is authorized if the tool is granted on any program
+			if len(programs) == 0 {
+				programs = []string{""}
+			}
+			for _, program := range programs {
+				if err := policy.Authorize(ctx, request.Params.Name, program); err != nil {
+					return toolErrorResult(err), nil
+				}
+			}
+			return next(ctx, request)
+		}
+	}
+}
+
+// filterTools returns a tool filter that only lists the tools the caller may use on at least one
+// program, offering as the choices of their program arguments only the programs the caller may
+// use them on
+func filterTools(policy *auth.Policy) server.ToolFilterFunc {
+	return func(ctx context.Context, tools []mcp.Tool) []mcp.Tool {
+		if policy == nil {
+			return tools
+		}
+		allowed := make([]mcp.Tool, 0, len(tools))
+		for _, tool := range tools {
+			if err := policy.Authorize(ctx, tool.Name, ""); err == nil {
+				allowed = append(allowed, authorizedProgramChoices(ctx, policy, tool))
+			}
+		}
+		return allowed
+	}
+}
+
+// authorizedProgramChoices returns a copy of a tool whose program arguments only offer the programs
+// the caller may use the tool on. The tool registered with the server is left unchanged.
+func authorizedProgramChoices(ctx context.Context, policy *auth.Policy, tool mcp.Tool) mcp.Tool {
+	properties := tool.InputSchema.Properties
+	cloned := false
+	for _, name := range programArgumentNames {
+		property, ok := properties[name].(map[string]any)
+		if !ok {
+			continue
+		}
+		programs, ok := property["enum"].([]string)
+		if !ok {
+			continue
+		}
+		allowed := slices.DeleteFunc(slices.Clone(programs), func(program string) bool {
+			return policy.Authorize(ctx, tool.Name, program) != nil
+		})
+		if len(allowed) == len(programs) {
+			continue
+		}
+		if !cloned {
+			properties, cloned = maps.Clone(properties), true
+		}
+		property = maps.Clone(property)
+		property["enum"] = allowed
+		properties[name] = property
+	}
+	tool.InputSchema.Properties = properties
+	return tool
+}
+
+// filterResources returns a hook that removes the resources of the programs the caller may not
+// read from resource listings. A filtered page may be shorter than the page size, but its cursor
+// still continues the listing.
+func filterResources(policy *auth.Policy, resources *complianceResources) server.OnAfterListResourcesFunc {
+	return func(ctx context.Context, _ any, _ *mcp.ListResourcesRequest, result *mcp.ListResourcesResult) {
+		if policy == nil {
+			return
+		}
+		result.Resources = slices.DeleteFunc(result.Resources, func(resource mcp.Resource) bool {
+			program, ok := resources.programOf(resource.URI)
+			return ok && policy.AuthorizeProgram(ctx, program) != nil
+		})
+	}
+}
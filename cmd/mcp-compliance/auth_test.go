@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/auth"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// testPolicy lets alice read FedRAMP Moderate with every tool and use get_control on FedRAMP High
+var testPolicy = &auth.Policy{Rules: []auth.Rule{
+	{Subjects: []string{"alice"}, Tools: []string{auth.Wildcard}, Programs: []string{"FedRAMP Moderate"}},
+	{Subjects: []string{"alice"}, Tools: []string{"get_control"}, Programs: []string{"FedRAMP High"}},
+}}
+
+func TestAuthorizeTools(t *testing.T) {
+	next := func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultText("ok"), nil
+	}
+	handler := authorizeTools(testPolicy)(next)
+	ctx := auth.WithIdentity(context.Background(), auth.Identity{Subject: "alice"})
+
+	tests := []struct {
+		tool      string
+		arguments map[string]any
+		allowed   bool
+	}{
+		{"get_control", map[string]any{"program": "FedRAMP High"}, true},
+		{"get_control_family", map[string]any{"program": "FedRAMP High"}, false},
+		{"get_control_family", map[string]any{"program": "FedRAMP Moderate"}, true},
+		// Every argument that names a program is authorized
+		{"save_overlay", map[string]any{"base": "FedRAMP Moderate"}, true},
+		{"save_overlay", map[string]any{"base": "FedRAMP High"}, false},
+		{"save_overlay", map[string]any{"base": "FedRAMP Moderate", "addFrom": "FedRAMP High"}, false},
+		// A call without a program needs the tool on any program
+		{"list_compliance_programs", nil, true},
+	}
+	for _, tt := range tests {
+		request := mcp.CallToolRequest{}
+		request.Params.Name = tt.tool
+		request.Params.Arguments = tt.arguments
+		result, err := handler(ctx, request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.IsError == tt.allowed {
+			t.Errorf("%s(%v) error = %v, want allowed %v", tt.tool, tt.arguments, result.IsError, tt.allowed)
+		}
+	}
+
+	// Unauthenticated callers are rejected before any program is checked
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "get_control"
+	if result, _ := handler(context.Background(), request); !result.IsError {
+		t.Error("unauthenticated call was allowed")
+	}
+}
+
+func TestFilterToolsProgramChoices(t *testing.T) {
+	programs := []string{"FedRAMP High", "FedRAMP Moderate", "Acme Tailored"}
+	tool := func(name string) mcp.Tool {
+		return mcp.NewTool(name, mcp.WithString("program", mcp.Enum(programs...)), mcp.WithString("base", mcp.Enum(programs...)))
+	}
+	ctx := auth.WithIdentity(context.Background(), auth.Identity{Subject: "alice"})
+
+	tools := filterTools(testPolicy)(ctx, []mcp.Tool{tool("get_control"), tool("list_controls")})
+	want := map[string][]string{
+		"get_control":   {"FedRAMP High", "FedRAMP Moderate"},
+		"list_controls": {"FedRAMP Moderate"},
+	}
+	for _, tool := range tools {
+		for _, name := range []string{"program", "base"} {
+			if got := tool.InputSchema.Properties[name].(map[string]any)["enum"]; !slices.Equal(got.([]string), want[tool.Name]) {
+				t.Errorf("%s %s choices = %v, want %v", tool.Name, name, got, want[tool.Name])
+			}
+		}
+	}
+
+	// The registered tools keep every program
+	registered := tool("list_controls")
+	filterTools(testPolicy)(ctx, []mcp.Tool{registered})
+	if got := registered.InputSchema.Properties["program"].(map[string]any)["enum"]; !slices.Equal(got.([]string), programs) {
+		t.Errorf("registered choices = %v, want %v", got, programs)
+	}
+}
+
+func TestFilterResources(t *testing.T) {
+	resources := &complianceResources{programs: map[string]string{
+		"fedramp-high":     "FedRAMP High",
+		"fedramp-moderate": "FedRAMP Moderate",
+	}}
+	result := &mcp.ListResourcesResult{Resources: []mcp.Resource{
+		{URI: resourceScheme + "programs"},
+		{URI: familiesURI("FedRAMP High")},
+		{URI: controlURI("FedRAMP High", "ac-2")},
+		{URI: familiesURI("FedRAMP Moderate")},
+		{URI: controlURI("FedRAMP Moderate", "ac-2")},
+	}}
+	ctx := auth.WithIdentity(context.Background(), auth.Identity{Subject: "bob"})
+	bobPolicy := &auth.Policy{Rules: []auth.Rule{{Subjects: []string{"bob"}, Tools: []string{"get_control"}, Programs: []string{"FedRAMP Moderate"}}}}
+
+	filterResources(bobPolicy, resources)(ctx, nil, nil, result)
+	var got []string
+	for _, resource := range result.Resources {
+		got = append(got, resource.URI)
+	}
+	want := []string{"compliance://programs", "compliance://fedramp-moderate/families", "compliance://fedramp-moderate/controls/ac-2"}
+	if !slices.Equal(got, want) {
+		t.Errorf("resources = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/auth"
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/services/fedramp_compliance"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// maxCompletionValues is the largest number of values a completion response may contain
+const maxCompletionValues = 100
+
+// complianceCompletions completes program, family and control ID arguments of prompts and
+// resource templates from the programs the caller can access
+type complianceCompletions struct {
+	service *fedramp_compliance.Service
+}
+
+// CompletePromptArgument provides completions for a prompt argument
+func (c *complianceCompletions) CompletePromptArgument(ctx context.Context, promptName string, argument mcp.CompleteArgument, context mcp.CompleteContext) (*mcp.Completion, error) {
+	program := context.Arguments["program"]
+
+	var candidates []string
+	var err error
+	switch argument.Name {
+	case "program":
+		candidates, err = c.service.ListCompliancePrograms(ctx)
+	case "family":
+		candidates, err = c.familyIDs(ctx, program)
+	case "controlId":
+		candidates, err = c.controlIDs(ctx, program)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return completion(argument.Value, candidates), nil
+}
+
+// CompleteResourceArgument provides completions for a resource template argument
+func (c *complianceCompletions) CompleteResourceArgument(ctx context.Context, uri string, argument mcp.CompleteArgument, context mcp.CompleteContext) (*mcp.Completion, error) {
+	// Resource URIs identify programs by slug rather than by name
+	var program string
+	if slug := context.Arguments["program"]; slug != "" {
+		resolved, err := resolveProgramSlug(ctx, c.service, slug)
+		if err != nil {
+			return completion("", nil), nil
+		}
+		program = resolved
+	}
+
+	var candidates []string
+	var err error
+	switch argument.Name {
+	case "program":
+		var programs []string
+		programs, err = c.service.ListCompliancePrograms(ctx)
+		for _, p := range programs {
+			candidates = append(candidates, programSlug(p))
+		}
+	case "family":
+		candidates, err = c.familyIDs(ctx, program)
+	case "control":
+		candidates, err = c.controlIDs(ctx, program)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return completion(argument.Value, candidates), nil
+}
+
+// familyIDs returns the IDs of the control families in a program, or in every program if none is given
+func (c *complianceCompletions) familyIDs(ctx context.Context, program string) ([]string, error) {
+	var ids []string
+	seen := make(map[string]bool)
+	err := c.eachFamily(ctx, program, func(family fedramp.ControlFamily) {
+		if !seen[family.ID] {
+			seen[family.ID] = true
+			ids = append(ids, family.ID)
+		}
+	})
+	return ids, err
+}
+
+// controlIDs returns the IDs of the controls in a program, or in every program if none is given
+func (c *complianceCompletions) controlIDs(ctx context.Context, program string) ([]string, error) {
+	var ids []string
+	seen := make(map[string]bool)
+	err := c.eachFamily(ctx, program, func(family fedramp.ControlFamily) {
+		for _, control := range family.Controls {
+			if !seen[control.ID] {
+				seen[control.ID] = true
+				ids = append(ids, control.ID)
+			}
+		}
+	})
+	return ids, err
+}
+
+// eachFamily calls fn for every control family in a program, or in every program if none is given.
+// An unknown or inaccessible program yields no families, so that completing against a half-typed
+// program name returns no values instead of an error.
+func (c *complianceCompletions) eachFamily(ctx context.Context, program string, fn func(fedramp.ControlFamily)) error {
+	programs := []string{program}
+	if program == "" {
+		var err error
+		if programs, err = c.service.ListCompliancePrograms(ctx); err != nil {
+			return err
+		}
+	}
+
+	for _, p := range programs {
+		families, err := c.service.ListControlFamilies(ctx, p)
+		if err != nil {
+			if fedramp.KindOf(err) == fedramp.ErrProgramUnavailable || errors.Is(err, auth.ErrPermissionDenied) {
+				continue
+			}
+			return err
+		}
+		for _, family := range families {
+			fn(family)
+		}
+	}
+	return nil
+}
+
+// completion ranks the candidates that match a partial value and returns at most the number of
+// values allowed in a completion response
+func completion(value string, candidates []string) *mcp.Completion {
+	matches := fedramp.MatchCandidates(value, candidates)
+	result := &mcp.Completion{
+		Values: matches[:min(len(matches), maxCompletionValues)],
+		Total:  len(matches),
+	}
+	result.HasMore = len(matches) > maxCompletionValues
+	return result
+}
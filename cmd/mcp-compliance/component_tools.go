@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/services/fedramp_implementation"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// addComponentTools adds the tools that record which components implement controls
+func addComponentTools(s *server.MCPServer, service *fedramp_implementation.Service) {
+	// Tool: set_component
+	setComponentTool := mcp.NewTool("set_component",
+		mcp.WithDescription("Create or update a component that implements controls, such as a product, service or identity provider (e.g., Grafana, Loki or Kubernetes). Arguments that are left out keep their recorded values."),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("The name of the component"),
+		),
+		mcp.WithString("type",
+			mcp.Description("The OSCAL type of the component, required for a new component"),
+			mcp.Enum(fedramp.ComponentTypes...),
+		),
+		mcp.WithString("description",
+			mcp.Description("What the component is and does"),
+		),
+		withFormat(),
+	)
+	s.AddTool(setComponentTool, toolHandler(setComponentTool, func(ctx context.Context, args struct {
+		Name        string  `json:"name"`
+		Type        string  `json:"type"`
+		Description *string `json:"description"`
+		formatArguments
+	}) (*mcp.CallToolResult, error) {
+		format, err := args.format()
+		if err != nil {
+			return nil, err
+		}
+
+		component, err := service.SetComponent(ctx, args.Name, fedramp.ComponentUpdate{Type: args.Type, Description: args.Description})
+		if err != nil {
+			return nil, err
+		}
+
+		return formattedResult(format, component, func(d *fedramp.Document) {
+			d.Component(component)
+		})
+	}))
+
+	// Tool: map_component
+	mapComponentTool := mcp.NewTool("map_component",
+		mcp.WithDescription("Map a component to a control it implements, with how it implements the control and the statements it satisfies. Replaces the component's earlier mapping to the control. In an SSP, records that name the component use its narratives."),
+		mcp.WithString("component",
+			mcp.Required(),
+			mcp.Description("The name of the component"),
+		),
+		mcp.WithString("program",
+			mcp.Required(),
+			mcp.Description("The compliance program (e.g., FedRAMP High, FedRAMP Moderate or a tailored program)"),
+		),
+		mcp.WithString("controlId",
+			mcp.Required(),
+			mcp.Description("The ID of the control (e.g., AC-1, IA-2 or AC-2(1))"),
+		),
+		mcp.WithString("narrative",
+			mcp.Description("How the component implements the control"),
+		),
+		mcp.WithObject("statementNarratives",
+			mcp.Description("The statements the component satisfies, with how it satisfies each (e.g., {\"a\": \"...\", \"ac-2_smt.b\": \"\"}); an empty narrative uses the control narrative. Without statements, the component satisfies the whole control."),
+			mcp.AdditionalProperties(map[string]any{"type": "string"}),
+		),
+		mcp.WithBoolean("remove",
+			mcp.Description("Remove the component's mapping to the control instead"),
+		),
+		withFormat(),
+	)
+	s.AddTool(mapComponentTool, toolHandler(mapComponentTool, func(ctx context.Context, args struct {
+		Component string `json:"component"`
+		controlArguments
+		Narrative           string            `json:"narrative"`
+		StatementNarratives map[string]string `json:"statementNarratives"`
+		Remove              bool              `json:"remove"`
+		formatArguments
+	}) (*mcp.CallToolResult, error) {
+		format, err := args.format()
+		if err != nil {
+			return nil, err
+		}
+
+		component, err := service.MapComponent(ctx, args.Component, args.Program, args.ControlID, args.Narrative, args.StatementNarratives, args.Remove)
+		if err != nil {
+			return nil, err
+		}
+
+		return formattedResult(format, component, func(d *fedramp.Document) {
+			d.Component(component)
+		})
+	}))
+
+	// Tool: get_components_for_control
+	componentsForControlTool := mcp.NewTool("get_components_for_control",
+		mcp.WithDescription("List the components that implement a control, with each component's narrative and the statements it satisfies"),
+		mcp.WithString("program",
+			mcp.Required(),
+			mcp.Description("The compliance program (e.g., FedRAMP High, FedRAMP Moderate or a tailored program)"),
+		),
+		mcp.WithString("controlId",
+			mcp.Required(),
+			mcp.Description("The ID of the control (e.g., AC-1, IA-2 or AC-2(1))"),
+		),
+		withFormat(),
+	)
+	s.AddTool(componentsForControlTool, toolHandler(componentsForControlTool, func(ctx context.Context, args struct {
+		controlArguments
+		formatArguments
+	}) (*mcp.CallToolResult, error) {
+		format, err := args.format()
+		if err != nil {
+			return nil, err
+		}
+
+		mappings, err := service.GetComponentsForControl(ctx, args.Program, args.ControlID)
+		if err != nil {
+			return nil, err
+		}
+
+		return formattedResult(format, mappings, func(d *fedramp.Document) {
+			d.ComponentMappings(args.ControlID, mappings)
+		})
+	}))
+
+	// Tool: get_controls_for_component
+	controlsForComponentTool := mcp.NewTool("get_controls_for_component",
+		mcp.WithDescription("Get a component with the controls it implements, its narrative for each and the statements it satisfies"),
+		mcp.WithString("component",
+			mcp.Required(),
+			mcp.Description("The name of the component"),
+		),
+		mcp.WithString("program",
+			mcp.Description("Only list the controls of this program"),
+		),
+		withFormat(),
+	)
+	s.AddTool(controlsForComponentTool, toolHandler(controlsForComponentTool, func(ctx context.Context, args struct {
+		Component string `json:"component"`
+		programArguments
+		formatArguments
+	}) (*mcp.CallToolResult, error) {
+		format, err := args.format()
+		if err != nil {
+			return nil, err
+		}
+
+		component, err := service.GetControlsForComponent(ctx, args.Component, args.Program)
+		if err != nil {
+			return nil, err
+		}
+
+		return formattedResult(format, component, func(d *fedramp.Document) {
+			d.Component(component)
+		})
+	}))
+
+	// Tool: import_component_definition
+	importComponentsTool := mcp.NewTool("import_component_definition",
+		mcp.WithDescription("Import the components of an OSCAL component definition, with the controls and statements each implements. Existing components keep their mappings to other controls."),
+		mcp.WithString("definition",
+			mcp.Required(),
+			mcp.Description("The OSCAL component definition in JSON"),
+		),
+		mcp.WithString("program",
+			mcp.Description("The program of control implementations whose source is not a FedRAMP profile"),
+		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description("Only report what would be imported, without changing any components"),
+		),
+		withFormat(),
+	)
+	s.AddTool(importComponentsTool, toolHandler(importComponentsTool, func(ctx context.Context, args struct {
+		Definition string `json:"definition"`
+		programArguments
+		DryRun bool `json:"dryRun"`
+		formatArguments
+	}) (*mcp.CallToolResult, error) {
+		format, err := args.format()
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := service.ImportComponentDefinition(ctx, []byte(args.Definition), args.Program, args.DryRun)
+		if err != nil {
+			return nil, err
+		}
+
+		return formattedResult(format, result, func(d *fedramp.Document) {
+			d.ComponentImport(result)
+		})
+	}))
+
+	// Tool: export_component_definition
+	exportComponentsTool := mcp.NewTool("export_component_definition",
+		mcp.WithDescription("Export components as an OSCAL component definition, with the controls and statements each implements. The json format returns the OSCAL document; markdown and text return a summary."),
+		mcp.WithArray("components",
+			mcp.Description("The names of the components to export; every component if left out"),
+			mcp.WithStringItems(),
+		),
+		mcp.WithString("program",
+			mcp.Description("Only include the controls of this program"),
+		),
+		withFormat(),
+	)
+	s.AddTool(exportComponentsTool, toolHandler(exportComponentsTool, func(ctx context.Context, args struct {
+		Components []string `json:"components"`
+		programArguments
+		formatArguments
+	}) (*mcp.CallToolResult, error) {
+		format, err := args.format()
+		if err != nil {
+			return nil, err
+		}
+
+		definition, err := service.ExportComponentDefinition(ctx, args.Components, args.Program)
+		if err != nil {
+			return nil, err
+		}
+
+		return formattedResult(format, definition, func(d *fedramp.Document) {
+			d.ComponentDefinitionSummary(definition)
+		})
+	}))
+}
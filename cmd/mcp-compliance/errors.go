@@ -0,0 +1,35 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/auth"
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// toolErrorResult converts an error into a tool error result. The message starts with a label for
+// the kind of error so that agents can tell a mistake they can correct, such as a mistyped control
+// ID, from a failure they cannot. Internal errors are logged and reported without their details.
+func toolErrorResult(err error) *mcp.CallToolResult {
+	switch {
+	case errors.Is(err, auth.ErrUnauthenticated):
+		return mcp.NewToolResultError("Authentication required")
+	case errors.Is(err, auth.ErrPermissionDenied):
+		return mcp.NewToolResultError("Permission denied: " + strings.TrimPrefix(err.Error(), auth.ErrPermissionDenied.Error()+": "))
+	}
+
+	switch fedramp.KindOf(err) {
+	case fedramp.ErrNotFound:
+		return mcp.NewToolResultError("Not found: " + err.Error())
+	case fedramp.ErrInvalidArgument:
+		return mcp.NewToolResultError("Invalid argument: " + err.Error())
+	case fedramp.ErrProgramUnavailable:
+		return mcp.NewToolResultError("Program unavailable: " + err.Error() + ". Use list_compliance_programs to see the available programs.")
+	default:
+		log.Printf("Internal error: %v", err)
+		return mcp.NewToolResultError("Internal error: the request could not be completed, please try again later")
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/ports"
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/services/fedramp_compliance"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// httpConfig configures the HTTP transport
+type httpConfig struct {
+	addr            string
+	baseURL         string
+	shutdownTimeout time.Duration
+	authenticator   ports.Authenticator
+}
+
+// serveHTTP serves the MCP server over HTTP until the context is cancelled, then shuts down gracefully.
+// It serves the streamable HTTP transport on /mcp, the SSE transport on /sse and /message, and
+// liveness and readiness probes on /healthz and /readyz. If an authenticator is configured, the
+// MCP endpoints require a bearer token; the probes never do.
+func serveHTTP(ctx context.Context, s *server.MCPServer, catalog *fedramp_compliance.Service, config httpConfig) error {
+	mux := http.NewServeMux()
+	httpServer := &http.Server{
+		Addr:              config.addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+		IdleTimeout:       120 * time.Second,
+		// No write timeout: SSE streams stay open for the lifetime of a client session
+	}
+
+	sseOptions := []server.SSEOption{
+		server.WithHTTPServer(httpServer),
+		server.WithKeepAlive(true),
+	}
+	if config.baseURL != "" {
+		sseOptions = append(sseOptions, server.WithBaseURL(config.baseURL))
+	}
+	sseServer := server.NewSSEServer(s, sseOptions...)
+	streamableServer := server.NewStreamableHTTPServer(s, server.WithEndpointPath("/mcp"))
+
+	protect := func(handler http.Handler) http.Handler {
+		if config.authenticator == nil {
+			return handler
+		}
+		return requireBearerToken(config.authenticator, handler)
+	}
+
+	var ready atomic.Bool
+	mux.Handle("/mcp", protect(streamableServer))
+	mux.Handle("/sse", protect(sseServer))
+	mux.Handle("/message", protect(sseServer))
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "ok")
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		if !ready.Load() {
+			http.Error(w, "not ready", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintln(w, "ok")
+	})
+
+	// Only report ready once every program can be loaded
+	if err := checkPrograms(catalog); err != nil {
+		return err
+	}
+	ready.Store(true)
+
+	errChan := make(chan error, 1)
+	go func() {
+		log.Printf("Serving MCP over HTTP on %s (streamable HTTP: /mcp, SSE: /sse)", config.addr)
+		if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errChan <- err
+		}
+		close(errChan)
+	}()
+
+	select {
+	case err, ok := <-errChan:
+		if ok {
+			return fmt.Errorf("HTTP server error: %v", err)
+		}
+		return nil
+	case <-ctx.Done():
+	}
+
+	// Stop reporting ready so load balancers drain traffic before connections are closed
+	log.Println("Shutting down MCP Compliance Server...")
+	ready.Store(false)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.shutdownTimeout)
+	defer cancel()
+
+	if err := streamableServer.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Failed to shut down streamable HTTP transport: %v", err)
+	}
+	// Shutting down the SSE transport closes its sessions and then the HTTP server
+	if err := sseServer.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Graceful shutdown did not complete, closing connections: %v", err)
+		return httpServer.Close()
+	}
+	return nil
+}
+
+// checkPrograms verifies that every compliance program can be loaded
+func checkPrograms(catalog *fedramp_compliance.Service) error {
+	ctx := context.Background()
+	programs, err := catalog.ListCompliancePrograms(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list compliance programs: %v", err)
+	}
+	for _, program := range programs {
+		if _, err := catalog.ListControlFamilies(ctx, program); err != nil {
+			return fmt.Errorf("failed to load %s: %v", program, err)
+		}
+	}
+	return nil
+}
+
+// requestTimeout returns a tool handler middleware that gives tool calls a deadline. The handler
+// runs with the deadline in its context, which the services pass to the stores, so a call that runs
+// out of time fails at its next store operation and is reported as timed out. A call that finished
+// its work before noticing the deadline returns its result.
+func requestTimeout(timeout time.Duration) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if timeout <= 0 {
+				return next(ctx, request)
+			}
+
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			result, err := next(ctx, request)
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) && (err != nil || result == nil || result.IsError) {
+				return mcp.NewToolResultError(fmt.Sprintf("Tool %s timed out after %s", request.Params.Name, timeout)), nil
+			}
+			return result, err
+		}
+	}
+}
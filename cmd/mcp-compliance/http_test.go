@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestRequestTimeout(t *testing.T) {
+	const timeout = 10 * time.Millisecond
+	tests := []struct {
+		name        string
+		handler     func(ctx context.Context) *mcp.CallToolResult
+		wantTimeout bool
+	}{
+		{"within the deadline", func(ctx context.Context) *mcp.CallToolResult {
+			return mcp.NewToolResultText("done")
+		}, false},
+		{"failed at the deadline", func(ctx context.Context) *mcp.CallToolResult {
+			<-ctx.Done()
+			return toolErrorResult(ctx.Err())
+		}, true},
+		// A call that finished its work reports it, even if it noticed the deadline too late
+		{"finished after the deadline", func(ctx context.Context) *mcp.CallToolResult {
+			<-ctx.Done()
+			return mcp.NewToolResultText("done")
+		}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var deadline bool
+			handler := requestTimeout(timeout)(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				_, deadline = ctx.Deadline()
+				return tt.handler(ctx), nil
+			})
+			result, err := handler(context.Background(), mcp.CallToolRequest{Params: mcp.CallToolParams{Name: "get_gap_analysis"}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !deadline {
+				t.Error("the handler ran without a deadline")
+			}
+			text := result.Content[0].(mcp.TextContent).Text
+			if timedOut := strings.Contains(text, "timed out"); timedOut != tt.wantTimeout || result.IsError != tt.wantTimeout {
+				t.Errorf("result = %q (error %v), want a timeout %v", text, result.IsError, tt.wantTimeout)
+			}
+		})
+	}
+}
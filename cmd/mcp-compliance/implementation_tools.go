@@ -0,0 +1,705 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/services/fedramp_implementation"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// addImplementationTools adds the tools that record how a system implements controls
+func addImplementationTools(s *server.MCPServer, service *fedramp_implementation.Service, budget responseBudget) {
+	// Tool: get_implementation
+	getImplementationTool := mcp.NewTool("get_implementation",
+		mcp.WithDescription("Get how a system implements a control: status, responsible roles and narrative"),
+		withSystem(true),
+		mcp.WithString("program",
+			mcp.Required(),
+			mcp.Description("The compliance program (e.g., FedRAMP High, FedRAMP Moderate or a tailored program)"),
+		),
+		mcp.WithString("controlId",
+			mcp.Required(),
+			mcp.Description("The ID of the control (e.g., AC-1, IA-2 or AC-2(1))"),
+		),
+		withFormat(),
+	)
+	s.AddTool(getImplementationTool, toolHandler(getImplementationTool, func(ctx context.Context, args struct {
+		systemArguments
+		controlArguments
+		formatArguments
+	}) (*mcp.CallToolResult, error) {
+		format, err := args.format()
+		if err != nil {
+			return nil, err
+		}
+
+		record, err := service.GetImplementation(ctx, args.System, args.Program, args.ControlID)
+		if err != nil {
+			return nil, err
+		}
+
+		return formattedResult(format, record, func(d *fedramp.Document) {
+			implementationDocument(d, record)
+		})
+	}))
+
+	// Tool: set_implementation
+	setImplementationTool := mcp.NewTool("set_implementation",
+		mcp.WithDescription("Record how a system implements a control. Creates the record if needed; arguments that are left out keep their recorded values."),
+		withSystem(true),
+		mcp.WithString("program",
+			mcp.Required(),
+			mcp.Description("The compliance program (e.g., FedRAMP High, FedRAMP Moderate or a tailored program)"),
+		),
+		mcp.WithString("controlId",
+			mcp.Required(),
+			mcp.Description("The ID of the control (e.g., AC-1, IA-2 or AC-2(1))"),
+		),
+		mcp.WithString("status",
+			mcp.Description("Implementation status, required for a new record"),
+			mcp.Enum(fedramp.ImplementationStatuses...),
+		),
+		mcp.WithArray("responsibleRoles",
+			mcp.Description("Roles responsible for the control (e.g., System Owner, ISSO); replaces the recorded roles"),
+			mcp.WithStringItems(),
+		),
+		mcp.WithArray("components",
+			mcp.Description("Components that implement the control (e.g., Grafana, Kubernetes); replaces the recorded components. In an SSP, each is described by its own narrative for the control, where it has one."),
+			mcp.WithStringItems(),
+		),
+		mcp.WithString("narrative",
+			mcp.Description("How the system implements the control; replaces the recorded narrative"),
+		),
+		mcp.WithObject("statementNarratives",
+			mcp.Description("How the system implements individual statements, by statement (e.g., {\"a\": \"...\", \"ac-2_smt.b\": \"...\"}); merged into the recorded narratives, and an empty narrative removes one"),
+			mcp.AdditionalProperties(map[string]any{"type": "string"}),
+		),
+		withFormat(),
+	)
+	s.AddTool(setImplementationTool, toolHandler(setImplementationTool, func(ctx context.Context, args struct {
+		systemArguments
+		controlArguments
+		Status              string            `json:"status"`
+		ResponsibleRoles    []string          `json:"responsibleRoles"`
+		Components          []string          `json:"components"`
+		Narrative           *string           `json:"narrative"`
+		StatementNarratives map[string]string `json:"statementNarratives"`
+		formatArguments
+	}) (*mcp.CallToolResult, error) {
+		format, err := args.format()
+		if err != nil {
+			return nil, err
+		}
+		update := fedramp.ImplementationUpdate{
+			ResponsibleRoles: args.ResponsibleRoles,
+			Components:       args.Components,
+			Narrative:        args.Narrative,
+			Statements:       args.StatementNarratives,
+		}
+		if args.Status != "" {
+			if update.Status, err = fedramp.ParseImplementationStatus(args.Status); err != nil {
+				return nil, err
+			}
+		}
+
+		record, err := service.SetImplementation(ctx, args.System, args.Program, args.ControlID, update)
+		if err != nil {
+			return nil, err
+		}
+
+		return formattedResult(format, record, func(d *fedramp.Document) {
+			implementationDocument(d, record)
+		})
+	}))
+
+	// Tool: list_implementations
+	listImplementationsTool := mcp.NewTool("list_implementations",
+		mcp.WithDescription("List recorded control implementations, optionally filtered by system, program and status"),
+		withSystem(false),
+		mcp.WithString("program",
+			mcp.Description("The compliance program (e.g., FedRAMP High, FedRAMP Moderate or a tailored program)"),
+		),
+		mcp.WithString("status",
+			mcp.Description("Only list records with this implementation status"),
+			mcp.Enum(fedramp.ImplementationStatuses...),
+		),
+		withPagination(),
+		withFormat(),
+	)
+	s.AddTool(listImplementationsTool, toolHandler(listImplementationsTool, func(ctx context.Context, args struct {
+		systemArguments
+		programArguments
+		Status string `json:"status"`
+		paginationArguments
+		formatArguments
+	}) (*mcp.CallToolResult, error) {
+		pageRequest, err := args.pageRequest()
+		if err != nil {
+			return nil, err
+		}
+		format, err := args.format()
+		if err != nil {
+			return nil, err
+		}
+		filter := fedramp.ImplementationFilter{System: args.System, Program: args.Program}
+		if args.Status != "" {
+			if filter.Status, err = fedramp.ParseImplementationStatus(args.Status); err != nil {
+				return nil, err
+			}
+		}
+
+		records, err := service.ListImplementations(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+
+		page, err := fedramp.Paginate(records, pageRequest)
+		if err != nil {
+			return nil, err
+		}
+
+		return formattedPage(budget, format, page, func(p fedramp.Page[fedramp.ImplementationRecord]) (any, error) {
+			return p, nil
+		}, func(d *fedramp.Document, p fedramp.Page[fedramp.ImplementationRecord]) {
+			d.Heading(1, "Control Implementations")
+			for _, record := range p.Items {
+				d.Item(0, fedramp.ControlLabel(record.ControlID), cmp.Or(string(record.Status), "no status")+" — "+record.System+", "+record.Program)
+			}
+			d.EndList()
+		})
+	}))
+
+	// Tool: export_ssp
+	exportSSPTool := mcp.NewTool("export_ssp",
+		mcp.WithDescription("Generate an OSCAL system security plan (SSP) for a system from its recorded implementations, with an implemented requirement for every control down to statement level. The json format returns the OSCAL document; markdown and text return a summary."),
+		withSystem(true),
+		mcp.WithString("program",
+			mcp.Required(),
+			mcp.Description("The compliance program (e.g., FedRAMP High, FedRAMP Moderate or a tailored program)"),
+		),
+		mcp.WithString("description",
+			mcp.Description("Description of the system for the SSP's system characteristics"),
+		),
+		withFormat(),
+	)
+	s.AddTool(exportSSPTool, toolHandler(exportSSPTool, func(ctx context.Context, args struct {
+		systemArguments
+		programArguments
+		Description string `json:"description"`
+		formatArguments
+	}) (*mcp.CallToolResult, error) {
+		format, err := args.format()
+		if err != nil {
+			return nil, err
+		}
+
+		ssp, err := service.ExportSSP(ctx, args.Program, fedramp.SSPOptions{System: args.System, Description: args.Description})
+		if err != nil {
+			return nil, err
+		}
+
+		return formattedResult(format, ssp, func(d *fedramp.Document) {
+			d.SSPSummary(ssp)
+		})
+	}))
+
+	// Tool: import_ssp
+	importSSPTool := mcp.NewTool("import_ssp",
+		mcp.WithDescription("Import an existing OSCAL system security plan (SSP) into the implementation records: statuses, statement narratives, parameter values, components and responsible roles. Reports the baseline controls missing from the SSP and the SSP controls that are not in the baseline."),
+		mcp.WithString("ssp",
+			mcp.Required(),
+			mcp.Description("The OSCAL system security plan in JSON"),
+		),
+		mcp.WithString("program",
+			mcp.Required(),
+			mcp.Description("The compliance program (e.g., FedRAMP High, FedRAMP Moderate or a tailored program)"),
+		),
+		mcp.WithString("system",
+			mcp.Description("The name of the system to record the implementations for; defaults to the system name in the SSP"),
+		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description("Only report what would be imported, without changing any records"),
+		),
+		withFormat(),
+	)
+	s.AddTool(importSSPTool, toolHandler(importSSPTool, func(ctx context.Context, args struct {
+		SSP string `json:"ssp"`
+		programArguments
+		systemArguments
+		DryRun bool `json:"dryRun"`
+		formatArguments
+	}) (*mcp.CallToolResult, error) {
+		format, err := args.format()
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := service.ImportSSP(ctx, args.Program, []byte(args.SSP), args.System, args.DryRun)
+		if err != nil {
+			return nil, err
+		}
+
+		return formattedResult(format, result, func(d *fedramp.Document) {
+			d.SSPImport(result)
+		})
+	}))
+
+	// Tool: assign_parameter
+	assignParameterTool := mcp.NewTool("assign_parameter",
+		mcp.WithDescription("Assign a system's value to an organization-defined parameter (ODP), e.g. \"90 days\" for an account inactivity period. Values are checked against the parameter's choices and constraints, and replace the program's value in the system's control prose and SSP."),
+		withSystem(true),
+		mcp.WithString("program",
+			mcp.Required(),
+			mcp.Description("The compliance program (e.g., FedRAMP High, FedRAMP Moderate or a tailored program)"),
+		),
+		mcp.WithString("parameterId",
+			mcp.Required(),
+			mcp.Description("The ID of the parameter (e.g., ac-02_odp.01)"),
+		),
+		mcp.WithArray("values",
+			mcp.Required(),
+			mcp.Description("The values to assign; an empty list removes the assignment"),
+			mcp.WithStringItems(),
+		),
+		withFormat(),
+	)
+	s.AddTool(assignParameterTool, toolHandler(assignParameterTool, func(ctx context.Context, args struct {
+		systemArguments
+		programArguments
+		ParameterID string   `json:"parameterId"`
+		Values      []string `json:"values"`
+		formatArguments
+	}) (*mcp.CallToolResult, error) {
+		format, err := args.format()
+		if err != nil {
+			return nil, err
+		}
+
+		record, err := service.AssignParameter(ctx, args.System, args.Program, args.ParameterID, args.Values)
+		if err != nil {
+			return nil, err
+		}
+
+		return formattedResult(format, record, func(d *fedramp.Document) {
+			implementationDocument(d, record)
+		})
+	}))
+
+	// Tool: get_parameter_report
+	parameterReportTool := mcp.NewTool("get_parameter_report",
+		mcp.WithDescription("Report the organization-defined parameters (ODPs) across a program's baseline that a system has not assigned values to and the program does not set, with their choices and constraints"),
+		withSystem(true),
+		mcp.WithString("program",
+			mcp.Required(),
+			mcp.Description("The compliance program (e.g., FedRAMP High, FedRAMP Moderate or a tailored program)"),
+		),
+		withPagination(),
+		withFormat(),
+	)
+	s.AddTool(parameterReportTool, toolHandler(parameterReportTool, func(ctx context.Context, args struct {
+		systemArguments
+		programArguments
+		paginationArguments
+		formatArguments
+	}) (*mcp.CallToolResult, error) {
+		pageRequest, err := args.pageRequest()
+		if err != nil {
+			return nil, err
+		}
+		format, err := args.format()
+		if err != nil {
+			return nil, err
+		}
+
+		report, err := service.GetParameterReport(ctx, args.System, args.Program)
+		if err != nil {
+			return nil, err
+		}
+
+		page, err := fedramp.Paginate(report.Unassigned, pageRequest)
+		if err != nil {
+			return nil, err
+		}
+
+		// Page through the unassigned parameters, keeping the totals on every page
+		type ParameterReportPage struct {
+			fedramp.ParameterReport
+			Unassigned fedramp.Page[fedramp.UnassignedParameter] `json:"unassigned"`
+		}
+
+		return formattedPage(budget, format, page, func(p fedramp.Page[fedramp.UnassignedParameter]) (any, error) {
+			return ParameterReportPage{ParameterReport: report, Unassigned: p}, nil
+		}, func(d *fedramp.Document, p fedramp.Page[fedramp.UnassignedParameter]) {
+			d.Heading(1, "Unassigned Parameters for "+report.System)
+			d.Field("Program", report.Program)
+			d.Field("Parameters", fmt.Sprintf("%d (%d assigned by the system, %d set by the program, %d unassigned)",
+				report.Total, report.AssignedBySystem, report.SetByProgram, len(report.Unassigned)))
+			d.UnassignedParameters(p.Items)
+		})
+	}))
+
+	// Tool: get_gap_analysis
+	gapAnalysisTool := mcp.NewTool("get_gap_analysis",
+		mcp.WithDescription("Analyze how far a system is from a program's baseline: readiness, control counts and percentages by status overall and by family, and the unaddressed controls and statements ranked by related-control fan-out. Use it to summarize readiness."),
+		withSystem(true),
+		mcp.WithString("program",
+			mcp.Required(),
+			mcp.Description("The compliance program (e.g., FedRAMP High, FedRAMP Moderate or a tailored program)"),
+		),
+		mcp.WithNumber("limit",
+			mcp.Description(fmt.Sprintf("Maximum number of ranked gaps to list (default %d)", defaultGapLimit)),
+			integer(),
+			mcp.Min(1),
+		),
+		mcp.WithString("format",
+			mcp.Description("Response format: json (default), markdown, text or csv (one row per gap)"),
+			mcp.Enum(append(slices.Clone(fedramp.Formats), "csv")...),
+		),
+	)
+	s.AddTool(gapAnalysisTool, toolHandler(gapAnalysisTool, func(ctx context.Context, args struct {
+		systemArguments
+		programArguments
+		Limit  int    `json:"limit"`
+		Format string `json:"format"`
+	}) (*mcp.CallToolResult, error) {
+		csvFormat := strings.EqualFold(strings.TrimSpace(args.Format), "csv")
+		var format fedramp.Format
+		if !csvFormat {
+			var err error
+			if format, err = fedramp.ParseFormat(args.Format); err != nil {
+				return nil, err
+			}
+		}
+		limit := args.Limit
+		if limit <= 0 {
+			limit = defaultGapLimit
+		}
+
+		analysis, err := service.GetGapAnalysis(ctx, args.System, args.Program)
+		if err != nil {
+			return nil, err
+		}
+
+		// List the top gaps, keeping the count of all of them
+		type GapAnalysisResponse struct {
+			fedramp.GapAnalysis
+			Gaps      []fedramp.ControlGap `json:"gaps"`
+			TotalGaps int                  `json:"totalGaps"`
+		}
+		response := GapAnalysisResponse{GapAnalysis: analysis, Gaps: analysis.Gaps, TotalGaps: len(analysis.Gaps)}
+		if len(response.Gaps) > limit {
+			response.Gaps = response.Gaps[:limit]
+		}
+
+		if csvFormat {
+			analysis.Gaps = response.Gaps
+			var b strings.Builder
+			if err := analysis.WriteCSV(&b); err != nil {
+				return nil, err
+			}
+			return mcp.NewToolResultText(b.String()), nil
+		}
+		return formattedResult(format, response, func(d *fedramp.Document) {
+			d.GapAnalysis(analysis, limit)
+		})
+	}))
+
+	// Tool: lint_narrative
+	lintNarrativeTool := mcp.NewTool("lint_narrative",
+		mcp.WithDescription("Check an implementation narrative against a control before it goes into an SSP: whether each labelled statement part (a., b., ...) is addressed, whether the assigned parameter values are mentioned and where vague language such as \"as appropriate\" or \"periodically\" needs specifics. Checks the system's recorded narrative, or a draft passed in narrative and statementNarratives. Returns findings part by part."),
+		withSystem(false),
+		mcp.WithString("program",
+			mcp.Required(),
+			mcp.Description("The compliance program (e.g., FedRAMP High, FedRAMP Moderate or a tailored program)"),
+		),
+		mcp.WithString("controlId",
+			mcp.Required(),
+			mcp.Description("The ID of the control (e.g., AC-1, IA-2 or AC-2(1))"),
+		),
+		mcp.WithString("narrative",
+			mcp.Description("Draft narrative to check in place of the recorded one; label the description of each part, e.g. \"a. ...\" or \"Part b: ...\". Parameter values assigned by the system are used when system is given."),
+		),
+		mcp.WithObject("statementNarratives",
+			mcp.Description("Draft narratives for individual statements to check in place of the recorded ones, by statement (e.g., {\"a\": \"...\", \"ac-2_smt.b\": \"...\"})"),
+			mcp.AdditionalProperties(map[string]any{"type": "string"}),
+		),
+		withFormat(),
+	)
+	s.AddTool(lintNarrativeTool, toolHandler(lintNarrativeTool, func(ctx context.Context, args struct {
+		systemArguments
+		controlArguments
+		Narrative           *string           `json:"narrative"`
+		StatementNarratives map[string]string `json:"statementNarratives"`
+		formatArguments
+	}) (*mcp.CallToolResult, error) {
+		format, err := args.format()
+		if err != nil {
+			return nil, err
+		}
+
+		lint, err := service.LintNarrative(ctx, args.System, args.Program, args.ControlID, args.Narrative, args.StatementNarratives)
+		if err != nil {
+			return nil, err
+		}
+
+		return formattedResult(format, lint, func(d *fedramp.Document) {
+			d.NarrativeLint(lint)
+		})
+	}))
+
+	// Tool: generate_policy_documents
+	policyDocumentsTool := mcp.NewTool("generate_policy_documents",
+		mcp.WithDescription("Generate Markdown skeletons of a control family's policy and procedures, as its policy and procedures control (e.g., AC-1) requires. The documents cover the family's controls with the parameter values the system assigns, and leave placeholders such as \"[Assignment: frequency]\" and bracketed prompts for the details to fill in with the user."),
+		mcp.WithString("program",
+			mcp.Required(),
+			mcp.Description("The compliance program (e.g., FedRAMP High, FedRAMP Moderate or a tailored program)"),
+		),
+		mcp.WithString("family",
+			mcp.Required(),
+			mcp.Description("The control family (e.g., AC, AU or IR)"),
+		),
+		withSystem(false),
+		mcp.WithString("kind",
+			mcp.Description("The document to generate; both a policy and procedures if omitted"),
+			mcp.Enum(fedramp.PolicyDocumentKinds...),
+		),
+		mcp.WithString("template",
+			mcp.Description("A Go text/template to generate the document of the given kind with, in place of the configured template"),
+		),
+		mcp.WithString("format",
+			mcp.Description("Response format: markdown (default, the documents themselves) or json (the documents with their titles and templates)"),
+			mcp.Enum(string(fedramp.FormatMarkdown), string(fedramp.FormatJSON)),
+		),
+	)
+	s.AddTool(policyDocumentsTool, toolHandler(policyDocumentsTool, func(ctx context.Context, args struct {
+		systemArguments
+		programArguments
+		Family   string `json:"family"`
+		Kind     string `json:"kind"`
+		Template string `json:"template"`
+		Format   string `json:"format"`
+	}) (*mcp.CallToolResult, error) {
+		var kinds []fedramp.PolicyDocumentKind
+		var templates map[fedramp.PolicyDocumentKind]string
+		if args.Kind != "" {
+			kind, err := fedramp.ParsePolicyDocumentKind(args.Kind)
+			if err != nil {
+				return nil, err
+			}
+			kinds = []fedramp.PolicyDocumentKind{kind}
+		}
+		if args.Template != "" {
+			if len(kinds) == 0 {
+				return nil, fedramp.NewError(fedramp.ErrInvalidArgument, "kind is required with template")
+			}
+			templates = map[fedramp.PolicyDocumentKind]string{kinds[0]: args.Template}
+		}
+
+		documents, err := service.GeneratePolicyDocuments(ctx, args.System, args.Program, []string{args.Family}, kinds, templates)
+		if err != nil {
+			return nil, err
+		}
+
+		if strings.EqualFold(strings.TrimSpace(args.Format), string(fedramp.FormatJSON)) {
+			return jsonResult(documents)
+		}
+		markdown := make([]string, 0, len(documents))
+		for _, document := range documents {
+			markdown = append(markdown, document.Markdown)
+		}
+		return mcp.NewToolResultText(strings.Join(markdown, "\n")), nil
+	}))
+
+	// Tool: set_responsibility
+	setResponsibilityTool := mcp.NewTool("set_responsibility",
+		mcp.WithDescription("Record who is responsible for a control or one of its statements: the provider, its customers, shared, or inherited from a leveraged authorization (e.g., the FedRAMP-authorized IaaS the system runs on). Inherited controls and statements count as addressed in the gap analysis and are attributed to the leveraged system in the SSP."),
+		withSystem(true),
+		mcp.WithString("program",
+			mcp.Required(),
+			mcp.Description("The compliance program (e.g., FedRAMP High, FedRAMP Moderate or a tailored program)"),
+		),
+		mcp.WithString("controlId",
+			mcp.Required(),
+			mcp.Description("The ID of the control (e.g., AC-1, IA-2 or AC-2(1))"),
+		),
+		mcp.WithString("statement",
+			mcp.Description("The statement of the control (e.g., a or ac-2_smt.a); the control as a whole if left out"),
+		),
+		mcp.WithString("responsibility",
+			mcp.Required(),
+			mcp.Description("Who is responsible; an empty value removes the recorded responsibility"),
+			mcp.Enum(append(slices.Clone(fedramp.ResponsibilityRoles), "")...),
+		),
+		mcp.WithString("leveragedAuthorization",
+			mcp.Description("The leveraged authorization it is inherited from or shared with (e.g., AWS GovCloud (US) High P-ATO); required when inherited"),
+		),
+		mcp.WithString("description",
+			mcp.Description("What each party does, e.g. the customer's responsibilities"),
+		),
+		withFormat(),
+	)
+	s.AddTool(setResponsibilityTool, toolHandler(setResponsibilityTool, func(ctx context.Context, args struct {
+		systemArguments
+		controlArguments
+		Statement              string `json:"statement"`
+		Responsibility         string `json:"responsibility"`
+		LeveragedAuthorization string `json:"leveragedAuthorization"`
+		Description            string `json:"description"`
+		formatArguments
+	}) (*mcp.CallToolResult, error) {
+		format, err := args.format()
+		if err != nil {
+			return nil, err
+		}
+		responsibility := fedramp.Responsibility{LeveragedAuthorization: args.LeveragedAuthorization, Description: args.Description}
+		if strings.TrimSpace(args.Responsibility) != "" {
+			if responsibility.Role, err = fedramp.ParseResponsibilityRole(args.Responsibility); err != nil {
+				return nil, err
+			}
+		}
+
+		record, err := service.SetResponsibility(ctx, args.System, args.Program, args.ControlID, args.Statement, responsibility)
+		if err != nil {
+			return nil, err
+		}
+
+		return formattedResult(format, record, func(d *fedramp.Document) {
+			implementationDocument(d, record)
+		})
+	}))
+
+	// Tool: import_crm
+	importCRMTool := mcp.NewTool("import_crm",
+		mcp.WithDescription("Import a leveraged system's customer responsibility matrix (CRM), exported from the FedRAMP CRM workbook as CSV. Controls and statements it marks as inheritable (Yes) become inherited from the leveraged authorization, partially inheritable ones (Partial) shared with it, and the others (No) the provider's own responsibility."),
+		withSystem(true),
+		mcp.WithString("program",
+			mcp.Required(),
+			mcp.Description("The compliance program (e.g., FedRAMP High, FedRAMP Moderate or a tailored program)"),
+		),
+		mcp.WithString("crm",
+			mcp.Required(),
+			mcp.Description("The CRM as CSV, with Control ID, Can Be Inherited from CSP and Specific Inheritance and Customer Agency/CSP Responsibilities columns"),
+		),
+		mcp.WithString("leveragedAuthorization",
+			mcp.Required(),
+			mcp.Description("The leveraged authorization the CRM belongs to (e.g., AWS GovCloud (US) High P-ATO)"),
+		),
+		mcp.WithBoolean("dryRun",
+			mcp.Description("Only report what would be imported, without changing any records"),
+		),
+		withFormat(),
+	)
+	s.AddTool(importCRMTool, toolHandler(importCRMTool, func(ctx context.Context, args struct {
+		systemArguments
+		programArguments
+		CRM                    string `json:"crm"`
+		LeveragedAuthorization string `json:"leveragedAuthorization"`
+		DryRun                 bool   `json:"dryRun"`
+		formatArguments
+	}) (*mcp.CallToolResult, error) {
+		format, err := args.format()
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := service.ImportCRM(ctx, args.Program, []byte(args.CRM), args.System, args.LeveragedAuthorization, args.DryRun)
+		if err != nil {
+			return nil, err
+		}
+
+		return formattedResult(format, result, func(d *fedramp.Document) {
+			d.CRMImport(result)
+		})
+	}))
+
+	// Tool: export_crm
+	exportCRMTool := mcp.NewTool("export_crm",
+		mcp.WithDescription("Export a system's customer responsibility matrix (CRM) as CSV in the FedRAMP CRM workbook column layout, with a row for every control and for each statement with its own responsibility"),
+		withSystem(true),
+		mcp.WithString("program",
+			mcp.Required(),
+			mcp.Description("The compliance program (e.g., FedRAMP High, FedRAMP Moderate or a tailored program)"),
+		),
+	)
+	s.AddTool(exportCRMTool, toolHandler(exportCRMTool, func(ctx context.Context, args struct {
+		systemArguments
+		programArguments
+	}) (*mcp.CallToolResult, error) {
+		crm, err := service.ExportCRM(ctx, args.System, args.Program)
+		if err != nil {
+			return nil, err
+		}
+		return mcp.NewToolResultText(string(crm)), nil
+	}))
+}
+
+// defaultGapLimit is the number of ranked gaps get_gap_analysis lists by default
+const defaultGapLimit = 25
+
+// systemArguments identifies the system whose implementation is recorded
+type systemArguments struct {
+	System string `json:"system"`
+}
+
+// withSystem adds the system argument used by the implementation tools
+func withSystem(required bool) mcp.ToolOption {
+	options := []mcp.PropertyOption{
+		mcp.Description("The name of the system implementing the controls (e.g., the system in the SSP)"),
+	}
+	if required {
+		options = append(options, mcp.Required())
+	}
+	return mcp.WithString("system", options...)
+}
+
+// implementationDocument renders an implementation record
+func implementationDocument(d *fedramp.Document, record fedramp.ImplementationRecord) {
+	d.Heading(1, fedramp.ControlLabel(record.ControlID)+" Implementation")
+	d.Field("System", record.System)
+	d.Field("Program", record.Program)
+	if record.Status != "" {
+		d.Field("Status", string(record.Status))
+	}
+	if len(record.ResponsibleRoles) > 0 {
+		d.Field("Responsible roles", strings.Join(record.ResponsibleRoles, ", "))
+	}
+	if len(record.Components) > 0 {
+		d.Field("Components", strings.Join(record.Components, ", "))
+	}
+	if record.Responsibility != nil {
+		d.Field("Responsibility", fedramp.ResponsibilityDescription(*record.Responsibility))
+	}
+	d.Field("Updated", record.UpdatedAt.Format("2006-01-02 15:04 MST"))
+	if record.Narrative != "" {
+		d.Heading(2, "Narrative")
+		d.Paragraph(record.Narrative)
+	}
+	if len(record.Statements) > 0 {
+		d.Heading(2, "Statement Narratives")
+		for _, statementID := range slices.Sorted(maps.Keys(record.Statements)) {
+			d.Item(0, statementID, record.Statements[statementID])
+		}
+		d.EndList()
+	}
+	if len(record.StatementResponsibilities) > 0 {
+		d.Heading(2, "Statement Responsibilities")
+		for _, statementID := range slices.Sorted(maps.Keys(record.StatementResponsibilities)) {
+			d.Item(0, statementID, fedramp.ResponsibilityDescription(record.StatementResponsibilities[statementID]))
+		}
+		d.EndList()
+	}
+	if len(record.Parameters) > 0 {
+		d.Heading(2, "Parameter Values")
+		for _, paramID := range slices.Sorted(maps.Keys(record.Parameters)) {
+			d.Item(0, paramID, strings.Join(record.Parameters[paramID], ", "))
+		}
+		d.EndList()
+	}
+}
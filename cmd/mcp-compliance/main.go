@@ -2,253 +2,150 @@ package main
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
+	"flag"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/adapters"
 	"github.com/grafana/hackathon-12-mcp-compliance/internal/services/fedramp_compliance"
-	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/services/fedramp_implementation"
 	"github.com/mark3labs/mcp-go/server"
 )
 
 func main() {
-	// Create the compliance service
-	complianceService := fedramp_compliance.NewService()
+	// Define command-line flags. Transport settings can also be configured with environment
+	// variables so that a shared deployment can be configured without changing its command line.
+	transport := flag.String("transport", envOrDefault("MCP_COMPLIANCE_TRANSPORT", "stdio"), "Transport to serve the MCP server on (stdio or http) [MCP_COMPLIANCE_TRANSPORT]")
+	addr := flag.String("addr", envOrDefault("MCP_COMPLIANCE_ADDR", ":8080"), "Address to listen on when using the http transport [MCP_COMPLIANCE_ADDR]")
+	baseURL := flag.String("base-url", envOrDefault("MCP_COMPLIANCE_BASE_URL", ""), "Public base URL of the server, used to build SSE message endpoints behind a proxy [MCP_COMPLIANCE_BASE_URL]")
+	requestTimeoutFlag := flag.Duration("request-timeout", 30*time.Second, "Maximum time a tool call may run (0 disables the timeout)")
+	shutdownTimeout := flag.Duration("shutdown-timeout", 15*time.Second, "Time to wait for open connections to finish when shutting down the http transport")
+	resourcePageSize := flag.Int("resource-page-size", 100, "Number of resources, prompts and tools returned per page of an MCP list request")
+	implementationStore := flag.String("implementation-store", envOrDefault("MCP_COMPLIANCE_IMPLEMENTATION_STORE", adapters.DefaultImplementationStorePath()), "JSON file that records how systems implement controls [MCP_COMPLIANCE_IMPLEMENTATION_STORE]")
+	overlayDir := flag.String("overlay-dir", envOrDefault("MCP_COMPLIANCE_OVERLAY_DIR", adapters.DefaultOverlayDirectory()), "Directory of the overlays that tailor programs, one JSON file each [MCP_COMPLIANCE_OVERLAY_DIR]")
+	componentStore := flag.String("component-store", envOrDefault("MCP_COMPLIANCE_COMPONENT_STORE", adapters.DefaultComponentStorePath()), "JSON file of the components that implement controls [MCP_COMPLIANCE_COMPONENT_STORE]")
+	templateDir := flag.String("template-dir", envOrDefault("MCP_COMPLIANCE_TEMPLATE_DIR", adapters.DefaultTemplateDirectory()), "Directory of templates that override the default policy and procedure templates [MCP_COMPLIANCE_TEMPLATE_DIR]")
+	maxResponseBytes := flag.Int("max-response-bytes", 64*1024, "Maximum size of a tool response in bytes; larger list results are truncated with a continuation cursor (0 disables the limit)")
+
+	// Authentication and authorization flags, which only apply to the http transport
+	var authFlags authConfig
+	flag.StringVar(&authFlags.mode, "auth", envOrDefault("MCP_COMPLIANCE_AUTH", "none"), "Authentication for the http transport (none, token or jwt) [MCP_COMPLIANCE_AUTH]")
+	flag.StringVar(&authFlags.tokensFile, "auth-tokens-file", envOrDefault("MCP_COMPLIANCE_AUTH_TOKENS_FILE", ""), "JSON file of static bearer tokens for token authentication [MCP_COMPLIANCE_AUTH_TOKENS_FILE]")
+	flag.StringVar(&authFlags.jwksFile, "auth-jwks-file", envOrDefault("MCP_COMPLIANCE_AUTH_JWKS_FILE", ""), "JWKS file with the issuer's signing keys for jwt authentication [MCP_COMPLIANCE_AUTH_JWKS_FILE]")
+	flag.StringVar(&authFlags.issuer, "auth-issuer", envOrDefault("MCP_COMPLIANCE_AUTH_ISSUER", ""), "Expected issuer of JWTs [MCP_COMPLIANCE_AUTH_ISSUER]")
+	flag.StringVar(&authFlags.audience, "auth-audience", envOrDefault("MCP_COMPLIANCE_AUTH_AUDIENCE", ""), "Expected audience of JWTs [MCP_COMPLIANCE_AUTH_AUDIENCE]")
+	flag.StringVar(&authFlags.groupsClaim, "auth-groups-claim", envOrDefault("MCP_COMPLIANCE_AUTH_GROUPS_CLAIM", "groups"), "JWT claim holding the caller's groups [MCP_COMPLIANCE_AUTH_GROUPS_CLAIM]")
+	flag.StringVar(&authFlags.policyFile, "auth-policy-file", envOrDefault("MCP_COMPLIANCE_AUTH_POLICY_FILE", ""), "JSON authorization policy granting tools and programs to subjects and groups [MCP_COMPLIANCE_AUTH_POLICY_FILE]")
+	flag.Parse()
+
+	// Set up authentication and authorization
+	if authFlags.enabled() && *transport != "http" {
+		log.Fatalf("Authentication and authorization require the http transport")
+	}
+	authenticator, err := newAuthenticator(authFlags)
+	if err != nil {
+		log.Fatalf("Failed to set up authentication: %v", err)
+	}
+	policy, err := loadPolicy(authFlags)
+	if err != nil {
+		log.Fatalf("Failed to load authorization policy: %v", err)
+	}
+	if policy != nil && authenticator == nil {
+		log.Fatalf("An authorization policy requires authentication (-auth token or -auth jwt)")
+	}
+
+	// Create the compliance service. The catalog is an unrestricted view of the same data, used to
+	// build resource listings and readiness checks that do not run on behalf of a caller.
+	overlays := adapters.NewJSONOverlayRepository(*overlayDir)
+	catalog := fedramp_compliance.NewService(fedramp_compliance.WithOverlayRepository(overlays))
+	complianceService := catalog
+	if policy != nil {
+		complianceService = fedramp_compliance.NewService(fedramp_compliance.WithOverlayRepository(overlays), fedramp_compliance.WithPolicy(*policy))
+	}
+
+	// Create the implementation tracking service
+	implementationOptions := []fedramp_implementation.Option{
+		fedramp_implementation.WithComponentRepository(adapters.NewJSONComponentRepository(*componentStore)),
+		fedramp_implementation.WithOverlayRepository(overlays),
+		fedramp_implementation.WithTemplateRepository(adapters.NewFileTemplateRepository(*templateDir)),
+	}
+	if policy != nil {
+		implementationOptions = append(implementationOptions, fedramp_implementation.WithPolicy(*policy))
+	}
+	implementationService := fedramp_implementation.NewService(adapters.NewJSONImplementationRepository(*implementationStore), implementationOptions...)
 
 	// Create the MCP server
+	completions := &complianceCompletions{service: complianceService}
+	hooks := &server.Hooks{}
 	s := server.NewMCPServer(
 		"MCP Compliance Server",
 		"1.0.0",
+		server.WithResourceCapabilities(false, false),
+		server.WithPromptCapabilities(false),
+		server.WithCompletions(),
+		server.WithPromptCompletionProvider(completions),
+		server.WithResourceCompletionProvider(completions),
+		server.WithPaginationLimit(*resourcePageSize),
+		server.WithToolHandlerMiddleware(requestTimeout(*requestTimeoutFlag)),
+		server.WithToolHandlerMiddleware(authorizeTools(policy)),
+		server.WithToolFilter(filterTools(policy)),
+		server.WithHooks(hooks),
 	)
 
 	// Add tools to the server
-	addComplianceTools(s, complianceService)
-
-	// Start the server using stdio
-	log.Println("Starting MCP Compliance Server...")
-	if err := server.ServeStdio(s); err != nil {
-		log.Fatalf("Server error: %v", err)
+	addComplianceTools(s, complianceService, implementationService, responseBudget{maxBytes: *maxResponseBytes})
+	addImplementationTools(s, implementationService, responseBudget{maxBytes: *maxResponseBytes})
+	addComponentTools(s, implementationService)
+
+	// Add resources to the server
+	resources, err := addComplianceResources(s, complianceService, catalog)
+	if err != nil {
+		log.Fatalf("Failed to add resources: %v", err)
 	}
-}
-
-// addComplianceTools adds all compliance-related tools to the MCP server
-func addComplianceTools(s *server.MCPServer, service *fedramp_compliance.Service) {
-	// Tool: list_compliance_programs
-	listProgramsTool := mcp.NewTool("list_compliance_programs",
-		mcp.WithDescription("List all available compliance programs"),
-	)
-	s.AddTool(listProgramsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		programs, err := service.ListCompliancePrograms()
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to list compliance programs: %v", err)), nil
-		}
-
-		// Format the result as JSON
-		programsJSON, err := json.MarshalIndent(programs, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal programs to JSON: %v", err)), nil
-		}
-
-		return mcp.NewToolResultText(string(programsJSON)), nil
-	})
-
-	// Tool: get_control
-	getControlTool := mcp.NewTool("get_control",
-		mcp.WithDescription("Get detailed information about a specific control"),
-		mcp.WithString("program",
-			mcp.Required(),
-			mcp.Description("The FedRAMP program (High or Moderate)"),
-			mcp.Enum("FedRAMP High", "FedRAMP Moderate"),
-		),
-		mcp.WithString("controlId",
-			mcp.Required(),
-			mcp.Description("The ID of the control (e.g., AC-1, IA-2)"),
-		),
-	)
-	s.AddTool(getControlTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		program := request.Params.Arguments["program"].(string)
-		controlID := request.Params.Arguments["controlId"].(string)
-
-		control, found, err := service.GetControl(program, controlID)
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to get control: %v", err)), nil
-		}
-		if !found {
-			return mcp.NewToolResultError(fmt.Sprintf("Control %s not found in %s", controlID, program)), nil
-		}
-
-		// Format the result as JSON
-		controlJSON, err := json.MarshalIndent(control, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal control to JSON: %v", err)), nil
-		}
-
-		return mcp.NewToolResultText(string(controlJSON)), nil
-	})
-
-	// Tool: get_control_family
-	getControlFamilyTool := mcp.NewTool("get_control_family",
-		mcp.WithDescription("Get all controls in a family (e.g., AC for Access Control)"),
-		mcp.WithString("program",
-			mcp.Required(),
-			mcp.Description("The FedRAMP program (High or Moderate)"),
-			mcp.Enum("FedRAMP High", "FedRAMP Moderate"),
-		),
-		mcp.WithString("family",
-			mcp.Required(),
-			mcp.Description("The control family ID (e.g., AC, IA)"),
-		),
-	)
-	s.AddTool(getControlFamilyTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		program := request.Params.Arguments["program"].(string)
-		familyID := request.Params.Arguments["family"].(string)
-
-		family, found, err := service.GetControlFamily(program, familyID)
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to get control family: %v", err)), nil
-		}
-		if !found {
-			return mcp.NewToolResultError(fmt.Sprintf("Control family %s not found in %s", familyID, program)), nil
-		}
-
-		// Format the result as JSON
-		familyJSON, err := json.MarshalIndent(family, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal control family to JSON: %v", err)), nil
-		}
-
-		return mcp.NewToolResultText(string(familyJSON)), nil
-	})
+	hooks.AddAfterListResources(filterResources(policy, resources))
 
-	// Tool: list_control_families
-	listControlFamiliesTool := mcp.NewTool("list_control_families",
-		mcp.WithDescription("List all control families in a program"),
-		mcp.WithString("program",
-			mcp.Required(),
-			mcp.Description("The FedRAMP program (High or Moderate)"),
-			mcp.Enum("FedRAMP High", "FedRAMP Moderate"),
-		),
-	)
-	s.AddTool(listControlFamiliesTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		program := request.Params.Arguments["program"].(string)
-
-		families, err := service.ListControlFamilies(program)
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to list control families: %v", err)), nil
-		}
-
-		// Create a simplified response with just family ID, title, and control count
-		type SimplifiedFamily struct {
-			ID           string `json:"id"`
-			Title        string `json:"title"`
-			ControlCount int    `json:"controlCount"`
-		}
-
-		simplifiedFamilies := make([]SimplifiedFamily, 0, len(families))
-		for _, family := range families {
-			simplifiedFamilies = append(simplifiedFamilies, SimplifiedFamily{
-				ID:           family.ID,
-				Title:        family.Title,
-				ControlCount: len(family.Controls),
-			})
-		}
-
-		// Format the result as JSON
-		familiesJSON, err := json.MarshalIndent(simplifiedFamilies, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal families to JSON: %v", err)), nil
-		}
-
-		return mcp.NewToolResultText(string(familiesJSON)), nil
-	})
-
-	// Tool: search_controls
-	searchControlsTool := mcp.NewTool("search_controls",
-		mcp.WithDescription("Search for controls by keyword"),
-		mcp.WithString("program",
-			mcp.Required(),
-			mcp.Description("The FedRAMP program (High or Moderate)"),
-			mcp.Enum("FedRAMP High", "FedRAMP Moderate"),
-		),
-		mcp.WithString("query",
-			mcp.Required(),
-			mcp.Description("The search query"),
-		),
-	)
-	s.AddTool(searchControlsTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		program := request.Params.Arguments["program"].(string)
-		query := request.Params.Arguments["query"].(string)
-
-		controls, err := service.SearchControls(program, query)
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to search controls: %v", err)), nil
-		}
-
-		// Create a simplified response with just control ID, title
-		type SimplifiedControl struct {
-			ID    string `json:"id"`
-			Title string `json:"title"`
-		}
-
-		simplifiedControls := make([]SimplifiedControl, 0, len(controls))
-		for _, control := range controls {
-			simplifiedControls = append(simplifiedControls, SimplifiedControl{
-				ID:    control.ID,
-				Title: control.Title,
-			})
-		}
-
-		// Format the result as JSON
-		controlsJSON, err := json.MarshalIndent(simplifiedControls, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal controls to JSON: %v", err)), nil
-		}
-
-		return mcp.NewToolResultText(string(controlsJSON)), nil
-	})
-
-	// Tool: get_control_evidence_guidance
-	getControlEvidenceGuidanceTool := mcp.NewTool("get_control_evidence_guidance",
-		mcp.WithDescription("Get detailed guidance for evidence about a specific control"),
-		mcp.WithString("program",
-			mcp.Required(),
-			mcp.Description("The FedRAMP program (High or Moderate)"),
-			mcp.Enum("FedRAMP High", "FedRAMP Moderate"),
-		),
-		mcp.WithString("controlId",
-			mcp.Required(),
-			mcp.Description("The ID of the control (e.g., AC-1, IA-2)"),
-		),
-	)
-	s.AddTool(getControlEvidenceGuidanceTool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		program := request.Params.Arguments["program"].(string)
-		controlID := request.Params.Arguments["controlId"].(string)
-
-		guidance, found, err := service.GetControlEvidenceGuidance(program, controlID)
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to get control evidence guidance: %v", err)), nil
-		}
-		if !found {
-			return mcp.NewToolResultError(fmt.Sprintf("Control %s not found in %s", controlID, program)), nil
-		}
+	// Add the overlay tools, which add programs, and offer every program to the tools
+	addOverlayTools(s, complianceService, catalog, resources)
+	programs, err := catalog.ListCompliancePrograms(context.Background())
+	if err != nil {
+		log.Fatalf("Failed to list compliance programs: %v", err)
+	}
+	setProgramChoices(s, programs)
 
-		// Create a response structure
-		response := struct {
-			ControlID string `json:"controlId"`
-			Program   string `json:"program"`
-			Guidance  string `json:"guidance"`
-		}{
-			ControlID: controlID,
-			Program:   program,
-			Guidance:  guidance,
-		}
+	// Add prompts to the server
+	addCompliancePrompts(s, complianceService)
 
-		// Format the result as JSON
-		responseJSON, err := json.MarshalIndent(response, "", "  ")
-		if err != nil {
-			return mcp.NewToolResultError(fmt.Sprintf("Failed to marshal response to JSON: %v", err)), nil
-		}
+	log.Println("Starting MCP Compliance Server...")
+	switch *transport {
+	case "stdio":
+		if err := server.ServeStdio(s); err != nil {
+			log.Fatalf("Server error: %v", err)
+		}
+	case "http":
+		ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+		defer stop()
+
+		config := httpConfig{
+			addr:            *addr,
+			baseURL:         *baseURL,
+			shutdownTimeout: *shutdownTimeout,
+			authenticator:   authenticator,
+		}
+		if err := serveHTTP(ctx, s, catalog, config); err != nil {
+			log.Fatalf("Server error: %v", err)
+		}
+	default:
+		log.Fatalf("Unknown transport %q (expected stdio or http)", *transport)
+	}
+}
 
-		return mcp.NewToolResultText(string(responseJSON)), nil
-	})
+// envOrDefault returns the value of an environment variable, or the fallback if it is not set
+func envOrDefault(name, fallback string) string {
+	if value, ok := os.LookupEnv(name); ok && value != "" {
+		return value
+	}
+	return fallback
 }
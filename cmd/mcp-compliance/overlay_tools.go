@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"log"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/services/fedramp_compliance"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// addOverlayTools adds the tools that tailor programs with overlays. A saved overlay becomes a
+// program of its own, which is offered by every tool and listed as resources from then on.
+func addOverlayTools(s *server.MCPServer, service, catalog *fedramp_compliance.Service, resources *complianceResources) {
+	// Tool: save_overlay
+	saveOverlayTool := mcp.NewTool("save_overlay",
+		mcp.WithDescription("Tailor a program for an organization with an overlay: add controls from another program, mark controls not applicable with a justification and tighten parameter values. The tailored program is available to every tool under the overlay's name. Creates the overlay if needed; arguments that are left out keep their recorded values."),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("The name of the overlay and of the tailored program (e.g., Acme Moderate)"),
+		),
+		mcp.WithString("base",
+			mcp.Description("The program the overlay tailors, required for a new overlay"),
+		),
+		mcp.WithString("description",
+			mcp.Description("What the tailored program is for"),
+		),
+		mcp.WithArray("addControls",
+			mcp.Description("Controls to add from the addFrom program (e.g., AC-2(11)); replaces the recorded additions from that program"),
+			mcp.WithStringItems(),
+		),
+		mcp.WithString("addFrom",
+			mcp.Description("The program to add controls from, required with addControls"),
+		),
+		mcp.WithObject("notApplicable",
+			mcp.Description("Controls that are not applicable, with the justification for each (e.g., {\"PE-13\": \"No data center of our own\"}); replaces the recorded ones"),
+			mcp.AdditionalProperties(map[string]any{"type": "string"}),
+		),
+		mcp.WithObject("parameters",
+			mcp.Description("Values that replace the base program's parameter values, by parameter (e.g., {\"ac-02_odp.10\": [\"30 days\"]}); replaces the recorded ones. Values must not loosen a period the base program sets."),
+			mcp.AdditionalProperties(map[string]any{"type": "array", "items": map[string]any{"type": "string"}}),
+		),
+		withFormat(),
+	)
+	s.AddTool(saveOverlayTool, toolHandler(saveOverlayTool, func(ctx context.Context, args struct {
+		Name          string              `json:"name"`
+		Base          string              `json:"base"`
+		Description   *string             `json:"description"`
+		AddControls   []string            `json:"addControls"`
+		AddFrom       string              `json:"addFrom"`
+		NotApplicable map[string]string   `json:"notApplicable"`
+		Parameters    map[string][]string `json:"parameters"`
+		formatArguments
+	}) (*mcp.CallToolResult, error) {
+		format, err := args.format()
+		if err != nil {
+			return nil, err
+		}
+		if args.AddControls != nil && args.AddFrom == "" {
+			return nil, fedramp.NewError(fedramp.ErrInvalidArgument, "addFrom is required with addControls")
+		}
+
+		// Start from the recorded overlay, if any
+		overlay, err := service.GetOverlay(ctx, args.Name)
+		switch {
+		case fedramp.KindOf(err) == fedramp.ErrNotFound:
+			overlay = fedramp.Overlay{Name: args.Name}
+		case err != nil:
+			return nil, err
+		}
+
+		// Apply the arguments
+		if args.Base != "" {
+			overlay.Base = args.Base
+		}
+		if args.Description != nil {
+			overlay.Description = *args.Description
+		}
+		if args.AddControls != nil {
+			overlay.Add = slices.DeleteFunc(overlay.Add, func(addition fedramp.OverlayAddition) bool {
+				return strings.EqualFold(addition.From, args.AddFrom)
+			})
+			for _, controlID := range args.AddControls {
+				overlay.Add = append(overlay.Add, fedramp.OverlayAddition{ControlID: controlID, From: args.AddFrom})
+			}
+		}
+		if args.NotApplicable != nil {
+			overlay.NotApplicable = nil
+			for _, controlID := range slices.Sorted(maps.Keys(args.NotApplicable)) {
+				overlay.NotApplicable = append(overlay.NotApplicable, fedramp.OverlayNotApplicable{ControlID: controlID, Justification: args.NotApplicable[controlID]})
+			}
+		}
+		if args.Parameters != nil {
+			overlay.Parameters = nil
+			for _, paramID := range slices.Sorted(maps.Keys(args.Parameters)) {
+				overlay.Parameters = append(overlay.Parameters, fedramp.OverlayParameter{ParameterID: paramID, Values: args.Parameters[paramID]})
+			}
+		}
+
+		overlay, err = service.SaveOverlay(ctx, overlay)
+		if err != nil {
+			return nil, err
+		}
+
+		// Offer the tailored program to every tool and list it as resources
+		if programs, err := catalog.ListCompliancePrograms(context.Background()); err != nil {
+			log.Printf("Failed to list compliance programs: %v", err)
+		} else {
+			setProgramChoices(s, programs)
+		}
+		if err := resources.addProgram(s, catalog, overlay.Name); err != nil {
+			log.Printf("Failed to add resources for %s: %v", overlay.Name, err)
+		}
+
+		return formattedResult(format, overlay, func(d *fedramp.Document) {
+			d.Overlay(overlay)
+		})
+	}))
+
+	// Tool: get_overlay
+	getOverlayTool := mcp.NewTool("get_overlay",
+		mcp.WithDescription("Get the overlay that tailors a program: its base program, the controls it adds and marks not applicable and the parameter values it sets"),
+		mcp.WithString("name",
+			mcp.Required(),
+			mcp.Description("The name of the overlay and of the tailored program"),
+		),
+		withFormat(),
+	)
+	s.AddTool(getOverlayTool, toolHandler(getOverlayTool, func(ctx context.Context, args struct {
+		Name string `json:"name"`
+		formatArguments
+	}) (*mcp.CallToolResult, error) {
+		format, err := args.format()
+		if err != nil {
+			return nil, err
+		}
+
+		overlay, err := service.GetOverlay(ctx, args.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		return formattedResult(format, overlay, func(d *fedramp.Document) {
+			d.Overlay(overlay)
+		})
+	}))
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/services/fedramp_compliance"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// addCompliancePrompts adds prompts for common compliance workflows to the MCP server.
+// Each prompt embeds the relevant control text from the compliance service so the agent
+// starts with the authoritative requirements instead of having to look them up.
+func addCompliancePrompts(s *server.MCPServer, service *fedramp_compliance.Service) {
+	// Prompt: draft_implementation_narrative
+	draftNarrativePrompt := mcp.NewPrompt("draft_implementation_narrative",
+		mcp.WithPromptDescription("Draft an implementation narrative describing how a system meets a control"),
+		mcp.WithArgument("program",
+			mcp.RequiredArgument(),
+			mcp.ArgumentDescription("The compliance program (e.g., FedRAMP High, FedRAMP Moderate or a tailored program)"),
+		),
+		mcp.WithArgument("controlId",
+			mcp.RequiredArgument(),
+			mcp.ArgumentDescription("The ID of the control (e.g., AC-2 or AC-2(1))"),
+		),
+		mcp.WithArgument("system",
+			mcp.ArgumentDescription("The name of the system being documented"),
+		),
+	)
+	s.AddPrompt(draftNarrativePrompt, func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		program := request.Params.Arguments["program"]
+		controlID := request.Params.Arguments["controlId"]
+		system := promptSystemName(request.Params.Arguments["system"])
+
+		controlContext, err := promptControlContext(ctx, service, program, controlID)
+		if err != nil {
+			return nil, err
+		}
+
+		instructions := fmt.Sprintf(`Draft an implementation narrative for control %s in %s for %s.
+
+Address every lettered and numbered part of the control statement separately, in order, using the part labels as headings.
+For each part, describe who is responsible, what is done, how it is done (tools, configuration and processes) and how often.
+Where the control has parameters, state the value chosen for the system instead of repeating the placeholder.
+Use the assessment objectives to check that the narrative gives an assessor something concrete to verify.
+Avoid vague language such as "as appropriate" or "periodically"; ask me for specifics you do not know.`,
+			fedramp.ControlLabel(controlID), program, system)
+
+		return mcp.NewGetPromptResult(
+			fmt.Sprintf("Draft an implementation narrative for %s", fedramp.ControlLabel(controlID)),
+			promptMessages(instructions, controlContext),
+		), nil
+	})
+
+	// Prompt: explain_control
+	explainControlPrompt := mcp.NewPrompt("explain_control",
+		mcp.WithPromptDescription("Explain a control in plain language to an engineer"),
+		mcp.WithArgument("program",
+			mcp.RequiredArgument(),
+			mcp.ArgumentDescription("The compliance program (e.g., FedRAMP High, FedRAMP Moderate or a tailored program)"),
+		),
+		mcp.WithArgument("controlId",
+			mcp.RequiredArgument(),
+			mcp.ArgumentDescription("The ID of the control (e.g., AC-2 or AC-2(1))"),
+		),
+	)
+	s.AddPrompt(explainControlPrompt, func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		program := request.Params.Arguments["program"]
+		controlID := request.Params.Arguments["controlId"]
+
+		controlContext, err := promptControlContext(ctx, service, program, controlID)
+		if err != nil {
+			return nil, err
+		}
+
+		instructions := fmt.Sprintf(`Explain control %s from %s to a software engineer who has not worked on compliance before.
+
+Start with a two or three sentence summary of what the control is trying to protect against.
+Then walk through each part of the control statement and describe what it means in practice for a cloud service, including concrete examples of configuration, automation or process that would satisfy it.
+Call out any parameters that the organization must decide on, and finish with the questions an assessor is likely to ask based on the assessment objectives.`,
+			fedramp.ControlLabel(controlID), program)
+
+		return mcp.NewGetPromptResult(
+			fmt.Sprintf("Explain %s to an engineer", fedramp.ControlLabel(controlID)),
+			promptMessages(instructions, controlContext),
+		), nil
+	})
+
+	// Prompt: plan_evidence_collection
+	planEvidencePrompt := mcp.NewPrompt("plan_evidence_collection",
+		mcp.WithPromptDescription("Plan evidence collection for every control in a family"),
+		mcp.WithArgument("program",
+			mcp.RequiredArgument(),
+			mcp.ArgumentDescription("The compliance program (e.g., FedRAMP High, FedRAMP Moderate or a tailored program)"),
+		),
+		mcp.WithArgument("family",
+			mcp.RequiredArgument(),
+			mcp.ArgumentDescription("The control family ID (e.g., AC, AU)"),
+		),
+	)
+	s.AddPrompt(planEvidencePrompt, func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		program := request.Params.Arguments["program"]
+		familyID := request.Params.Arguments["family"]
+
+		familyContext, err := promptFamilyContext(ctx, service, program, familyID)
+		if err != nil {
+			return nil, err
+		}
+
+		instructions := fmt.Sprintf(`Plan evidence collection for the %s control family in %s.
+
+For each control, list the evidence an assessor will expect based on its assessment objectives: documents to examine, people to interview and mechanisms to test.
+For each piece of evidence, suggest where it would come from (for example a policy document, a configuration export, audit logs or a screenshot), who owns it and how often it should be refreshed.
+Group evidence that satisfies several controls so it only needs to be collected once, and flag evidence that can be collected automatically.`,
+			strings.ToUpper(familyID), program)
+
+		return mcp.NewGetPromptResult(
+			fmt.Sprintf("Plan evidence collection for the %s family", strings.ToUpper(familyID)),
+			promptMessages(instructions, familyContext),
+		), nil
+	})
+
+	// Prompt: gap_review
+	gapReviewPrompt := mcp.NewPrompt("gap_review",
+		mcp.WithPromptDescription("Review a system against a program to find compliance gaps"),
+		mcp.WithArgument("program",
+			mcp.RequiredArgument(),
+			mcp.ArgumentDescription("The compliance program (e.g., FedRAMP High, FedRAMP Moderate or a tailored program)"),
+		),
+		mcp.WithArgument("system",
+			mcp.RequiredArgument(),
+			mcp.ArgumentDescription("The name of the system being reviewed"),
+		),
+		mcp.WithArgument("family",
+			mcp.ArgumentDescription("Limit the review to a single control family (e.g., AC)"),
+		),
+	)
+	s.AddPrompt(gapReviewPrompt, func(ctx context.Context, request mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		program := request.Params.Arguments["program"]
+		system := promptSystemName(request.Params.Arguments["system"])
+		familyID := request.Params.Arguments["family"]
+
+		var baselineContext string
+		var err error
+		scope := "every control family"
+		if familyID != "" {
+			baselineContext, err = promptFamilyContext(ctx, service, program, familyID)
+			scope = fmt.Sprintf("the %s control family", strings.ToUpper(familyID))
+		} else {
+			baselineContext, err = promptBaselineContext(ctx, service, program)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		instructions := fmt.Sprintf(`Perform a gap review of %s against %s, covering %s.
+
+Work through the controls below and, for each one, ask me how %s currently meets it unless I have already told you.
+Classify each control as implemented, partially implemented, planned or not implemented, and for anything short of implemented describe the gap and a concrete next step to close it.
+Use the get_control tool to read the full text of any control that is only listed by title.
+Finish with a summary of the largest gaps, ordered by risk.`,
+			system, program, scope, system)
+
+		return mcp.NewGetPromptResult(
+			fmt.Sprintf("Gap review of %s against %s", system, program),
+			promptMessages(instructions, baselineContext),
+		), nil
+	})
+}
+
+// promptMessages builds the messages for a prompt: the instructions followed by the compliance context
+func promptMessages(instructions, complianceContext string) []mcp.PromptMessage {
+	return []mcp.PromptMessage{
+		mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(instructions)),
+		mcp.NewPromptMessage(mcp.RoleUser, mcp.NewTextContent(complianceContext)),
+	}
+}
+
+// promptControlContext returns the full text, parameters and assessment objectives of a control
+func promptControlContext(ctx context.Context, service *fedramp_compliance.Service, program, controlID string) (string, error) {
+	if program == "" || controlID == "" {
+		return "", fmt.Errorf("program and controlId are required")
+	}
+
+	control, err := service.GetControl(ctx, program, controlID)
+	if err != nil {
+		return "", err
+	}
+
+	return controlMarkdown(program, control), nil
+}
+
+// promptFamilyContext returns the full text of every control in a family
+func promptFamilyContext(ctx context.Context, service *fedramp_compliance.Service, program, familyID string) (string, error) {
+	if program == "" || familyID == "" {
+		return "", fmt.Errorf("program and family are required")
+	}
+
+	family, err := service.GetControlFamily(ctx, program, familyID)
+	if err != nil {
+		return "", err
+	}
+
+	var context strings.Builder
+	fmt.Fprintf(&context, "# %s %s (%d controls)\n\n", strings.ToUpper(family.ID), family.Title, len(family.Controls))
+	for _, control := range family.Controls {
+		context.WriteString(controlMarkdown(program, control))
+		context.WriteString("\n")
+	}
+	return context.String(), nil
+}
+
+// promptBaselineContext lists every control in a program by title. The full text of a whole
+// baseline is too large for a prompt, so the agent is expected to fetch controls as it goes.
+func promptBaselineContext(ctx context.Context, service *fedramp_compliance.Service, program string) (string, error) {
+	if program == "" {
+		return "", fmt.Errorf("program is required")
+	}
+
+	families, err := service.ListControlFamilies(ctx, program)
+	if err != nil {
+		return "", fmt.Errorf("failed to list control families: %v", err)
+	}
+
+	var context strings.Builder
+	fmt.Fprintf(&context, "# %s Baseline\n\n", program)
+	for _, family := range families {
+		fmt.Fprintf(&context, "## %s %s\n\n", strings.ToUpper(family.ID), family.Title)
+		for _, control := range family.Controls {
+			fmt.Fprintf(&context, "- %s: %s\n", fedramp.ControlLabel(control.ID), control.Title)
+		}
+		context.WriteString("\n")
+	}
+	return context.String(), nil
+}
+
+// promptSystemName returns the system name to use in a prompt, defaulting to a generic description
+func promptSystemName(system string) string {
+	if strings.TrimSpace(system) == "" {
+		return "our system"
+	}
+	return strings.TrimSpace(system)
+}
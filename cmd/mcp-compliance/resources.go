@@ -0,0 +1,331 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/services/fedramp_compliance"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	// resourceScheme is the URI scheme used for all compliance resources
+	resourceScheme = "compliance://"
+
+	// jsonSuffix selects the JSON representation of a resource instead of Markdown
+	jsonSuffix = ".json"
+
+	markdownMIMEType = "text/markdown"
+	jsonMIMEType     = "application/json"
+)
+
+// complianceResources serves compliance programs, families and controls as MCP resources
+type complianceResources struct {
+	service *fedramp_compliance.Service
+
+	mu       sync.RWMutex
+	programs map[string]string // Listed programs by slug
+}
+
+// addComplianceResources registers compliance resources and resource templates with the MCP server.
+// Every family and control in every program is listed as a concrete resource so that clients can
+// browse them; the templates let clients address any of them directly. The listing is built from
+// the unrestricted catalog at startup and filtered for each caller by filterResources, while reads
+// go through the service as the calling identity. Programs added later are listed with addProgram.
+func addComplianceResources(s *server.MCPServer, service, catalog *fedramp_compliance.Service) (*complianceResources, error) {
+	r := &complianceResources{service: service, programs: make(map[string]string)}
+
+	s.AddResource(mcp.NewResource(resourceScheme+"programs", "Compliance programs",
+		mcp.WithResourceDescription("Available compliance programs and links to their control families"),
+		mcp.WithMIMEType(markdownMIMEType),
+	), r.handleRead)
+	s.AddResource(mcp.NewResource(resourceScheme+"programs"+jsonSuffix, "Compliance programs (JSON)",
+		mcp.WithResourceDescription("Available compliance programs and links to their control families"),
+		mcp.WithMIMEType(jsonMIMEType),
+	), r.handleRead)
+
+	templates := []struct {
+		uriTemplate string
+		name        string
+		description string
+	}{
+		{"{program}/families", "Control families", "Control families in a program (e.g., compliance://fedramp-high/families)"},
+		{"{program}/families/{family}", "Control family", "A control family and its controls (e.g., compliance://fedramp-high/families/ac)"},
+		{"{program}/controls/{control}", "Control", "A single control (e.g., compliance://fedramp-high/controls/ac-2)"},
+	}
+	for _, t := range templates {
+		s.AddResourceTemplate(mcp.NewResourceTemplate(resourceScheme+t.uriTemplate, t.name,
+			mcp.WithTemplateDescription(t.description),
+			mcp.WithTemplateMIMEType(markdownMIMEType),
+		), r.handleRead)
+		s.AddResourceTemplate(mcp.NewResourceTemplate(resourceScheme+t.uriTemplate+jsonSuffix, t.name+" (JSON)",
+			mcp.WithTemplateDescription(t.description+" as JSON"),
+			mcp.WithTemplateMIMEType(jsonMIMEType),
+		), r.handleRead)
+	}
+
+	ctx := context.Background()
+	programs, err := catalog.ListCompliancePrograms(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list compliance programs: %v", err)
+	}
+	sort.Strings(programs)
+
+	for _, program := range programs {
+		if err := r.addProgram(s, catalog, program); err != nil {
+			return nil, err
+		}
+	}
+
+	return r, nil
+}
+
+// addProgram lists the families and controls of a program as resources, e.g. when an overlay
+// adds a tailored program
+func (r *complianceResources) addProgram(s *server.MCPServer, catalog *fedramp_compliance.Service, program string) error {
+	families, err := catalog.ListControlFamilies(context.Background(), program)
+	if err != nil {
+		return fmt.Errorf("failed to list control families for %s: %v", program, err)
+	}
+	r.mu.Lock()
+	r.programs[programSlug(program)] = program
+	r.mu.Unlock()
+
+	s.AddResource(mcp.NewResource(familiesURI(program), fmt.Sprintf("%s: control families", program),
+		mcp.WithResourceDescription(fmt.Sprintf("Control families in %s", program)),
+		mcp.WithMIMEType(markdownMIMEType),
+	), r.handleRead)
+
+	for _, family := range families {
+		s.AddResource(mcp.NewResource(familyURI(program, family.ID),
+			fmt.Sprintf("%s: %s %s", program, strings.ToUpper(family.ID), family.Title),
+			mcp.WithResourceDescription(fmt.Sprintf("%s control family in %s", family.Title, program)),
+			mcp.WithMIMEType(markdownMIMEType),
+		), r.handleRead)
+
+		for _, control := range family.Controls {
+			s.AddResource(mcp.NewResource(controlURI(program, control.ID),
+				fmt.Sprintf("%s: %s %s", program, fedramp.ControlLabel(control.ID), control.Title),
+				mcp.WithResourceDescription(fmt.Sprintf("%s control in %s", control.Title, program)),
+				mcp.WithMIMEType(markdownMIMEType),
+			), r.handleRead)
+		}
+	}
+
+	return nil
+}
+
+// programOf returns the listed program a resource URI belongs to, if any
+func (r *complianceResources) programOf(uri string) (string, bool) {
+	path, ok := strings.CutPrefix(uri, resourceScheme)
+	if !ok {
+		return "", false
+	}
+	slug, _, _ := strings.Cut(path, "/")
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	program, ok := r.programs[slug]
+	return program, ok
+}
+
+// handleRead reads any compliance resource by parsing its URI
+func (r *complianceResources) handleRead(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	uri := request.Params.URI
+	path, ok := strings.CutPrefix(uri, resourceScheme)
+	if !ok {
+		return nil, fmt.Errorf("unsupported resource URI: %s", uri)
+	}
+
+	asJSON := strings.HasSuffix(path, jsonSuffix)
+	path = strings.TrimSuffix(path, jsonSuffix)
+
+	var content any
+	var markdown string
+	var err error
+
+	segments := strings.Split(path, "/")
+	switch {
+	case len(segments) == 1 && segments[0] == "programs":
+		content, markdown, err = r.readPrograms(ctx)
+	case len(segments) == 2 && segments[1] == "families":
+		content, markdown, err = r.readFamilies(ctx, segments[0])
+	case len(segments) == 3 && segments[1] == "families":
+		content, markdown, err = r.readFamily(ctx, segments[0], segments[2])
+	case len(segments) == 3 && segments[1] == "controls":
+		content, markdown, err = r.readControl(ctx, segments[0], segments[2])
+	default:
+		return nil, fmt.Errorf("unsupported resource URI: %s", uri)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if asJSON {
+		data, err := json.MarshalIndent(content, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal resource to JSON: %v", err)
+		}
+		return []mcp.ResourceContents{mcp.TextResourceContents{URI: uri, MIMEType: jsonMIMEType, Text: string(data)}}, nil
+	}
+	return []mcp.ResourceContents{mcp.TextResourceContents{URI: uri, MIMEType: markdownMIMEType, Text: markdown}}, nil
+}
+
+// resourceLink is a reference to another compliance resource
+type resourceLink struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	URI   string `json:"uri"`
+}
+
+// readPrograms returns the list of compliance programs
+func (r *complianceResources) readPrograms(ctx context.Context) (any, string, error) {
+	programs, err := r.service.ListCompliancePrograms(ctx)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list compliance programs: %v", err)
+	}
+	sort.Strings(programs)
+
+	links := make([]resourceLink, 0, len(programs))
+	var md strings.Builder
+	md.WriteString("# Compliance Programs\n\n")
+	for _, program := range programs {
+		link := resourceLink{ID: programSlug(program), Title: program, URI: familiesURI(program)}
+		links = append(links, link)
+		fmt.Fprintf(&md, "- [%s](%s)\n", link.Title, link.URI)
+	}
+
+	return links, md.String(), nil
+}
+
+// readFamilies returns the control families in a program
+func (r *complianceResources) readFamilies(ctx context.Context, slug string) (any, string, error) {
+	program, err := r.resolveProgram(ctx, slug)
+	if err != nil {
+		return nil, "", err
+	}
+
+	families, err := r.service.ListControlFamilies(ctx, program)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list control families: %v", err)
+	}
+
+	links := make([]resourceLink, 0, len(families))
+	var md strings.Builder
+	fmt.Fprintf(&md, "# %s Control Families\n\n", program)
+	md.WriteString("| Family | Title | Controls |\n|---|---|---|\n")
+	for _, family := range families {
+		link := resourceLink{ID: family.ID, Title: family.Title, URI: familyURI(program, family.ID)}
+		links = append(links, link)
+		fmt.Fprintf(&md, "| [%s](%s) | %s | %d |\n", strings.ToUpper(family.ID), link.URI, family.Title, len(family.Controls))
+	}
+
+	return links, md.String(), nil
+}
+
+// readFamily returns a control family with links to its controls
+func (r *complianceResources) readFamily(ctx context.Context, slug, familyID string) (any, string, error) {
+	program, err := r.resolveProgram(ctx, slug)
+	if err != nil {
+		return nil, "", err
+	}
+
+	family, err := r.service.GetControlFamily(ctx, program, familyID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	content := struct {
+		ID       string         `json:"id"`
+		Title    string         `json:"title"`
+		Program  string         `json:"program"`
+		Controls []resourceLink `json:"controls"`
+	}{
+		ID:       family.ID,
+		Title:    family.Title,
+		Program:  program,
+		Controls: make([]resourceLink, 0, len(family.Controls)),
+	}
+
+	var md strings.Builder
+	fmt.Fprintf(&md, "# %s %s\n\n", strings.ToUpper(family.ID), family.Title)
+	fmt.Fprintf(&md, "**Program:** %s\n\n", program)
+	md.WriteString("| Control | Title |\n|---|---|\n")
+	for _, control := range family.Controls {
+		link := resourceLink{ID: control.ID, Title: control.Title, URI: controlURI(program, control.ID)}
+		content.Controls = append(content.Controls, link)
+		fmt.Fprintf(&md, "| [%s](%s) | %s |\n", fedramp.ControlLabel(control.ID), link.URI, control.Title)
+	}
+
+	return content, md.String(), nil
+}
+
+// readControl returns a single control
+func (r *complianceResources) readControl(ctx context.Context, slug, controlID string) (any, string, error) {
+	program, err := r.resolveProgram(ctx, slug)
+	if err != nil {
+		return nil, "", err
+	}
+
+	control, err := r.service.GetControl(ctx, program, controlID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return control, controlMarkdown(program, control), nil
+}
+
+// resolveProgram finds the program whose slug matches the one used in a resource URI
+func (r *complianceResources) resolveProgram(ctx context.Context, slug string) (string, error) {
+	return resolveProgramSlug(ctx, r.service, slug)
+}
+
+// resolveProgramSlug finds the program whose slug matches the one used in a resource URI
+func resolveProgramSlug(ctx context.Context, service *fedramp_compliance.Service, slug string) (string, error) {
+	programs, err := service.ListCompliancePrograms(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to list compliance programs: %v", err)
+	}
+
+	slugs := make([]string, 0, len(programs))
+	for _, program := range programs {
+		if programSlug(program) == strings.ToLower(slug) {
+			return program, nil
+		}
+		slugs = append(slugs, programSlug(program))
+	}
+	return "", fedramp.NewError(fedramp.ErrProgramUnavailable, "program not found: %s", slug).WithSuggestions(slugs...)
+}
+
+// controlMarkdown renders a control as Markdown
+func controlMarkdown(program string, control fedramp.Control) string {
+	d := fedramp.NewDocument(fedramp.FormatMarkdown)
+	d.Control(control, 1, nil)
+	d.Field("Program", program)
+	return d.String()
+}
+
+// programSlug converts a program name into the identifier used in resource URIs (e.g., fedramp-high)
+func programSlug(program string) string {
+	return strings.ToLower(strings.Join(strings.Fields(program), "-"))
+}
+
+// familiesURI returns the URI of the control family list for a program
+func familiesURI(program string) string {
+	return fmt.Sprintf("%s%s/families", resourceScheme, programSlug(program))
+}
+
+// familyURI returns the URI of a control family
+func familyURI(program, familyID string) string {
+	return fmt.Sprintf("%s%s/families/%s", resourceScheme, programSlug(program), fedramp.NormalizeFamilyID(familyID))
+}
+
+// controlURI returns the URI of a control
+func controlURI(program, controlID string) string {
+	return fmt.Sprintf("%s%s/controls/%s", resourceScheme, programSlug(program), fedramp.NormalizeControlID(controlID))
+}
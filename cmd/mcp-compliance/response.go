@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// responseBudget limits the size of tool responses so that large results do not overflow
+// an agent's context window
+type responseBudget struct {
+	maxBytes int
+}
+
+// renderPage renders a page of results. If the response exceeds the budget, items are dropped
+// from the end of the page and the continuation cursor is moved back so the caller can fetch
+// them with the next request. At least one item is always returned so that callers can make
+// progress through the result set.
+func renderPage[T any](budget responseBudget, page fedramp.Page[T], render func(fedramp.Page[T]) (string, error)) (string, error) {
+	text, err := render(page)
+	if err != nil || budget.maxBytes <= 0 || len(text) <= budget.maxBytes || len(page.Items) <= 1 {
+		return text, err
+	}
+
+	// Binary search for the largest number of items that fits within the budget
+	low, high := 1, len(page.Items)-1
+	for low < high {
+		mid := (low + high + 1) / 2
+		candidate, err := render(page.Truncate(mid))
+		if err != nil {
+			return "", err
+		}
+		if len(candidate) <= budget.maxBytes {
+			low = mid
+		} else {
+			high = mid - 1
+		}
+	}
+
+	return render(page.Truncate(low))
+}
+
+// formattedResult returns a value as indented JSON, or as the document written by render for
+// the Markdown and text formats
+func formattedResult(format fedramp.Format, v any, render func(d *fedramp.Document)) (*mcp.CallToolResult, error) {
+	if format == fedramp.FormatJSON {
+		return jsonResult(v)
+	}
+	d := fedramp.NewDocument(format)
+	render(d)
+	return mcp.NewToolResultText(d.String()), nil
+}
+
+// formattedPage returns a page of results within the response budget, either as the indented
+// JSON of the value built by wrap, or as the document written by render for the Markdown and
+// text formats. Rendered documents end with a summary of where the page is in the result set.
+func formattedPage[T any](budget responseBudget, format fedramp.Format, page fedramp.Page[T], wrap func(fedramp.Page[T]) (any, error), render func(d *fedramp.Document, p fedramp.Page[T])) (*mcp.CallToolResult, error) {
+	text, err := renderPage(budget, page, func(p fedramp.Page[T]) (string, error) {
+		if format == fedramp.FormatJSON {
+			v, err := wrap(p)
+			if err != nil {
+				return "", err
+			}
+			data, err := json.MarshalIndent(v, "", "  ")
+			if err != nil {
+				return "", fedramp.WrapError(fedramp.ErrInternal, err, "failed to marshal page to JSON")
+			}
+			return string(data), nil
+		}
+
+		d := fedramp.NewDocument(format)
+		render(d, p)
+		d.PageSummary(p.Offset, len(p.Items), p.Total, p.NextCursor)
+		return d.String(), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mcp.NewToolResultText(text), nil
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
+)
+
+func TestRenderPage(t *testing.T) {
+	items := []string{"aaaa", "bbbb", "cccc", "dddd", "eeee", "ffff"}
+	page, err := fedramp.Paginate(items, fedramp.PageRequest{Cursor: fedramp.EncodeCursor(1), PageSize: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	render := func(p fedramp.Page[string]) (string, error) {
+		return strings.Join(p.Items, ","), nil
+	}
+
+	tests := []struct {
+		name       string
+		maxBytes   int
+		want       string
+		wantOffset int // of the next cursor
+	}{
+		{"within the budget", 100, "bbbb,cccc,dddd,eeee", 5},
+		{"no budget", 0, "bbbb,cccc,dddd,eeee", 5},
+		{"over the budget", 14, "bbbb,cccc,dddd", 4},
+		{"exactly the budget", 9, "bbbb,cccc", 3},
+		// At least one item is returned, so that callers make progress
+		{"budget below one item", 2, "bbbb", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rendered fedramp.Page[string]
+			text, err := renderPage(responseBudget{maxBytes: tt.maxBytes}, page, func(p fedramp.Page[string]) (string, error) {
+				rendered = p
+				return render(p)
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if text != tt.want {
+				t.Errorf("renderPage() = %q, want %q", text, tt.want)
+			}
+			// The continuation cursor resumes after the last item returned
+			if offset, err := fedramp.DecodeCursor(rendered.NextCursor); err != nil || offset != tt.wantOffset {
+				t.Errorf("renderPage() next cursor offset = %d, %v, want %d", offset, err, tt.wantOffset)
+			}
+		})
+	}
+}
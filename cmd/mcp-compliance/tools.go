@@ -0,0 +1,501 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/services/fedramp_compliance"
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/services/fedramp_implementation"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// maxControlSelectors is the largest number of control IDs and ranges get_controls accepts in one call
+const maxControlSelectors = 100
+
+// addComplianceTools adds all compliance-related tools to the MCP server. Controls are read with a
+// system's parameter values from the implementation service when a system is given.
+func addComplianceTools(s *server.MCPServer, service *fedramp_compliance.Service, implementations *fedramp_implementation.Service, budget responseBudget) {
+	// Tool: list_compliance_programs
+	listProgramsTool := mcp.NewTool("list_compliance_programs",
+		mcp.WithDescription("List all available compliance programs"),
+		withFormat(),
+	)
+	s.AddTool(listProgramsTool, toolHandler(listProgramsTool, func(ctx context.Context, args formatArguments) (*mcp.CallToolResult, error) {
+		format, err := args.format()
+		if err != nil {
+			return nil, err
+		}
+
+		programs, err := service.ListCompliancePrograms(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		return formattedResult(format, programs, func(d *fedramp.Document) {
+			d.Heading(1, "Compliance Programs")
+			for _, program := range programs {
+				d.Item(0, "", program)
+			}
+			d.EndList()
+		})
+	}))
+
+	// Tool: get_control
+	getControlTool := mcp.NewTool("get_control",
+		mcp.WithDescription("Get detailed information about a specific control"),
+		mcp.WithString("program",
+			mcp.Required(),
+			mcp.Description("The compliance program (e.g., FedRAMP High, FedRAMP Moderate or a tailored program)"),
+		),
+		mcp.WithString("controlId",
+			mcp.Required(),
+			mcp.Description("The ID of the control (e.g., AC-1, IA-2 or AC-2(1))"),
+		),
+		mcp.WithString("system",
+			mcp.Description("Show the parameter values this system assigns in place of the program's"),
+		),
+		withFormat(),
+	)
+	s.AddTool(getControlTool, toolHandler(getControlTool, func(ctx context.Context, args struct {
+		controlArguments
+		systemArguments
+		formatArguments
+	}) (*mcp.CallToolResult, error) {
+		format, err := args.format()
+		if err != nil {
+			return nil, err
+		}
+
+		control, err := service.GetControl(ctx, args.Program, args.ControlID)
+		if err != nil {
+			return nil, err
+		}
+		if args.System != "" {
+			if control, err = implementations.AssignParameters(ctx, args.System, args.Program, control); err != nil {
+				return nil, err
+			}
+		}
+
+		// Explain where the requirements of a withdrawn control went
+		type ControlResponse struct {
+			fedramp.Control
+			Notice string `json:"notice,omitempty"`
+		}
+
+		return formattedResult(format, ControlResponse{Control: control, Notice: control.WithdrawnNotice()}, func(d *fedramp.Document) {
+			d.Control(control, 1, nil)
+		})
+	}))
+
+	// Tool: get_controls
+	getControlsTool := mcp.NewTool("get_controls",
+		mcp.WithDescription("Get several controls in one call by ID, range or wildcard. Controls that do not exist are listed under unknown."),
+		mcp.WithString("program",
+			mcp.Required(),
+			mcp.Description("The compliance program (e.g., FedRAMP High, FedRAMP Moderate or a tailored program)"),
+		),
+		mcp.WithArray("controlIds",
+			mcp.Required(),
+			mcp.Description("Control IDs (e.g., AC-1 or AC-2(1)), ranges within a family (e.g., AC-2 through AC-6), a control and its enhancements (e.g., AC-2(*)) or a whole family (e.g., AC-*)"),
+			mcp.WithStringItems(),
+			mcp.MinItems(1),
+			mcp.MaxItems(maxControlSelectors),
+		),
+		withIncludeWithdrawn(),
+		withPagination(),
+		withFields(),
+		withFormat(),
+	)
+	s.AddTool(getControlsTool, toolHandler(getControlsTool, func(ctx context.Context, args struct {
+		programArguments
+		ControlIDs []string `json:"controlIds"`
+		withdrawnArguments
+		paginationArguments
+		fieldsArguments
+		formatArguments
+	}) (*mcp.CallToolResult, error) {
+		pageRequest, err := args.pageRequest()
+		if err != nil {
+			return nil, err
+		}
+		fields, err := args.fields(nil)
+		if err != nil {
+			return nil, err
+		}
+		format, err := args.format()
+		if err != nil {
+			return nil, err
+		}
+
+		selection, err := service.GetControls(ctx, args.Program, args.ControlIDs, args.queryOptions()...)
+		if err != nil {
+			return nil, err
+		}
+
+		page, err := fedramp.Paginate(selection.Controls, pageRequest)
+		if err != nil {
+			return nil, err
+		}
+
+		// Create a response with the requested page of controls and the IDs that matched nothing
+		type ControlsPage struct {
+			fedramp.Page[map[string]any]
+			Unknown []string `json:"unknown"`
+		}
+
+		return formattedPage(budget, format, page, func(p fedramp.Page[fedramp.Control]) (any, error) {
+			projected, err := projectPage(p, fields)
+			return ControlsPage{Page: projected, Unknown: selection.Unknown}, err
+		}, func(d *fedramp.Document, p fedramp.Page[fedramp.Control]) {
+			d.Heading(1, "Controls in "+args.Program)
+			if len(selection.Unknown) > 0 {
+				d.Field("Unknown", strings.Join(selection.Unknown, ", "))
+			}
+			d.Controls(p.Items, 2, fields)
+		})
+	}))
+
+	// Tool: get_control_family
+	getControlFamilyTool := mcp.NewTool("get_control_family",
+		mcp.WithDescription("Get all controls in a family (e.g., AC for Access Control)"),
+		mcp.WithString("program",
+			mcp.Required(),
+			mcp.Description("The compliance program (e.g., FedRAMP High, FedRAMP Moderate or a tailored program)"),
+		),
+		mcp.WithString("family",
+			mcp.Required(),
+			mcp.Description("The control family ID (e.g., AC, IA)"),
+		),
+		withIncludeWithdrawn(),
+		withPagination(),
+		withFields(),
+		withFormat(),
+	)
+	s.AddTool(getControlFamilyTool, toolHandler(getControlFamilyTool, func(ctx context.Context, args struct {
+		familyArguments
+		withdrawnArguments
+		paginationArguments
+		fieldsArguments
+		formatArguments
+	}) (*mcp.CallToolResult, error) {
+		pageRequest, err := args.pageRequest()
+		if err != nil {
+			return nil, err
+		}
+		fields, err := args.fields(nil)
+		if err != nil {
+			return nil, err
+		}
+		format, err := args.format()
+		if err != nil {
+			return nil, err
+		}
+
+		family, err := service.GetControlFamily(ctx, args.Program, args.Family, args.queryOptions()...)
+		if err != nil {
+			return nil, err
+		}
+
+		page, err := fedramp.Paginate(family.Controls, pageRequest)
+		if err != nil {
+			return nil, err
+		}
+
+		// Create a response with the family details and the requested page of controls
+		type FamilyPage struct {
+			ID    string `json:"id"`
+			Title string `json:"title"`
+			fedramp.Page[map[string]any]
+		}
+
+		return formattedPage(budget, format, page, func(p fedramp.Page[fedramp.Control]) (any, error) {
+			projected, err := projectPage(p, fields)
+			return FamilyPage{ID: family.ID, Title: family.Title, Page: projected}, err
+		}, func(d *fedramp.Document, p fedramp.Page[fedramp.Control]) {
+			d.Heading(1, strings.ToUpper(family.ID)+" "+family.Title)
+			d.Controls(p.Items, 2, fields)
+		})
+	}))
+
+	// Tool: list_control_families
+	listControlFamiliesTool := mcp.NewTool("list_control_families",
+		mcp.WithDescription("List all control families in a program"),
+		mcp.WithString("program",
+			mcp.Required(),
+			mcp.Description("The compliance program (e.g., FedRAMP High, FedRAMP Moderate or a tailored program)"),
+		),
+		withIncludeWithdrawn(),
+		withPagination(),
+		withFormat(),
+	)
+	s.AddTool(listControlFamiliesTool, toolHandler(listControlFamiliesTool, func(ctx context.Context, args struct {
+		programArguments
+		withdrawnArguments
+		paginationArguments
+		formatArguments
+	}) (*mcp.CallToolResult, error) {
+		pageRequest, err := args.pageRequest()
+		if err != nil {
+			return nil, err
+		}
+		format, err := args.format()
+		if err != nil {
+			return nil, err
+		}
+
+		families, err := service.ListControlFamilies(ctx, args.Program, args.queryOptions()...)
+		if err != nil {
+			return nil, err
+		}
+
+		// Create a simplified response with just family ID, title, and control count
+		type SimplifiedFamily struct {
+			ID           string `json:"id"`
+			Title        string `json:"title"`
+			ControlCount int    `json:"controlCount"`
+		}
+
+		simplifiedFamilies := make([]SimplifiedFamily, 0, len(families))
+		for _, family := range families {
+			simplifiedFamilies = append(simplifiedFamilies, SimplifiedFamily{
+				ID:           family.ID,
+				Title:        family.Title,
+				ControlCount: len(family.Controls),
+			})
+		}
+
+		page, err := fedramp.Paginate(simplifiedFamilies, pageRequest)
+		if err != nil {
+			return nil, err
+		}
+
+		// Format the result as JSON
+		return formattedPage(budget, format, page, func(p fedramp.Page[SimplifiedFamily]) (any, error) {
+			return p, nil
+		}, func(d *fedramp.Document, p fedramp.Page[SimplifiedFamily]) {
+			d.Heading(1, "Control Families in "+args.Program)
+			for _, family := range p.Items {
+				d.Item(0, strings.ToUpper(family.ID), fmt.Sprintf("%s (%d controls)", family.Title, family.ControlCount))
+			}
+			d.EndList()
+		})
+	}))
+
+	// Tool: search_controls
+	searchControlsTool := mcp.NewTool("search_controls",
+		mcp.WithDescription("Search for controls by keyword"),
+		mcp.WithString("program",
+			mcp.Required(),
+			mcp.Description("The compliance program (e.g., FedRAMP High, FedRAMP Moderate or a tailored program)"),
+		),
+		mcp.WithString("query",
+			mcp.Required(),
+			mcp.Description("The search query"),
+		),
+		withIncludeWithdrawn(),
+		withPagination(),
+		withFields(),
+		withFormat(),
+	)
+	s.AddTool(searchControlsTool, toolHandler(searchControlsTool, func(ctx context.Context, args struct {
+		programArguments
+		Query string `json:"query"`
+		withdrawnArguments
+		paginationArguments
+		fieldsArguments
+		formatArguments
+	}) (*mcp.CallToolResult, error) {
+		pageRequest, err := args.pageRequest()
+		if err != nil {
+			return nil, err
+		}
+		// Search results default to just the control ID and title
+		fields, err := args.fields([]string{"id", "title"})
+		if err != nil {
+			return nil, err
+		}
+		format, err := args.format()
+		if err != nil {
+			return nil, err
+		}
+
+		controls, err := service.SearchControls(ctx, args.Program, args.Query, args.queryOptions()...)
+		if err != nil {
+			return nil, err
+		}
+
+		page, err := fedramp.Paginate(controls, pageRequest)
+		if err != nil {
+			return nil, err
+		}
+
+		return formattedPage(budget, format, page, func(p fedramp.Page[fedramp.Control]) (any, error) {
+			return projectPage(p, fields)
+		}, func(d *fedramp.Document, p fedramp.Page[fedramp.Control]) {
+			d.Heading(1, fmt.Sprintf("Controls matching %q in %s", args.Query, args.Program))
+			d.Controls(p.Items, 2, fields)
+		})
+	}))
+
+	// Tool: get_control_evidence_guidance
+	getControlEvidenceGuidanceTool := mcp.NewTool("get_control_evidence_guidance",
+		mcp.WithDescription("Get the evidence expected for each determination statement of a control: assessment methods (EXAMINE, INTERVIEW, TEST), suggested artifact types and assessment objects"),
+		mcp.WithString("program",
+			mcp.Required(),
+			mcp.Description("The compliance program (e.g., FedRAMP High, FedRAMP Moderate or a tailored program)"),
+		),
+		mcp.WithString("controlId",
+			mcp.Required(),
+			mcp.Description("The ID of the control (e.g., AC-1, IA-2 or AC-2(1))"),
+		),
+		withFormat(),
+	)
+	s.AddTool(getControlEvidenceGuidanceTool, toolHandler(getControlEvidenceGuidanceTool, func(ctx context.Context, args struct {
+		controlArguments
+		formatArguments
+	}) (*mcp.CallToolResult, error) {
+		format, err := args.format()
+		if err != nil {
+			return nil, err
+		}
+
+		guidance, err := service.GetControlEvidenceGuidance(ctx, args.Program, args.ControlID)
+		if err != nil {
+			return nil, err
+		}
+
+		// Create a response structure
+		response := struct {
+			ControlID string `json:"controlId"`
+			Program   string `json:"program"`
+			fedramp.EvidenceGuidance
+		}{
+			ControlID:        fedramp.NormalizeControlID(args.ControlID),
+			Program:          args.Program,
+			EvidenceGuidance: guidance,
+		}
+
+		return formattedResult(format, response, func(d *fedramp.Document) {
+			d.Heading(1, "Evidence Guidance for "+fedramp.ControlLabel(args.ControlID))
+			d.Field("Program", args.Program)
+			d.EvidenceGuidance(guidance)
+		})
+	}))
+}
+
+// programArguments identifies the program a tool operates on
+type programArguments struct {
+	Program string `json:"program"`
+}
+
+// controlArguments identifies a control in a program
+type controlArguments struct {
+	programArguments
+	ControlID string `json:"controlId"`
+}
+
+// familyArguments identifies a control family in a program
+type familyArguments struct {
+	programArguments
+	Family string `json:"family"`
+}
+
+// jsonResult formats a value as an indented JSON tool result
+func jsonResult(v any) (*mcp.CallToolResult, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, fedramp.WrapError(fedramp.ErrInternal, err, "failed to marshal result to JSON")
+	}
+	return mcp.NewToolResultText(string(data)), nil
+}
+
+// withPagination adds the cursor and pageSize arguments used by list tools
+func withPagination() mcp.ToolOption {
+	return func(t *mcp.Tool) {
+		mcp.WithString("cursor",
+			mcp.Description("Continuation cursor returned as nextCursor by a previous call"),
+		)(t)
+		mcp.WithNumber("pageSize",
+			mcp.Description(fmt.Sprintf("Number of items to return (default %d, maximum %d)", fedramp.DefaultPageSize, fedramp.MaxPageSize)),
+			integer(),
+			mcp.Min(1),
+			mcp.Max(fedramp.MaxPageSize),
+		)(t)
+	}
+}
+
+// integer declares a number argument as an integer, so that calls with fractional values are
+// rejected when their arguments are bound
+func integer() mcp.PropertyOption {
+	return func(schema map[string]any) {
+		schema["type"] = "integer"
+	}
+}
+
+// withFields adds the fields projection argument used by tools that return controls
+func withFields() mcp.ToolOption {
+	return mcp.WithString("fields",
+		mcp.Description(fmt.Sprintf("Comma-separated list of control fields to return (%s)", strings.Join(fedramp.ControlFields, ", "))),
+	)
+}
+
+// withIncludeWithdrawn adds the argument used by listing tools to return withdrawn controls
+func withIncludeWithdrawn() mcp.ToolOption {
+	return mcp.WithBoolean("includeWithdrawn",
+		mcp.Description("Also return controls that have been withdrawn from the catalog (default false)"),
+	)
+}
+
+// withFormat adds the format argument used by every tool
+func withFormat() mcp.ToolOption {
+	return mcp.WithString("format",
+		mcp.Description("Response format: json (default), markdown or text"),
+		mcp.Enum(fedramp.Formats...),
+	)
+}
+
+// programArgumentNames are the tool arguments that name a compliance program
+var programArgumentNames = []string{"program", "base", "addFrom"}
+
+// setProgramChoices offers the available programs as the choices of every tool argument that names
+// a program. It runs when the server starts and again whenever an overlay adds a tailored program,
+// and clients that listen for changes to the tool list are notified. filterTools narrows the
+// choices to the programs each caller may use. Tool handlers keep the schemas they were created
+// with, so unknown programs are reported by the services with suggestions.
+func setProgramChoices(s *server.MCPServer, programs []string) {
+	var updated []server.ServerTool
+	for _, tool := range s.ListTools() {
+		properties := maps.Clone(tool.Tool.InputSchema.Properties)
+		changed := false
+		for _, name := range programArgumentNames {
+			property, ok := properties[name].(map[string]any)
+			if !ok {
+				continue
+			}
+			property = maps.Clone(property)
+			property["enum"] = slices.Clone(programs)
+			properties[name] = property
+			changed = true
+		}
+		if changed {
+			tool.Tool.InputSchema.Properties = properties
+			updated = append(updated, *tool)
+		}
+	}
+	if len(updated) > 0 {
+		s.AddTools(updated...)
+	}
+}
+
+// projectPage applies the field projection to the controls on a page
+func projectPage(page fedramp.Page[fedramp.Control], fields []string) (fedramp.Page[map[string]any], error) {
+	return fedramp.MapPage(page, func(controls []fedramp.Control) ([]map[string]any, error) {
+		return fedramp.ProjectControls(controls, fields)
+	})
+}
@@ -1,20 +1,38 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"os"
 
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
 	"github.com/grafana/hackathon-12-mcp-compliance/internal/services/fedramp_compliance"
 )
 
 func main() {
+	// Define command-line flags
+	programFlag := flag.String("program", "FedRAMP High", "Program to query (e.g., FedRAMP High, FedRAMP Moderate)")
+	controlFlag := flag.String("control", "AC-1", "Control to get, in any common notation (e.g., AC-2, AC-2(1), ac-2.1)")
+	familyFlag := flag.String("family", "AC", "Control family to get (e.g., AC, AU)")
+	queryFlag := flag.String("query", "access", "Keyword to search for")
+	flag.Parse()
+
+	// Validate the control ID up front so that a typo is reported before any lookups
+	parsedControlID, err := fedramp.ParseControlID(*controlFlag)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+
 	// Create the compliance service
 	service := fedramp_compliance.NewService()
+	ctx := context.Background()
 
 	// List available programs
 	fmt.Println("Available compliance programs:")
-	programs, err := service.ListCompliancePrograms()
+	programs, err := service.ListCompliancePrograms(ctx)
 	if err != nil {
 		fmt.Printf("Error listing programs: %v\n", err)
 		os.Exit(1)
@@ -25,39 +43,31 @@ func main() {
 	fmt.Println()
 
 	// Get a control
-	programName := "FedRAMP High"
-	controlID := "ac-1"
-	fmt.Printf("Getting control %s from %s:\n", controlID, programName)
-	control, found, err := service.GetControl(programName, controlID)
+	programName := *programFlag
+	controlID := parsedControlID.String()
+	fmt.Printf("Getting control %s from %s:\n", parsedControlID.Label(), programName)
+	control, err := service.GetControl(ctx, programName, controlID)
 	if err != nil {
 		fmt.Printf("Error getting control: %v\n", err)
 		os.Exit(1)
 	}
-	if !found {
-		fmt.Printf("Control %s not found in %s\n", controlID, programName)
-		os.Exit(1)
-	}
 	printJSON("Control", control)
 	fmt.Println()
 
 	// Get a control family
-	familyID := "ac"
+	familyID := fedramp.NormalizeFamilyID(*familyFlag)
 	fmt.Printf("Getting control family %s from %s:\n", familyID, programName)
-	family, found, err := service.GetControlFamily(programName, familyID)
+	family, err := service.GetControlFamily(ctx, programName, familyID)
 	if err != nil {
 		fmt.Printf("Error getting control family: %v\n", err)
 		os.Exit(1)
 	}
-	if !found {
-		fmt.Printf("Control family %s not found in %s\n", familyID, programName)
-		os.Exit(1)
-	}
 	fmt.Printf("Family: %s - %s (%d controls)\n", family.ID, family.Title, len(family.Controls))
 	fmt.Println()
 
 	// List control families
 	fmt.Printf("Listing control families in %s:\n", programName)
-	families, err := service.ListControlFamilies(programName)
+	families, err := service.ListControlFamilies(ctx, programName)
 	if err != nil {
 		fmt.Printf("Error listing control families: %v\n", err)
 		os.Exit(1)
@@ -68,9 +78,9 @@ func main() {
 	fmt.Println()
 
 	// Search for controls
-	query := "access"
+	query := *queryFlag
 	fmt.Printf("Searching for controls with keyword '%s' in %s:\n", query, programName)
-	results, err := service.SearchControls(programName, query)
+	results, err := service.SearchControls(ctx, programName, query)
 	if err != nil {
 		fmt.Printf("Error searching controls: %v\n", err)
 		os.Exit(1)
@@ -82,17 +92,13 @@ func main() {
 	fmt.Println()
 
 	// Get control evidence guidance
-	fmt.Printf("Getting evidence guidance for control %s in %s:\n", controlID, programName)
-	guidance, found, err := service.GetControlEvidenceGuidance(programName, controlID)
+	fmt.Printf("Getting evidence guidance for control %s in %s:\n", parsedControlID.Label(), programName)
+	guidance, err := service.GetControlEvidenceGuidance(ctx, programName, controlID)
 	if err != nil {
 		fmt.Printf("Error getting evidence guidance: %v\n", err)
 		os.Exit(1)
 	}
-	if !found {
-		fmt.Printf("Control %s not found in %s\n", controlID, programName)
-		os.Exit(1)
-	}
-	fmt.Printf("Evidence Guidance:\n%s\n", guidance)
+	printJSON("Evidence Guidance", guidance)
 }
 
 // Helper function to print JSON
@@ -2,7 +2,7 @@ package adapters
 
 import (
 	"encoding/json"
-	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
@@ -31,6 +31,7 @@ func (r *EmbeddedComplianceRepository) ListPrograms() ([]string, error) {
 	for program := range r.programFiles {
 		programs = append(programs, program)
 	}
+	sort.Strings(programs)
 	return programs, nil
 }
 
@@ -49,7 +50,9 @@ func (r *EmbeddedComplianceRepository) LoadProgram(programName string) (fedramp.
 		}
 
 		if !ok {
-			return fedramp.Program{}, fmt.Errorf("program not found: %s", programName)
+			programs, _ := r.ListPrograms()
+			return fedramp.Program{}, fedramp.NewError(fedramp.ErrProgramUnavailable, "program not found: %s", programName).
+				WithSuggestions(programs...)
 		}
 	}
 
@@ -58,14 +61,14 @@ func (r *EmbeddedComplianceRepository) LoadProgram(programName string) (fedramp.
 	if err != nil {
 		// If the file doesn't exist in the embedded FS, it might not have been processed yet
 		// In this case, return a more helpful error message
-		return fedramp.Program{}, fmt.Errorf("program data file not found: %s (run 'make run-fedramp-data-%s' to generate it)",
+		return fedramp.Program{}, fedramp.NewError(fedramp.ErrProgramUnavailable, "program data file not found: %s (run 'make run-fedramp-data-%s' to generate it)",
 			filePath, strings.ToLower(strings.Split(programName, " ")[1]))
 	}
 
 	// Unmarshal the JSON data
 	var program fedramp.Program
 	if err := json.Unmarshal(data, &program); err != nil {
-		return fedramp.Program{}, fmt.Errorf("failed to parse program data: %v", err)
+		return fedramp.Program{}, fedramp.WrapError(fedramp.ErrInternal, err, "failed to parse program data")
 	}
 
 	return program, nil
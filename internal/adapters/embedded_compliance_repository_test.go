@@ -0,0 +1,49 @@
+package adapters
+
+import (
+	"testing"
+
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
+)
+
+// hasEnhancements reports whether the program lists any control enhancement
+func hasEnhancements(program fedramp.Program) bool {
+	for _, family := range program.Families {
+		for _, control := range family.Controls {
+			if id, err := fedramp.ParseControlID(control.ID); err == nil && id.IsEnhancement() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func TestEmbeddedProgramEnhancements(t *testing.T) {
+	repo := NewEmbeddedComplianceRepository()
+	programs, err := repo.ListPrograms()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range programs {
+		t.Run(name, func(t *testing.T) {
+			program, err := repo.LoadProgram(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !hasEnhancements(program) {
+				t.Skip("the embedded data predates enhancement parsing; run 'make run-fedramp-data-high run-fedramp-data-moderate' to regenerate it")
+			}
+
+			for _, id := range []string{"ac-2.1", "AC-2(1)"} {
+				control, ok := program.FindControl(id)
+				if !ok {
+					t.Fatalf("%s not found", id)
+				}
+				if control.ID != "ac-2.1" || control.Title == "" || control.FullText == "" {
+					t.Errorf("%s = %+v, want ac-2.1 with its title and statement", id, control)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package adapters
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
+)
+
+// lockRetryInterval is how often a file lock held by another process is tried again
+const lockRetryInterval = 10 * time.Millisecond
+
+// fileLock serializes the calls that read and write a file, between the goroutines of a process
+// and, with an advisory lock on a lock file next to it, between processes such as the CLI and the
+// MCP server. Unlike a sync.Mutex, callers stop waiting for it once their context is done.
+type fileLock struct {
+	path string        // The lock file, created on first use
+	held chan struct{} // Held by the goroutine that holds the lock in this process
+	file *os.File      // The open lock file while the lock is held
+}
+
+// newFileLock creates an unlocked lock that locks the file at a path
+func newFileLock(path string) *fileLock {
+	return &fileLock{path: path, held: make(chan struct{}, 1)}
+}
+
+// lock waits for the lock, returning an error instead if the context is done first
+func (l *fileLock) lock(ctx context.Context, what string) error {
+	if err := contextError(ctx, what); err != nil {
+		return err
+	}
+	select {
+	case l.held <- struct{}{}:
+	case <-ctx.Done():
+		return contextError(ctx, what)
+	}
+
+	file, err := l.open(what)
+	if err != nil {
+		<-l.held
+		return err
+	}
+	for {
+		locked, err := tryLockFile(file)
+		if err != nil {
+			file.Close()
+			<-l.held
+			return fedramp.WrapError(fedramp.ErrInternal, err, "failed to lock the %s", what)
+		}
+		if locked {
+			l.file = file
+			return nil
+		}
+		select {
+		case <-time.After(lockRetryInterval):
+		case <-ctx.Done():
+			file.Close()
+			<-l.held
+			return contextError(ctx, what)
+		}
+	}
+}
+
+// open opens the lock file, creating it and its directory if needed
+func (l *fileLock) open(what string) (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return nil, fedramp.WrapError(fedramp.ErrInternal, err, "failed to create %s", filepath.Dir(l.path))
+	}
+	file, err := os.OpenFile(l.path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, fedramp.WrapError(fedramp.ErrInternal, err, "failed to lock the %s", what)
+	}
+	return file, nil
+}
+
+// unlock releases the lock
+func (l *fileLock) unlock() {
+	unlockFile(l.file)
+	l.file.Close()
+	l.file = nil
+	<-l.held
+}
@@ -0,0 +1,14 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package adapters
+
+import "os"
+
+// tryLockFile reports the lock as taken. Files are not locked between processes on this platform,
+// only between the goroutines of a process.
+func tryLockFile(*os.File) (bool, error) {
+	return true, nil
+}
+
+// unlockFile does nothing, as files are not locked between processes on this platform
+func unlockFile(*os.File) {}
@@ -0,0 +1,57 @@
+package adapters
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/ports"
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/resources"
+)
+
+// FileTemplateRepository implements the TemplateRepository interface with a directory of template
+// files named after their templates, e.g. policy.md.tmpl, which override the embedded defaults
+type FileTemplateRepository struct {
+	dir string
+}
+
+// DefaultTemplateDirectory returns the template directory shared by the MCP server and the command
+// line tool, next to the implementation records
+func DefaultTemplateDirectory() string {
+	return filepath.Join(filepath.Dir(DefaultImplementationStorePath()), "templates")
+}
+
+// NewFileTemplateRepository creates a repository that reads templates from a directory, falling
+// back to the embedded defaults. An empty directory name uses only the defaults.
+func NewFileTemplateRepository(dir string) *FileTemplateRepository {
+	return &FileTemplateRepository{dir: dir}
+}
+
+// GetTemplate returns the text of the template with a name from the directory, or the embedded
+// default template with the name
+func (r *FileTemplateRepository) GetTemplate(name string) (string, error) {
+	fileName := name + ".md.tmpl"
+	if r.dir != "" {
+		data, err := os.ReadFile(filepath.Join(r.dir, fileName))
+		if err == nil {
+			return string(data), nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", fedramp.WrapError(fedramp.ErrInternal, err, "failed to read template %s", name)
+		}
+	}
+
+	data, err := resources.Templates.ReadFile("templates/" + fileName)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", fedramp.NewError(fedramp.ErrNotFound, "template %q not found", name)
+	}
+	if err != nil {
+		return "", fedramp.WrapError(fedramp.ErrInternal, err, "failed to read template %s", name)
+	}
+	return string(data), nil
+}
+
+// Ensure FileTemplateRepository implements TemplateRepository
+var _ ports.TemplateRepository = (*FileTemplateRepository)(nil)
@@ -0,0 +1,129 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/ports"
+)
+
+// JSONComponentRepository implements the ComponentRepository interface with a JSON file. Like
+// JSONImplementationRepository, the file is read on every call and replaced atomically on every
+// write under a lock shared between processes, and calls whose context is done give up without
+// reading or writing it.
+type JSONComponentRepository struct {
+	path string
+	lock *fileLock
+}
+
+// componentFile is the layout of the JSON file
+type componentFile struct {
+	Components []fedramp.Component `json:"components"`
+}
+
+// DefaultComponentStorePath returns the components file shared by the MCP server and the command
+// line tool, next to the implementation records
+func DefaultComponentStorePath() string {
+	return filepath.Join(filepath.Dir(DefaultImplementationStorePath()), "components.json")
+}
+
+// NewJSONComponentRepository creates a repository that stores components in a JSON file, which is
+// created on the first write
+func NewJSONComponentRepository(path string) *JSONComponentRepository {
+	return &JSONComponentRepository{path: path, lock: newFileLock(path + ".lock")}
+}
+
+// GetComponent returns the component with a name in any case
+func (r *JSONComponentRepository) GetComponent(ctx context.Context, name string) (fedramp.Component, error) {
+	if err := r.lock.lock(ctx, "components"); err != nil {
+		return fedramp.Component{}, err
+	}
+	defer r.lock.unlock()
+
+	components, err := r.load()
+	if err != nil {
+		return fedramp.Component{}, err
+	}
+	component, ok := fedramp.FindComponent(components, name)
+	if !ok {
+		return fedramp.Component{}, fedramp.NewError(fedramp.ErrNotFound, "component %q not found", name).
+			WithSuggestions(fedramp.ClosestMatches(name, fedramp.ComponentNames(components))...)
+	}
+	return component, nil
+}
+
+// SaveComponent creates or replaces the component with the component's name
+func (r *JSONComponentRepository) SaveComponent(ctx context.Context, component fedramp.Component) error {
+	if err := r.lock.lock(ctx, "components"); err != nil {
+		return err
+	}
+	defer r.lock.unlock()
+
+	components, err := r.load()
+	if err != nil {
+		return err
+	}
+	index := slices.IndexFunc(components, func(existing fedramp.Component) bool {
+		return strings.EqualFold(existing.Name, component.Name)
+	})
+	if index >= 0 {
+		components[index] = component
+	} else {
+		components = append(components, component)
+	}
+	return r.store(components)
+}
+
+// ListComponents returns every component, ordered by name
+func (r *JSONComponentRepository) ListComponents(ctx context.Context) ([]fedramp.Component, error) {
+	if err := r.lock.lock(ctx, "components"); err != nil {
+		return nil, err
+	}
+	defer r.lock.unlock()
+
+	components, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+	if components == nil {
+		components = []fedramp.Component{}
+	}
+	return components, nil
+}
+
+// load reads the components from the file. A missing file has no components.
+func (r *JSONComponentRepository) load() ([]fedramp.Component, error) {
+	data, err := os.ReadFile(r.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fedramp.WrapError(fedramp.ErrInternal, err, "failed to read components")
+	}
+
+	var file componentFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fedramp.WrapError(fedramp.ErrInternal, err, "failed to parse components in %s", r.path)
+	}
+	return file.Components, nil
+}
+
+// store writes the components to the file in name order, replacing it atomically
+func (r *JSONComponentRepository) store(components []fedramp.Component) error {
+	slices.SortFunc(components, func(a, b fedramp.Component) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+	data, err := json.MarshalIndent(componentFile{Components: components}, "", "  ")
+	if err != nil {
+		return fedramp.WrapError(fedramp.ErrInternal, err, "failed to marshal components")
+	}
+	return writeFileAtomically(r.path, data, "components")
+}
+
+// Ensure JSONComponentRepository implements ComponentRepository
+var _ ports.ComponentRepository = (*JSONComponentRepository)(nil)
@@ -0,0 +1,216 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/ports"
+)
+
+// JSONImplementationRepository implements the ImplementationRepository interface with a JSON file.
+// The file is read on every call and replaced atomically on every write, under a lock that the
+// CLI and the MCP server both take, so that they can share it. Calls whose context is done give
+// up without reading or writing it.
+type JSONImplementationRepository struct {
+	path string
+	lock *fileLock
+}
+
+// implementationFile is the layout of the JSON file
+type implementationFile struct {
+	Implementations []fedramp.ImplementationRecord `json:"implementations"`
+}
+
+// DefaultImplementationStorePath returns the implementation records file shared by the MCP server
+// and the command line tool, in the user's home directory
+func DefaultImplementationStorePath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "implementations.json"
+	}
+	return filepath.Join(home, ".mcp-compliance", "implementations.json")
+}
+
+// NewJSONImplementationRepository creates a repository that stores implementation records in a
+// JSON file, which is created on the first write
+func NewJSONImplementationRepository(path string) *JSONImplementationRepository {
+	return &JSONImplementationRepository{path: path, lock: newFileLock(path + ".lock")}
+}
+
+// GetImplementation returns the implementation record for a key
+func (r *JSONImplementationRepository) GetImplementation(ctx context.Context, key fedramp.ImplementationKey) (fedramp.ImplementationRecord, error) {
+	if err := r.lock.lock(ctx, "implementation records"); err != nil {
+		return fedramp.ImplementationRecord{}, err
+	}
+	defer r.lock.unlock()
+
+	records, err := r.load()
+	if err != nil {
+		return fedramp.ImplementationRecord{}, err
+	}
+	for _, record := range records {
+		if record.ImplementationKey == key {
+			return record, nil
+		}
+	}
+	return fedramp.ImplementationRecord{}, fedramp.NewError(fedramp.ErrNotFound, "no implementation recorded for %s in %s for system %q",
+		fedramp.ControlLabel(key.ControlID), key.Program, key.System)
+}
+
+// UpdateImplementation reads, updates and saves the implementation record for a key under the
+// repository's lock
+func (r *JSONImplementationRepository) UpdateImplementation(ctx context.Context, key fedramp.ImplementationKey, update func(record *fedramp.ImplementationRecord) error) (fedramp.ImplementationRecord, error) {
+	records, err := r.UpdateImplementations(ctx, []fedramp.ImplementationKey{key}, func(records []*fedramp.ImplementationRecord) error {
+		return update(records[0])
+	})
+	if err != nil {
+		return fedramp.ImplementationRecord{}, err
+	}
+	return records[0], nil
+}
+
+// UpdateImplementations reads, updates and saves the implementation records for keys under the
+// repository's lock, writing the file once. Nothing is written if the context is done by the time
+// the records have been updated.
+func (r *JSONImplementationRepository) UpdateImplementations(ctx context.Context, keys []fedramp.ImplementationKey, update func(records []*fedramp.ImplementationRecord) error) ([]fedramp.ImplementationRecord, error) {
+	if err := r.lock.lock(ctx, "implementation records"); err != nil {
+		return nil, err
+	}
+	defer r.lock.unlock()
+
+	records, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+	indexes := make([]int, len(keys))
+	updated := make([]*fedramp.ImplementationRecord, len(keys))
+	for i, key := range keys {
+		indexes[i] = slices.IndexFunc(records, func(existing fedramp.ImplementationRecord) bool {
+			return existing.ImplementationKey == key
+		})
+		record := fedramp.ImplementationRecord{ImplementationKey: key}
+		if indexes[i] >= 0 {
+			record = records[indexes[i]]
+		}
+		updated[i] = &record
+	}
+	if err := update(updated); err != nil {
+		return nil, err
+	}
+
+	result := make([]fedramp.ImplementationRecord, len(keys))
+	changed := false
+	for i, record := range updated {
+		record.ImplementationKey = keys[i]
+		result[i] = *record
+		switch {
+		case indexes[i] >= 0:
+			records[indexes[i]] = *record
+			changed = true
+		case !record.CreatedAt.IsZero():
+			records = append(records, *record)
+			changed = true
+		}
+	}
+	if !changed {
+		return result, nil
+	}
+	if err := contextError(ctx, "implementation records"); err != nil {
+		return nil, err
+	}
+	if err := r.store(records); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ListImplementations returns the implementation records selected by a filter, ordered by key
+func (r *JSONImplementationRepository) ListImplementations(ctx context.Context, filter fedramp.ImplementationFilter) ([]fedramp.ImplementationRecord, error) {
+	if err := r.lock.lock(ctx, "implementation records"); err != nil {
+		return nil, err
+	}
+	defer r.lock.unlock()
+
+	records, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+	selected := make([]fedramp.ImplementationRecord, 0, len(records))
+	for _, record := range records {
+		if filter.Matches(record) {
+			selected = append(selected, record)
+		}
+	}
+	return selected, nil
+}
+
+// load reads the records from the file. A missing file has no records.
+func (r *JSONImplementationRepository) load() ([]fedramp.ImplementationRecord, error) {
+	data, err := os.ReadFile(r.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fedramp.WrapError(fedramp.ErrInternal, err, "failed to read implementation records")
+	}
+
+	var file implementationFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fedramp.WrapError(fedramp.ErrInternal, err, "failed to parse implementation records in %s", r.path)
+	}
+	return file.Implementations, nil
+}
+
+// store writes the records to the file in key order, replacing it atomically
+func (r *JSONImplementationRepository) store(records []fedramp.ImplementationRecord) error {
+	slices.SortFunc(records, func(a, b fedramp.ImplementationRecord) int {
+		return fedramp.CompareImplementationKeys(a.ImplementationKey, b.ImplementationKey)
+	})
+	data, err := json.MarshalIndent(implementationFile{Implementations: records}, "", "  ")
+	if err != nil {
+		return fedramp.WrapError(fedramp.ErrInternal, err, "failed to marshal implementation records")
+	}
+
+	return writeFileAtomically(r.path, data, "implementation records")
+}
+
+// writeFileAtomically writes data to a file through a temporary file in the same directory, which
+// replaces the file once written, so that readers never see a partly written file
+func writeFileAtomically(path string, data []byte, what string) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fedramp.WrapError(fedramp.ErrInternal, err, "failed to create %s", dir)
+	}
+	temp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fedramp.WrapError(fedramp.ErrInternal, err, "failed to write %s", what)
+	}
+	defer os.Remove(temp.Name())
+	if _, err := temp.Write(data); err != nil {
+		temp.Close()
+		return fedramp.WrapError(fedramp.ErrInternal, err, "failed to write %s", what)
+	}
+	if err := temp.Close(); err != nil {
+		return fedramp.WrapError(fedramp.ErrInternal, err, "failed to write %s", what)
+	}
+	if err := os.Rename(temp.Name(), path); err != nil {
+		return fedramp.WrapError(fedramp.ErrInternal, err, "failed to write %s", what)
+	}
+	return nil
+}
+
+// contextError returns an error if the context is done, so that a call that was cancelled or ran
+// out of time leaves a file alone
+func contextError(ctx context.Context, what string) error {
+	if err := ctx.Err(); err != nil {
+		return fedramp.WrapError(fedramp.ErrInternal, err, "gave up on the %s", what)
+	}
+	return nil
+}
+
+// Ensure JSONImplementationRepository implements ImplementationRepository
+var _ ports.ImplementationRepository = (*JSONImplementationRepository)(nil)
@@ -0,0 +1,167 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
+)
+
+func TestJSONImplementationRepositoryConcurrentUpdates(t *testing.T) {
+	repo := NewJSONImplementationRepository(filepath.Join(t.TempDir(), "implementations.json"))
+	key := fedramp.ImplementationKey{System: "Test", Program: "FedRAMP Moderate", ControlID: "ac-2"}
+
+	const updates = 20
+	var wg sync.WaitGroup
+	for i := range updates {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := repo.UpdateImplementation(context.Background(), key, func(record *fedramp.ImplementationRecord) error {
+				if record.CreatedAt.IsZero() {
+					record.CreatedAt = time.Now()
+				}
+				record.ResponsibleRoles = append(record.ResponsibleRoles, fmt.Sprintf("Role %d", i))
+				return nil
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	record, err := repo.GetImplementation(context.Background(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(record.ResponsibleRoles) != updates {
+		t.Errorf("got %d roles after %d concurrent updates, want every update kept", len(record.ResponsibleRoles), updates)
+	}
+}
+
+func TestJSONImplementationRepositorySharedFile(t *testing.T) {
+	// Repositories on the same file, as in the CLI and the MCP server, only share the file's lock
+	path := filepath.Join(t.TempDir(), "implementations.json")
+	repos := []*JSONImplementationRepository{NewJSONImplementationRepository(path), NewJSONImplementationRepository(path)}
+	key := fedramp.ImplementationKey{System: "Test", Program: "FedRAMP Moderate", ControlID: "ac-2"}
+
+	const updates = 20
+	var wg sync.WaitGroup
+	for i := range updates {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := repos[i%2].UpdateImplementation(context.Background(), key, func(record *fedramp.ImplementationRecord) error {
+				if record.CreatedAt.IsZero() {
+					record.CreatedAt = time.Now()
+				}
+				record.ResponsibleRoles = append(record.ResponsibleRoles, fmt.Sprintf("Role %d", i))
+				time.Sleep(time.Millisecond) // Give the other repository a chance to interleave
+				return nil
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	record, err := repos[0].GetImplementation(context.Background(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(record.ResponsibleRoles) != updates {
+		t.Errorf("got %d roles after %d concurrent updates through two repositories, want every update kept", len(record.ResponsibleRoles), updates)
+	}
+}
+
+func TestJSONImplementationRepositoryUpdateImplementations(t *testing.T) {
+	repo := NewJSONImplementationRepository(filepath.Join(t.TempDir(), "implementations.json"))
+	keys := []fedramp.ImplementationKey{
+		{System: "Test", Program: "FedRAMP Moderate", ControlID: "ac-2"},
+		{System: "Test", Program: "FedRAMP Moderate", ControlID: "ac-3"},
+	}
+
+	// A failed update saves none of the records
+	_, err := repo.UpdateImplementations(context.Background(), keys, func(records []*fedramp.ImplementationRecord) error {
+		records[0].CreatedAt = time.Now()
+		return errors.New("failed")
+	})
+	if err == nil {
+		t.Fatal("UpdateImplementations returned no error")
+	}
+	if records, _ := repo.ListImplementations(context.Background(), fedramp.ImplementationFilter{}); len(records) != 0 {
+		t.Fatalf("failed update saved %d records, want none", len(records))
+	}
+
+	// New records are saved only once they have a CreatedAt
+	_, err = repo.UpdateImplementations(context.Background(), keys, func(records []*fedramp.ImplementationRecord) error {
+		records[0].CreatedAt = time.Now()
+		records[0].Status = fedramp.StatusPlanned
+		records[1].Status = fedramp.StatusPlanned
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, err := repo.ListImplementations(context.Background(), fedramp.ImplementationFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].ImplementationKey != keys[0] {
+		t.Errorf("saved %+v, want only the record for %v", records, keys[0])
+	}
+}
+
+func TestJSONImplementationRepositoryContext(t *testing.T) {
+	repo := NewJSONImplementationRepository(filepath.Join(t.TempDir(), "implementations.json"))
+	key := fedramp.ImplementationKey{System: "Test", Program: "FedRAMP Moderate", ControlID: "ac-2"}
+	create := func(record *fedramp.ImplementationRecord) error {
+		record.CreatedAt = time.Now()
+		record.Status = fedramp.StatusPlanned
+		return nil
+	}
+
+	// An update whose context is done by the time the record is updated is not saved
+	ctx, cancel := context.WithCancel(context.Background())
+	if _, err := repo.UpdateImplementation(ctx, key, func(record *fedramp.ImplementationRecord) error {
+		cancel()
+		return create(record)
+	}); !errors.Is(err, context.Canceled) {
+		t.Errorf("UpdateImplementation() error = %v, want it cancelled", err)
+	}
+	if records, _ := repo.ListImplementations(context.Background(), fedramp.ImplementationFilter{}); len(records) != 0 {
+		t.Errorf("cancelled update saved %+v", records)
+	}
+
+	// A call waiting for another to finish gives up at its deadline
+	started, release := make(chan struct{}), make(chan struct{})
+	done := make(chan error)
+	go func() {
+		_, err := repo.UpdateImplementation(context.Background(), key, func(record *fedramp.ImplementationRecord) error {
+			close(started)
+			<-release
+			return create(record)
+		})
+		done <- err
+	}()
+	<-started
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := repo.GetImplementation(ctx, key); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GetImplementation() error = %v, want its deadline exceeded", err)
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.GetImplementation(context.Background(), key); err != nil {
+		t.Errorf("GetImplementation() after the update returned error: %v", err)
+	}
+}
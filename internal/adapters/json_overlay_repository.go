@@ -0,0 +1,136 @@
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/ports"
+)
+
+// JSONOverlayRepository implements the OverlayRepository interface with a directory of JSON files,
+// one small file per overlay, so that overlays can be reviewed and shared on their own. Like the
+// other JSON repositories, it reads and writes them under a lock shared between processes.
+type JSONOverlayRepository struct {
+	dir  string
+	lock *fileLock
+}
+
+// DefaultOverlayDirectory returns the overlay directory shared by the MCP server and the command
+// line tool, next to the implementation records
+func DefaultOverlayDirectory() string {
+	return filepath.Join(filepath.Dir(DefaultImplementationStorePath()), "overlays")
+}
+
+// NewJSONOverlayRepository creates a repository that stores overlays in a directory, which is
+// created on the first write
+func NewJSONOverlayRepository(dir string) *JSONOverlayRepository {
+	return &JSONOverlayRepository{dir: dir, lock: newFileLock(filepath.Clean(dir) + ".lock")}
+}
+
+// GetOverlay returns the overlay with a name in any case
+func (r *JSONOverlayRepository) GetOverlay(name string) (fedramp.Overlay, error) {
+	if err := r.lock.lock(context.Background(), "overlays"); err != nil {
+		return fedramp.Overlay{}, err
+	}
+	defer r.lock.unlock()
+
+	overlays, err := r.load()
+	if err != nil {
+		return fedramp.Overlay{}, err
+	}
+	index := slices.IndexFunc(overlays, func(overlay fedramp.Overlay) bool {
+		return strings.EqualFold(overlay.Name, strings.TrimSpace(name))
+	})
+	if index < 0 {
+		names := make([]string, 0, len(overlays))
+		for _, overlay := range overlays {
+			names = append(names, overlay.Name)
+		}
+		return fedramp.Overlay{}, fedramp.NewError(fedramp.ErrNotFound, "overlay %q not found", name).
+			WithSuggestions(fedramp.ClosestMatches(name, names)...)
+	}
+	return overlays[index], nil
+}
+
+// SaveOverlay creates or replaces the overlay with the overlay's name, in a file named after it
+func (r *JSONOverlayRepository) SaveOverlay(overlay fedramp.Overlay) error {
+	if err := r.lock.lock(context.Background(), "overlays"); err != nil {
+		return err
+	}
+	defer r.lock.unlock()
+
+	// Names that differ only in punctuation would share a file
+	path := filepath.Join(r.dir, overlayFileName(overlay.Name))
+	if data, err := os.ReadFile(path); err == nil {
+		var existing fedramp.Overlay
+		if json.Unmarshal(data, &existing) == nil && !strings.EqualFold(existing.Name, overlay.Name) {
+			return fedramp.NewError(fedramp.ErrInvalidArgument, "overlay %q would replace the file of overlay %q; choose another name", overlay.Name, existing.Name)
+		}
+	}
+
+	data, err := json.MarshalIndent(overlay, "", "  ")
+	if err != nil {
+		return fedramp.WrapError(fedramp.ErrInternal, err, "failed to marshal overlay %s", overlay.Name)
+	}
+	return writeFileAtomically(path, data, "overlay "+overlay.Name)
+}
+
+// ListOverlays returns every overlay, ordered by name
+func (r *JSONOverlayRepository) ListOverlays() ([]fedramp.Overlay, error) {
+	if err := r.lock.lock(context.Background(), "overlays"); err != nil {
+		return nil, err
+	}
+	defer r.lock.unlock()
+
+	return r.load()
+}
+
+// load reads every overlay file in the directory. A missing directory has no overlays.
+func (r *JSONOverlayRepository) load() ([]fedramp.Overlay, error) {
+	paths, err := filepath.Glob(filepath.Join(r.dir, "*.json"))
+	if err != nil {
+		return nil, fedramp.WrapError(fedramp.ErrInternal, err, "failed to list overlays")
+	}
+
+	overlays := make([]fedramp.Overlay, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fedramp.WrapError(fedramp.ErrInternal, err, "failed to read overlay %s", path)
+		}
+		var overlay fedramp.Overlay
+		if err := json.Unmarshal(data, &overlay); err != nil {
+			return nil, fedramp.WrapError(fedramp.ErrInternal, err, "failed to parse overlay %s", path)
+		}
+		if strings.TrimSpace(overlay.Name) == "" {
+			return nil, fedramp.NewError(fedramp.ErrInternal, "overlay %s has no name", path)
+		}
+		overlays = append(overlays, overlay)
+	}
+	slices.SortFunc(overlays, func(a, b fedramp.Overlay) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+	return overlays, nil
+}
+
+// overlayFileName returns the name of an overlay's file, e.g. "acme-moderate.json" for
+// "Acme Moderate"
+func overlayFileName(name string) string {
+	slug := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_':
+			return r
+		default:
+			return '-'
+		}
+	}, strings.ToLower(strings.Join(strings.Fields(name), "-")))
+	return slug + ".json"
+}
+
+// Ensure JSONOverlayRepository implements OverlayRepository
+var _ ports.OverlayRepository = (*JSONOverlayRepository)(nil)
@@ -0,0 +1,353 @@
+package adapters
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/auth"
+)
+
+// JWTConfig configures a JWT authenticator
+type JWTConfig struct {
+	// JWKSFile is the path to a JSON Web Key Set containing the issuer's signing keys
+	JWKSFile string
+	// Issuer is the expected "iss" claim. It is not checked if empty.
+	Issuer string
+	// Audience is the expected "aud" claim. It is not checked if empty.
+	Audience string
+	// GroupsClaim is the claim holding the caller's groups (defaults to "groups")
+	GroupsClaim string
+	// Leeway is the clock skew allowed when checking expiry and not-before times
+	Leeway time.Duration
+}
+
+// JWTAuthenticator implements the Authenticator interface by validating OIDC/JWT bearer tokens
+// against the keys in a JWKS file, so that it works without network access to the issuer
+type JWTAuthenticator struct {
+	config JWTConfig
+	keys   []jsonWebKey
+	now    func() time.Time
+}
+
+// jsonWebKey is a parsed public key from a JWKS file
+type jsonWebKey struct {
+	id        string
+	algorithm string
+	key       crypto.PublicKey
+}
+
+// jwtHeader is the header of a JWT
+type jwtHeader struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+}
+
+// NewJWTAuthenticator creates a new JWT authenticator
+func NewJWTAuthenticator(config JWTConfig) (*JWTAuthenticator, error) {
+	if config.GroupsClaim == "" {
+		config.GroupsClaim = "groups"
+	}
+
+	data, err := os.ReadFile(config.JWKSFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read JWKS file: %v", err)
+	}
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &JWTAuthenticator{
+		config: config,
+		keys:   keys,
+		now:    time.Now,
+	}, nil
+}
+
+// Authenticate validates a bearer token and returns the identity it was issued to
+func (a *JWTAuthenticator) Authenticate(ctx context.Context, token string) (auth.Identity, error) {
+	claims, err := a.verify(token)
+	if err != nil {
+		return auth.Identity{}, fmt.Errorf("%w: %v", auth.ErrUnauthenticated, err)
+	}
+	if err := a.validateClaims(claims); err != nil {
+		return auth.Identity{}, fmt.Errorf("%w: %v", auth.ErrUnauthenticated, err)
+	}
+
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return auth.Identity{}, fmt.Errorf("%w: token has no subject", auth.ErrUnauthenticated)
+	}
+
+	return auth.Identity{
+		Subject: subject,
+		Groups:  stringsClaim(claims[a.config.GroupsClaim]),
+	}, nil
+}
+
+// verify checks the signature of a token and returns its claims
+func (a *JWTAuthenticator) verify(token string) (map[string]any, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("malformed token")
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("malformed token header: %v", err)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("malformed token signature: %v", err)
+	}
+
+	key, err := a.findKey(header)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifySignature(header.Algorithm, key.key, []byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return nil, err
+	}
+
+	var claims map[string]any
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("malformed token claims: %v", err)
+	}
+	return claims, nil
+}
+
+// findKey returns the key that signed a token
+func (a *JWTAuthenticator) findKey(header jwtHeader) (jsonWebKey, error) {
+	for _, key := range a.keys {
+		if header.KeyID != "" && key.id != header.KeyID {
+			continue
+		}
+		if key.algorithm != "" && key.algorithm != header.Algorithm || !suitsAlgorithm(key.key, header.Algorithm) {
+			continue
+		}
+		return key, nil
+	}
+	return jsonWebKey{}, fmt.Errorf("no signing key found for kid %q and alg %q", header.KeyID, header.Algorithm)
+}
+
+// suitsAlgorithm reports whether a key can verify signatures made with an algorithm
+func suitsAlgorithm(key crypto.PublicKey, algorithm string) bool {
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		return strings.HasPrefix(algorithm, "RS") || strings.HasPrefix(algorithm, "PS")
+	case *ecdsa.PublicKey:
+		return ecdsaCurves[algorithm] == key.Curve.Params().Name
+	}
+	return false
+}
+
+// validateClaims checks the registered claims of a token
+func (a *JWTAuthenticator) validateClaims(claims map[string]any) error {
+	now := a.now()
+
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return fmt.Errorf("token has no expiry")
+	}
+	if now.After(time.Unix(int64(exp), 0).Add(a.config.Leeway)) {
+		return fmt.Errorf("token has expired")
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(a.config.Leeway).Before(time.Unix(int64(nbf), 0)) {
+		return fmt.Errorf("token is not valid yet")
+	}
+
+	if a.config.Issuer != "" {
+		if issuer, _ := claims["iss"].(string); issuer != a.config.Issuer {
+			return fmt.Errorf("token was issued by %q, expected %q", issuer, a.config.Issuer)
+		}
+	}
+	if a.config.Audience != "" && !slices.Contains(stringsClaim(claims["aud"]), a.config.Audience) {
+		return fmt.Errorf("token is not intended for audience %q", a.config.Audience)
+	}
+
+	return nil
+}
+
+// ecdsaCurves are the curves of the ECDSA signing algorithms
+var ecdsaCurves = map[string]string{
+	"ES256": "P-256",
+	"ES384": "P-384",
+	"ES512": "P-521",
+}
+
+// verifySignature verifies a JWS signature using the algorithm named in the token header
+func verifySignature(algorithm string, key crypto.PublicKey, signed, signature []byte) error {
+	if len(algorithm) != 5 {
+		return fmt.Errorf("unsupported signing algorithm %q", algorithm)
+	}
+
+	var hash crypto.Hash
+	switch algorithm[2:] {
+	case "256":
+		hash = crypto.SHA256
+	case "384":
+		hash = crypto.SHA384
+	case "512":
+		hash = crypto.SHA512
+	default:
+		return fmt.Errorf("unsupported signing algorithm %q", algorithm)
+	}
+	hasher := hash.New()
+	hasher.Write(signed)
+	digest := hasher.Sum(nil)
+
+	switch {
+	case strings.HasPrefix(algorithm, "RS"):
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("signing key is not an RSA key")
+		}
+		if err := rsa.VerifyPKCS1v15(rsaKey, hash, digest, signature); err != nil {
+			return fmt.Errorf("invalid token signature")
+		}
+	case strings.HasPrefix(algorithm, "PS"):
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("signing key is not an RSA key")
+		}
+		if err := rsa.VerifyPSS(rsaKey, hash, digest, signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}); err != nil {
+			return fmt.Errorf("invalid token signature")
+		}
+	case strings.HasPrefix(algorithm, "ES"):
+		ecKey, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("signing key is not an EC key")
+		}
+		// Each ES algorithm is defined for a single curve, so a key on another curve is not accepted
+		if curve := ecKey.Curve.Params().Name; curve != ecdsaCurves[algorithm] {
+			return fmt.Errorf("signing algorithm %q does not match the %s key", algorithm, curve)
+		}
+		size := (ecKey.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return fmt.Errorf("invalid token signature")
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(ecKey, digest, r, s) {
+			return fmt.Errorf("invalid token signature")
+		}
+	default:
+		// Symmetric and unsigned tokens are rejected: the server only holds public keys
+		return fmt.Errorf("unsupported signing algorithm %q", algorithm)
+	}
+	return nil
+}
+
+// parseJWKS parses the public keys in a JSON Web Key Set
+func parseJWKS(data []byte) ([]jsonWebKey, error) {
+	var jwks struct {
+		Keys []struct {
+			KeyType   string `json:"kty"`
+			KeyID     string `json:"kid"`
+			Use       string `json:"use"`
+			Algorithm string `json:"alg"`
+			N         string `json:"n"`
+			E         string `json:"e"`
+			Curve     string `json:"crv"`
+			X         string `json:"x"`
+			Y         string `json:"y"`
+		} `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &jwks); err != nil {
+		return nil, fmt.Errorf("failed to parse JWKS: %v", err)
+	}
+
+	keys := make([]jsonWebKey, 0, len(jwks.Keys))
+	for i, k := range jwks.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+
+		var key crypto.PublicKey
+		switch k.KeyType {
+		case "RSA":
+			n, err := base64.RawURLEncoding.DecodeString(k.N)
+			if err != nil {
+				return nil, fmt.Errorf("key %d has an invalid modulus: %v", i+1, err)
+			}
+			e, err := base64.RawURLEncoding.DecodeString(k.E)
+			if err != nil {
+				return nil, fmt.Errorf("key %d has an invalid exponent: %v", i+1, err)
+			}
+			key = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		case "EC":
+			var curve elliptic.Curve
+			switch k.Curve {
+			case "P-256":
+				curve = elliptic.P256()
+			case "P-384":
+				curve = elliptic.P384()
+			case "P-521":
+				curve = elliptic.P521()
+			default:
+				return nil, fmt.Errorf("key %d uses unsupported curve %q", i+1, k.Curve)
+			}
+			x, err := base64.RawURLEncoding.DecodeString(k.X)
+			if err != nil {
+				return nil, fmt.Errorf("key %d has an invalid x coordinate: %v", i+1, err)
+			}
+			y, err := base64.RawURLEncoding.DecodeString(k.Y)
+			if err != nil {
+				return nil, fmt.Errorf("key %d has an invalid y coordinate: %v", i+1, err)
+			}
+			ecKey := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+			if _, err := ecKey.ECDH(); err != nil {
+				return nil, fmt.Errorf("key %d is not a valid %s key: %v", i+1, k.Curve, err)
+			}
+			key = ecKey
+		default:
+			// Symmetric and unknown key types cannot be used to verify tokens
+			continue
+		}
+
+		keys = append(keys, jsonWebKey{id: k.KeyID, algorithm: k.Algorithm, key: key})
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("JWKS contains no usable signing keys")
+	}
+	return keys, nil
+}
+
+// decodeSegment decodes a base64url-encoded JSON segment of a token
+func decodeSegment(segment string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// stringsClaim returns a claim that may be a single string or an array of strings as a slice
+func stringsClaim(claim any) []string {
+	switch value := claim.(type) {
+	case string:
+		return []string{value}
+	case []any:
+		values := make([]string, 0, len(value))
+		for _, v := range value {
+			if s, ok := v.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
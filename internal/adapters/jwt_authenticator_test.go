@@ -0,0 +1,197 @@
+package adapters
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/auth"
+)
+
+// jwtTestKeys are the signing keys of the test issuer
+type jwtTestKeys struct {
+	ec256 *ecdsa.PrivateKey
+	ec384 *ecdsa.PrivateKey
+	rsa   *rsa.PrivateKey
+}
+
+// newJWTTestAuthenticator returns an authenticator for the issuer's keys at a fixed time
+func newJWTTestAuthenticator(t *testing.T, now time.Time) (*JWTAuthenticator, jwtTestKeys) {
+	t.Helper()
+	ec256, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ec384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encode := func(b []byte) string { return base64.RawURLEncoding.EncodeToString(b) }
+	ecJWK := func(id string, key *ecdsa.PrivateKey) map[string]string {
+		size := (key.Curve.Params().BitSize + 7) / 8
+		return map[string]string{
+			"kty": "EC", "kid": id, "use": "sig", "crv": key.Curve.Params().Name,
+			"x": encode(key.X.FillBytes(make([]byte, size))), "y": encode(key.Y.FillBytes(make([]byte, size))),
+		}
+	}
+	jwks, err := json.Marshal(map[string]any{"keys": []map[string]string{
+		ecJWK("ec256", ec256),
+		ecJWK("ec384", ec384),
+		{"kty": "RSA", "kid": "rsa", "alg": "RS256", "n": encode(rsaKey.N.Bytes()), "e": "AQAB"},
+		{"kty": "oct", "kid": "secret", "k": encode([]byte("secret"))},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, jwks, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	authenticator, err := NewJWTAuthenticator(JWTConfig{
+		JWKSFile: path,
+		Issuer:   "https://issuer.example.com",
+		Audience: "mcp-compliance",
+		Leeway:   time.Minute,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	authenticator.now = func() time.Time { return now }
+	return authenticator, jwtTestKeys{ec256: ec256, ec384: ec384, rsa: rsaKey}
+}
+
+// signJWT returns a token with the given header and claims, signed with key for the header's
+// algorithm: an ECDSA or RSA private key, a byte slice for HMAC, or nil for no signature
+func signJWT(t *testing.T, header, claims map[string]any, key any) string {
+	t.Helper()
+	segment := func(v any) string {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	signed := segment(header) + "." + segment(claims)
+
+	var signature []byte
+	switch key := key.(type) {
+	case *ecdsa.PrivateKey:
+		hash := map[string]crypto.Hash{"ES256": crypto.SHA256, "ES384": crypto.SHA384, "ES512": crypto.SHA512}[header["alg"].(string)]
+		hasher := hash.New()
+		hasher.Write([]byte(signed))
+		r, s, err := ecdsa.Sign(rand.Reader, key, hasher.Sum(nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		size := (key.Curve.Params().BitSize + 7) / 8
+		signature = append(r.FillBytes(make([]byte, size)), s.FillBytes(make([]byte, size))...)
+	case *rsa.PrivateKey:
+		digest := sha256.Sum256([]byte(signed))
+		var err error
+		if signature, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:]); err != nil {
+			t.Fatal(err)
+		}
+	case []byte:
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(signed))
+		signature = mac.Sum(nil)
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestJWTAuthenticator(t *testing.T) {
+	now := time.Unix(1_800_000_000, 0)
+	authenticator, keys := newJWTTestAuthenticator(t, now)
+
+	claims := func(changes map[string]any) map[string]any {
+		claims := map[string]any{
+			"sub":    "alice",
+			"iss":    "https://issuer.example.com",
+			"aud":    "mcp-compliance",
+			"exp":    now.Add(time.Hour).Unix(),
+			"groups": []string{"assessors"},
+		}
+		for name, value := range changes {
+			if value == nil {
+				delete(claims, name)
+			} else {
+				claims[name] = value
+			}
+		}
+		return claims
+	}
+	es256 := map[string]any{"alg": "ES256", "kid": "ec256"}
+
+	tests := []struct {
+		name  string
+		token string
+		valid bool
+	}{
+		{"ES256", signJWT(t, es256, claims(nil), keys.ec256), true},
+		{"ES384", signJWT(t, map[string]any{"alg": "ES384", "kid": "ec384"}, claims(nil), keys.ec384), true},
+		{"RS256", signJWT(t, map[string]any{"alg": "RS256", "kid": "rsa"}, claims(nil), keys.rsa), true},
+		{"key found without a key ID", signJWT(t, map[string]any{"alg": "RS256"}, claims(nil), keys.rsa), true},
+
+		// Algorithms the keys are not for
+		{"alg none", signJWT(t, map[string]any{"alg": "none", "kid": "ec256"}, claims(nil), nil), false},
+		{"alg none without a key ID", signJWT(t, map[string]any{"alg": "none"}, claims(nil), nil), false},
+		{"HS256 with a symmetric key", signJWT(t, map[string]any{"alg": "HS256", "kid": "secret"}, claims(nil), []byte("secret")), false},
+		{"HS256 with a public key as the secret", signJWT(t, map[string]any{"alg": "HS256", "kid": "rsa"}, claims(nil), keys.rsa.N.Bytes()), false},
+		{"ES384 with a P-256 key", signJWT(t, map[string]any{"alg": "ES384", "kid": "ec256"}, claims(nil), keys.ec256), false},
+		{"ES256 with a P-384 key", signJWT(t, map[string]any{"alg": "ES256", "kid": "ec384"}, claims(nil), keys.ec384), false},
+		{"RS256 with an EC key", signJWT(t, map[string]any{"alg": "RS256", "kid": "ec256"}, claims(nil), keys.rsa), false},
+		{"signed by another key", signJWT(t, map[string]any{"alg": "ES256", "kid": "ec256"}, claims(nil), keys.ec384), false},
+
+		// Expiry and not-before times, with a minute's leeway
+		{"expired", signJWT(t, es256, claims(map[string]any{"exp": now.Add(-2 * time.Minute).Unix()}), keys.ec256), false},
+		{"expired within the leeway", signJWT(t, es256, claims(map[string]any{"exp": now.Add(-30 * time.Second).Unix()}), keys.ec256), true},
+		{"no expiry", signJWT(t, es256, claims(map[string]any{"exp": nil}), keys.ec256), false},
+		{"not valid yet", signJWT(t, es256, claims(map[string]any{"nbf": now.Add(2 * time.Minute).Unix()}), keys.ec256), false},
+		{"valid within the leeway", signJWT(t, es256, claims(map[string]any{"nbf": now.Add(30 * time.Second).Unix()}), keys.ec256), true},
+
+		// Issuer and audience
+		{"other issuer", signJWT(t, es256, claims(map[string]any{"iss": "https://other.example.com"}), keys.ec256), false},
+		{"no issuer", signJWT(t, es256, claims(map[string]any{"iss": nil}), keys.ec256), false},
+		{"audience in a list", signJWT(t, es256, claims(map[string]any{"aud": []string{"other", "mcp-compliance"}}), keys.ec256), true},
+		{"other audience", signJWT(t, es256, claims(map[string]any{"aud": []string{"other"}}), keys.ec256), false},
+		{"no audience", signJWT(t, es256, claims(map[string]any{"aud": nil}), keys.ec256), false},
+
+		{"no subject", signJWT(t, es256, claims(map[string]any{"sub": nil}), keys.ec256), false},
+		{"malformed", "not.a-token", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			identity, err := authenticator.Authenticate(context.Background(), tt.token)
+			if !tt.valid {
+				if !errors.Is(err, auth.ErrUnauthenticated) {
+					t.Errorf("Authenticate() = %+v, %v, want an unauthenticated error", identity, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Authenticate() returned error: %v", err)
+			}
+			if identity.Subject != "alice" || len(identity.Groups) != 1 || identity.Groups[0] != "assessors" {
+				t.Errorf("Authenticate() = %+v, want alice in assessors", identity)
+			}
+		})
+	}
+}
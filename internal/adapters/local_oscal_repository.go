@@ -3,6 +3,7 @@ package adapters
 import (
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
@@ -43,140 +44,141 @@ func (r *LocalOSCALRepository) ProcessOSCALCatalog(catalog fedramp.OSCALCatalog,
 				Controls: []fedramp.Control{},
 			}
 
-			// Process each control in the family
+			// Process each control in the family, followed by its enhancements
 			for _, oscalControl := range group.Controls {
-				control := fedramp.Control{
-					ID:                   oscalControl.ID,
-					Title:                oscalControl.Title,
-					Parameters:           []fedramp.ControlParameter{},
-					Statements:           []fedramp.ControlStatement{},
-					AssessmentObjectives: []fedramp.AssessmentObjective{},
-				}
+				family.Controls = r.appendControl(family.Controls, oscalControl)
+			}
 
-				// Extract parameters
-				for _, param := range oscalControl.Params {
-					parameter := fedramp.ControlParameter{
-						ID:    param.ID,
-						Label: param.Label,
-					}
-
-					// Extract guidelines
-					for _, guideline := range param.Guidelines {
-						if guideline.Prose != "" {
-							parameter.Guidelines = append(parameter.Guidelines, guideline.Prose)
-						}
-					}
-
-					control.Parameters = append(control.Parameters, parameter)
-				}
+			program.Families = append(program.Families, family)
+		}
+	}
 
-				// Extract statements, guidance, and assessment objectives
-				var statementText strings.Builder
-				var evidenceGuidanceBuilder strings.Builder
-
-				for _, part := range oscalControl.Parts {
-					if part.Name == "statement" {
-						statement := r.extractStatement(part)
-						control.Statements = append(control.Statements, statement)
-
-						// Build the full statement text
-						if part.Prose != "" {
-							statementText.WriteString(part.Prose)
-							statementText.WriteString("\n\n")
-						}
-
-						// Add sub-parts prose to the full text
-						for _, subPart := range part.Parts {
-							if subPart.Prose != "" {
-								statementText.WriteString(subPart.Prose)
-								statementText.WriteString("\n")
-							}
-
-							// Add deeper nested parts
-							for _, subSubPart := range subPart.Parts {
-								if subSubPart.Prose != "" {
-									statementText.WriteString("  " + subSubPart.Prose)
-									statementText.WriteString("\n")
-								}
-							}
-						}
-					} else if part.Name == "guidance" {
-						control.Guidance = part.Prose
-
-						// Check if guidance contains evidence-related information
-						if strings.Contains(strings.ToLower(part.Prose), "evidence") ||
-							strings.Contains(strings.ToLower(part.Prose), "assess") ||
-							strings.Contains(strings.ToLower(part.Prose), "audit") ||
-							strings.Contains(strings.ToLower(part.Prose), "document") {
-							evidenceGuidanceBuilder.WriteString("Guidance related to evidence:\n")
-							evidenceGuidanceBuilder.WriteString(part.Prose)
-							evidenceGuidanceBuilder.WriteString("\n\n")
-						}
-					} else if part.Name == "assessment-objective" {
-						objective := r.extractAssessmentObjective(part)
-						control.AssessmentObjectives = append(control.AssessmentObjectives, objective)
-
-						// Add assessment objectives to evidence guidance
-						evidenceGuidanceBuilder.WriteString("Assessment Objective:\n")
-						evidenceGuidanceBuilder.WriteString(part.Prose)
-						evidenceGuidanceBuilder.WriteString("\n")
-
-						// Add assessment methods
-						for _, subPart := range part.Parts {
-							for _, prop := range subPart.Props {
-								if prop.Name == "method" {
-									evidenceGuidanceBuilder.WriteString("Assessment Method: ")
-									evidenceGuidanceBuilder.WriteString(prop.Value)
-									evidenceGuidanceBuilder.WriteString("\n")
-								}
-							}
-
-							if subPart.Prose != "" {
-								evidenceGuidanceBuilder.WriteString(subPart.Prose)
-								evidenceGuidanceBuilder.WriteString("\n")
-							}
-						}
-					}
-				}
+	return program, nil
+}
 
-				// Set the full text of the control
-				control.FullText = statementText.String()
-
-				// Set the evidence guidance
-				control.EvidenceGuidance = evidenceGuidanceBuilder.String()
-
-				// Create a search index by combining all text fields
-				var searchIndexBuilder strings.Builder
-				searchIndexBuilder.WriteString(control.ID)
-				searchIndexBuilder.WriteString(" ")
-				searchIndexBuilder.WriteString(control.Title)
-				searchIndexBuilder.WriteString(" ")
-				searchIndexBuilder.WriteString(control.FullText)
-				searchIndexBuilder.WriteString(" ")
-				searchIndexBuilder.WriteString(control.Guidance)
-				searchIndexBuilder.WriteString(" ")
-				searchIndexBuilder.WriteString(control.EvidenceGuidance)
-
-				// Add parameter labels and guidelines to search index
-				for _, param := range control.Parameters {
-					searchIndexBuilder.WriteString(" ")
-					searchIndexBuilder.WriteString(param.Label)
-					for _, guideline := range param.Guidelines {
-						searchIndexBuilder.WriteString(" ")
-						searchIndexBuilder.WriteString(guideline)
-					}
-				}
+// appendControl appends a control and its enhancements, which the catalog nests in the control,
+// to a family's controls
+func (r *LocalOSCALRepository) appendControl(controls []fedramp.Control, oscalControl fedramp.OSCALControl) []fedramp.Control {
+	controls = append(controls, r.processControl(oscalControl))
+	for _, enhancement := range oscalControl.Controls {
+		controls = r.appendControl(controls, enhancement)
+	}
+	return controls
+}
+
+// processControl converts an OSCAL control into a Control
+func (r *LocalOSCALRepository) processControl(oscalControl fedramp.OSCALControl) fedramp.Control {
+	control := fedramp.Control{
+		ID:                   oscalControl.ID,
+		Title:                oscalControl.Title,
+		Parameters:           []fedramp.ControlParameter{},
+		Statements:           []fedramp.ControlStatement{},
+		AssessmentObjectives: []fedramp.AssessmentObjective{},
+	}
+
+	// Record whether the control has been withdrawn and where its requirements went
+	for _, prop := range oscalControl.Props {
+		if prop.Name == "status" {
+			control.Status = prop.Value
+		}
+	}
+	for _, link := range oscalControl.Links {
+		switch link.Rel {
+		case "incorporated-into":
+			if id := fedramp.ControlIDFromHref(link.Href); !slices.Contains(control.IncorporatedInto, id) {
+				control.IncorporatedInto = append(control.IncorporatedInto, id)
+			}
+		case "moved-to":
+			control.MovedTo = fedramp.ControlIDFromHref(link.Href)
+		case "related":
+			if id := fedramp.ControlIDFromHref(link.Href); !slices.Contains(control.RelatedControls, id) {
+				control.RelatedControls = append(control.RelatedControls, id)
+			}
+		}
+	}
 
-				control.SearchIndex = strings.ToLower(searchIndexBuilder.String())
+	// Extract parameters
+	for _, param := range oscalControl.Params {
+		parameter := fedramp.ControlParameter{
+			ID:     param.ID,
+			Label:  param.Label,
+			Values: param.Values,
+		}
 
-				family.Controls = append(family.Controls, control)
+		// Extract guidelines
+		for _, guideline := range param.Guidelines {
+			if guideline.Prose != "" {
+				parameter.Guidelines = append(parameter.Guidelines, guideline.Prose)
 			}
+		}
 
-			program.Families = append(program.Families, family)
+		// Extract the choices and constraints that assigned values must satisfy
+		if param.Select != nil && len(param.Select.Choice) > 0 {
+			parameter.Select = &fedramp.ParameterSelection{HowMany: param.Select.HowMany, Choices: param.Select.Choice}
 		}
+		for _, constraint := range param.Constraints {
+			if constraint.Description != "" {
+				parameter.Constraints = append(parameter.Constraints, constraint.Description)
+			}
+		}
+		for _, prop := range param.Props {
+			if prop.Name == "legacy-identifier" {
+				parameter.LegacyID = prop.Value
+			}
+		}
+
+		control.Parameters = append(control.Parameters, parameter)
 	}
 
-	return program, nil
+	// Extract statements, guidance, and assessment objectives
+	for _, part := range oscalControl.Parts {
+		if part.Name == "statement" {
+			statement := r.extractStatement(part)
+			control.Statements = append(control.Statements, statement)
+		} else if part.Name == "guidance" {
+			control.Guidance = part.Prose
+		} else if part.Name == "assessment-objective" {
+			objective := r.extractAssessmentObjective(part)
+			control.AssessmentObjectives = append(control.AssessmentObjectives, objective)
+		}
+	}
+
+	// Set the full text of the control from its statement tree
+	control.FullText = fedramp.StatementText(control.Statements)
+
+	// Build the evidence guidance from the assessment objectives and the objects of each assessment method
+	objects := make(map[string][]string)
+	r.collectAssessmentObjects(oscalControl.Parts, objects)
+	control.EvidenceGuidance = fedramp.BuildEvidenceGuidance(control.AssessmentObjectives, control.Parameters, objects)
+
+	// Create a search index by combining all text fields
+	var searchIndexBuilder strings.Builder
+	searchIndexBuilder.WriteString(control.ID)
+	searchIndexBuilder.WriteString(" ")
+	searchIndexBuilder.WriteString(control.Title)
+	searchIndexBuilder.WriteString(" ")
+	searchIndexBuilder.WriteString(control.FullText)
+	searchIndexBuilder.WriteString(" ")
+	searchIndexBuilder.WriteString(control.Guidance)
+	searchIndexBuilder.WriteString(" ")
+	for _, determination := range control.EvidenceGuidance.Determinations {
+		searchIndexBuilder.WriteString(" ")
+		searchIndexBuilder.WriteString(determination.Statement)
+	}
+
+	// Add parameter labels and guidelines to search index
+	for _, param := range control.Parameters {
+		searchIndexBuilder.WriteString(" ")
+		searchIndexBuilder.WriteString(param.Label)
+		for _, guideline := range param.Guidelines {
+			searchIndexBuilder.WriteString(" ")
+			searchIndexBuilder.WriteString(guideline)
+		}
+	}
+
+	control.SearchIndex = strings.ToLower(searchIndexBuilder.String())
+
+	return control
 }
 
 // SerializeProgram serializes a Program to JSON
@@ -188,126 +190,94 @@ func (r *LocalOSCALRepository) SerializeProgram(program fedramp.Program) ([]byte
 	return data, nil
 }
 
-// Recursively extract control statements
-func (r *LocalOSCALRepository) extractStatement(part struct {
-	ID    string `json:"id"`
-	Name  string `json:"name"`
-	Prose string `json:"prose,omitempty"`
-	Parts []struct {
-		ID    string `json:"id"`
-		Name  string `json:"name"`
-		Prose string `json:"prose,omitempty"`
-		Props []struct {
-			Name  string `json:"name"`
-			Value string `json:"value"`
-		} `json:"props,omitempty"`
-		Parts []struct {
-			ID    string `json:"id"`
-			Name  string `json:"name"`
-			Prose string `json:"prose,omitempty"`
-			Parts []struct {
-				ID    string `json:"id"`
-				Name  string `json:"name"`
-				Prose string `json:"prose,omitempty"`
-			} `json:"parts,omitempty"`
-		} `json:"parts,omitempty"`
-	} `json:"parts,omitempty"`
-}) fedramp.ControlStatement {
+// extractStatement converts an OSCAL statement part and its nested parts into a statement tree,
+// keeping the label of every part
+func (r *LocalOSCALRepository) extractStatement(part fedramp.OSCALPart) fedramp.ControlStatement {
 	statement := fedramp.ControlStatement{
 		ID:    part.ID,
 		Name:  part.Name,
 		Prose: part.Prose,
 	}
 
-	// Extract label from props if available
-	for _, subPart := range part.Parts {
-		for _, prop := range subPart.Props {
-			if prop.Name == "label" {
-				statement.Label = prop.Value
-				break
-			}
-		}
-
-		// Recursively process sub-parts
-		if len(subPart.Parts) > 0 {
-			for _, subSubPart := range subPart.Parts {
-				subStatement := fedramp.ControlStatement{
-					ID:    subSubPart.ID,
-					Name:  subSubPart.Name,
-					Prose: subSubPart.Prose,
-				}
-				statement.SubParts = append(statement.SubParts, subStatement)
-			}
+	for _, prop := range part.Props {
+		if prop.Name == "label" {
+			statement.Label = prop.Value
+			break
 		}
+	}
 
-		subStatement := fedramp.ControlStatement{
-			ID:    subPart.ID,
-			Name:  subPart.Name,
-			Prose: subPart.Prose,
-		}
-		statement.Parts = append(statement.Parts, subStatement)
+	for _, subPart := range part.Parts {
+		statement.Parts = append(statement.Parts, r.extractStatement(subPart))
 	}
 
 	return statement
 }
 
-// Extract assessment objectives
-func (r *LocalOSCALRepository) extractAssessmentObjective(part struct {
-	ID    string `json:"id"`
-	Name  string `json:"name"`
-	Prose string `json:"prose,omitempty"`
-	Parts []struct {
-		ID    string `json:"id"`
-		Name  string `json:"name"`
-		Prose string `json:"prose,omitempty"`
-		Props []struct {
-			Name  string `json:"name"`
-			Value string `json:"value"`
-		} `json:"props,omitempty"`
-		Parts []struct {
-			ID    string `json:"id"`
-			Name  string `json:"name"`
-			Prose string `json:"prose,omitempty"`
-			Parts []struct {
-				ID    string `json:"id"`
-				Name  string `json:"name"`
-				Prose string `json:"prose,omitempty"`
-			} `json:"parts,omitempty"`
-		} `json:"parts,omitempty"`
-	} `json:"parts,omitempty"`
-}) fedramp.AssessmentObjective {
+// extractAssessmentObjective converts an OSCAL assessment objective and its nested objectives into
+// an objective tree, keeping the label and assessment methods of every objective
+func (r *LocalOSCALRepository) extractAssessmentObjective(part fedramp.OSCALPart) fedramp.AssessmentObjective {
 	objective := fedramp.AssessmentObjective{
 		ID:    part.ID,
 		Name:  part.Name,
 		Prose: part.Prose,
 	}
 
-	// Extract methods from props if available
+	for _, prop := range part.Props {
+		switch prop.Name {
+		case "label":
+			objective.Label = prop.Value
+		case "method":
+			objective.Methods = append(objective.Methods, fedramp.AssessmentMethod{
+				Name:  "method",
+				Value: prop.Value,
+			})
+		}
+	}
+
 	for _, subPart := range part.Parts {
-		for _, prop := range subPart.Props {
-			if prop.Name == "method" {
-				method := fedramp.AssessmentMethod{
-					Name:  "method",
-					Value: prop.Value,
-				}
-				objective.Methods = append(objective.Methods, method)
-			}
+		if subPart.Name == "assessment-objective" {
+			objective.Parts = append(objective.Parts, r.extractAssessmentObjective(subPart))
 		}
+	}
 
-		// Recursively process sub-parts
-		if len(subPart.Parts) > 0 {
-			for _, subSubPart := range subPart.Parts {
-				subObjective := fedramp.AssessmentObjective{
-					ID:    subSubPart.ID,
-					Name:  subSubPart.Name,
-					Prose: subSubPart.Prose,
+	return objective
+}
+
+// collectAssessmentObjects finds the assessment-method parts of a control and records the assessment
+// objects listed for each method, e.g. the documents to examine or the roles to interview
+func (r *LocalOSCALRepository) collectAssessmentObjects(parts []fedramp.OSCALPart, objects map[string][]string) {
+	for _, part := range parts {
+		if part.Name == "assessment-method" {
+			var method string
+			for _, prop := range part.Props {
+				if prop.Name == "method" {
+					method = strings.ToUpper(prop.Value)
+				}
+			}
+			for _, subPart := range part.Parts {
+				if subPart.Name == "assessment-objects" && method != "" {
+					objects[method] = append(objects[method], splitAssessmentObjects(subPart.Prose)...)
 				}
-				objective.Parts = append(objective.Parts, subObjective)
 			}
 		}
+		r.collectAssessmentObjects(part.Parts, objects)
 	}
+}
 
-	return objective
+// splitAssessmentObjects splits the prose of an assessment-objects part, which lists one object per
+// line or separates them with semicolons inside "[SELECT FROM: ...]"
+func splitAssessmentObjects(prose string) []string {
+	prose = strings.TrimSpace(prose)
+	prose = strings.TrimPrefix(prose, "[SELECT FROM:")
+	prose = strings.TrimSuffix(prose, "]")
+
+	var objects []string
+	for _, line := range strings.FieldsFunc(prose, func(r rune) bool { return r == '\n' || r == ';' }) {
+		if object := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(line), ".")); object != "" {
+			objects = append(objects, object)
+		}
+	}
+	return objects
 }
 
 // Ensure LocalOSCALRepository implements OSCALRepository
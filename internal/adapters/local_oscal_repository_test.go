@@ -0,0 +1,90 @@
+package adapters
+
+import (
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
+)
+
+// loadTestProgram processes the test catalog, whose AC-2 nests enhancements as a FedRAMP resolved
+// profile catalog does
+func loadTestProgram(t *testing.T) fedramp.Program {
+	t.Helper()
+	data, err := os.ReadFile("testdata/catalog.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := NewLocalOSCALRepository()
+	catalog, err := repo.ParseOSCALCatalog(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	program, err := repo.ProcessOSCALCatalog(catalog, "Test Baseline")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return program
+}
+
+func TestProcessOSCALCatalogEnhancements(t *testing.T) {
+	program := loadTestProgram(t)
+
+	var ids []string
+	for _, control := range program.Families[0].Controls {
+		ids = append(ids, control.ID)
+	}
+	if want := []string{"ac-2", "ac-2.1", "ac-2.2", "ac-2.10", "ac-3"}; !slices.Equal(ids, want) {
+		t.Fatalf("controls = %v, want %v", ids, want)
+	}
+
+	control, ok := program.FindControl("ac-2.1")
+	if !ok {
+		t.Fatal("ac-2.1 not found")
+	}
+	if control.Title != "Automated System Account Management" || len(control.Parameters) != 1 || control.FullText == "" {
+		t.Errorf("ac-2.1 = %+v, want its title, parameter and statement", control)
+	}
+	if withdrawn, _ := program.FindControl("ac-2.10"); !withdrawn.IsWithdrawn() {
+		t.Errorf("ac-2.10 status = %q, want withdrawn", withdrawn.Status)
+	}
+	if control, _ := program.FindControl("ac-2"); len(control.Parameters) != 1 || control.Parameters[0].Constraints[0] != "at least annually" {
+		t.Errorf("ac-2 parameters = %+v, want the constraint of ac-02_odp.10", control.Parameters)
+	}
+}
+
+func TestProcessOSCALCatalogSelectEnhancements(t *testing.T) {
+	program := loadTestProgram(t)
+
+	tests := []struct {
+		selector string
+		want     []string
+	}{
+		{"AC-2(1)", []string{"ac-2.1"}},
+		{"ac-2.1", []string{"ac-2.1"}},
+		{"AC-2 (2)", []string{"ac-2.2"}},
+		{"AC-02(01)", []string{"ac-2.1"}},
+		{"AC-2(*)", []string{"ac-2", "ac-2.1", "ac-2.2"}},
+		{"AC-2(10)", []string{"ac-2.10"}},
+		{"AC-2(1) through AC-2(2)", []string{"ac-2.1", "ac-2.2"}},
+		{"AC-2 through AC-3", []string{"ac-2", "ac-3"}},
+		{"AC-*", []string{"ac-2", "ac-2.1", "ac-2.2", "ac-3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			selector, err := fedramp.ParseControlSelector(tt.selector)
+			if err != nil {
+				t.Fatalf("ParseControlSelector(%q) returned error: %v", tt.selector, err)
+			}
+			selection := fedramp.SelectControls(program, []fedramp.ControlSelector{selector}, false)
+			var got []string
+			for _, control := range selection.Controls {
+				got = append(got, control.ID)
+			}
+			if !slices.Equal(got, tt.want) || len(selection.Unknown) != 0 {
+				t.Errorf("SelectControls(%q) = %v (unknown %v), want %v", tt.selector, got, selection.Unknown, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package adapters
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/auth"
+)
+
+// StaticTokenAuthenticator implements the Authenticator interface using a fixed set of bearer tokens
+type StaticTokenAuthenticator struct {
+	// Map of token hashes to the identities they were issued to. Tokens are looked up by hash
+	// so that comparisons do not leak the token through timing.
+	identities map[[sha256.Size]byte]auth.Identity
+}
+
+// staticTokenFile is the format of a static token file
+type staticTokenFile struct {
+	Tokens []struct {
+		Token   string   `json:"token"`
+		Subject string   `json:"subject"`
+		Groups  []string `json:"groups,omitempty"`
+	} `json:"tokens"`
+}
+
+// NewStaticTokenAuthenticator creates a new static token authenticator from a JSON token file
+func NewStaticTokenAuthenticator(path string) (*StaticTokenAuthenticator, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token file: %v", err)
+	}
+
+	var file staticTokenFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse token file: %v", err)
+	}
+
+	identities := make(map[[sha256.Size]byte]auth.Identity, len(file.Tokens))
+	for i, entry := range file.Tokens {
+		if entry.Token == "" || entry.Subject == "" {
+			return nil, fmt.Errorf("token %d in %s must have a token and a subject", i+1, path)
+		}
+		hash := sha256.Sum256([]byte(entry.Token))
+		if _, ok := identities[hash]; ok {
+			return nil, fmt.Errorf("token %d in %s is a duplicate", i+1, path)
+		}
+		identities[hash] = auth.Identity{Subject: entry.Subject, Groups: entry.Groups}
+	}
+
+	return &StaticTokenAuthenticator{identities: identities}, nil
+}
+
+// Authenticate validates a bearer token and returns the identity it was issued to
+func (a *StaticTokenAuthenticator) Authenticate(ctx context.Context, token string) (auth.Identity, error) {
+	identity, ok := a.identities[sha256.Sum256([]byte(token))]
+	if !ok {
+		return auth.Identity{}, fmt.Errorf("%w: unknown token", auth.ErrUnauthenticated)
+	}
+	return identity, nil
+}
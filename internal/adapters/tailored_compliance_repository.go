@@ -0,0 +1,85 @@
+package adapters
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/ports"
+)
+
+// TailoredComplianceRepository implements the ComplianceRepository interface by adding the
+// programs derived from overlays to the programs of another repository. Each overlay is a program
+// under its own name, applied to its base program whenever it is loaded, so that it follows
+// updates to the base program's data.
+type TailoredComplianceRepository struct {
+	base     ports.ComplianceRepository
+	overlays ports.OverlayRepository
+}
+
+// NewTailoredComplianceRepository creates a repository with the programs of a base repository and
+// the programs derived from the overlays in an overlay repository
+func NewTailoredComplianceRepository(base ports.ComplianceRepository, overlays ports.OverlayRepository) *TailoredComplianceRepository {
+	return &TailoredComplianceRepository{base: base, overlays: overlays}
+}
+
+// ListPrograms returns the programs of the base repository and the tailored programs, in name order
+func (r *TailoredComplianceRepository) ListPrograms() ([]string, error) {
+	programs, err := r.base.ListPrograms()
+	if err != nil {
+		return nil, err
+	}
+	overlays, err := r.overlays.ListOverlays()
+	if err != nil {
+		return nil, err
+	}
+	for _, overlay := range overlays {
+		if !slices.ContainsFunc(programs, func(program string) bool { return strings.EqualFold(program, overlay.Name) }) {
+			programs = append(programs, overlay.Name)
+		}
+	}
+	slices.Sort(programs)
+	return programs, nil
+}
+
+// LoadProgram loads a program of the base repository, or applies an overlay to its base program
+func (r *TailoredComplianceRepository) LoadProgram(programName string) (fedramp.Program, error) {
+	basePrograms, err := r.base.ListPrograms()
+	if err != nil {
+		return fedramp.Program{}, err
+	}
+	if slices.ContainsFunc(basePrograms, func(program string) bool { return strings.EqualFold(program, programName) }) {
+		return r.base.LoadProgram(programName)
+	}
+
+	overlay, err := r.overlays.GetOverlay(programName)
+	if fedramp.KindOf(err) == fedramp.ErrNotFound {
+		programs, _ := r.ListPrograms()
+		return fedramp.Program{}, fedramp.NewError(fedramp.ErrProgramUnavailable, "program not found: %s", programName).
+			WithSuggestions(programs...)
+	}
+	if err != nil {
+		return fedramp.Program{}, err
+	}
+	return r.apply(overlay)
+}
+
+// apply applies an overlay to the programs of the base repository and returns the derived program
+func (r *TailoredComplianceRepository) apply(overlay fedramp.Overlay) (fedramp.Program, error) {
+	var base fedramp.Program
+	var sources []fedramp.Program
+	for i, name := range overlay.OverlayPrograms() {
+		program, err := r.base.LoadProgram(name)
+		if err != nil {
+			return fedramp.Program{}, err
+		}
+		if i == 0 {
+			base = program
+		}
+		sources = append(sources, program)
+	}
+	return fedramp.ApplyOverlay(base, overlay, sources)
+}
+
+// Ensure TailoredComplianceRepository implements ComplianceRepository
+var _ ports.ComplianceRepository = (*TailoredComplianceRepository)(nil)
@@ -0,0 +1,32 @@
+package auth
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrUnauthenticated is returned when a request does not carry valid credentials
+var ErrUnauthenticated = errors.New("unauthenticated")
+
+// ErrPermissionDenied is returned when an identity is not allowed to perform an operation
+var ErrPermissionDenied = errors.New("permission denied")
+
+// Identity represents an authenticated caller
+type Identity struct {
+	Subject string   `json:"subject"`
+	Groups  []string `json:"groups,omitempty"`
+}
+
+// identityKey is the context key for the authenticated identity
+type identityKey struct{}
+
+// WithIdentity returns a copy of the context carrying the authenticated identity
+func WithIdentity(ctx context.Context, identity Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// IdentityFromContext returns the authenticated identity carried by the context, if any
+func IdentityFromContext(ctx context.Context) (Identity, bool) {
+	identity, ok := ctx.Value(identityKey{}).(Identity)
+	return identity, ok
+}
@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Wildcard matches any subject, group, tool or program in a rule
+const Wildcard = "*"
+
+// Policy is a set of rules granting identities access to tools and programs.
+// Access is denied unless a rule grants it.
+type Policy struct {
+	Rules []Rule `json:"rules"`
+}
+
+// Rule grants the listed tools on the listed programs to the listed subjects and groups
+type Rule struct {
+	Subjects []string `json:"subjects,omitempty"`
+	Groups   []string `json:"groups,omitempty"`
+	Tools    []string `json:"tools"`
+	Programs []string `json:"programs"`
+}
+
+// ParsePolicy parses a JSON authorization policy
+func ParsePolicy(data []byte) (Policy, error) {
+	var policy Policy
+	if err := json.Unmarshal(data, &policy); err != nil {
+		return Policy{}, fmt.Errorf("failed to parse policy: %v", err)
+	}
+	for i, rule := range policy.Rules {
+		if len(rule.Subjects) == 0 && len(rule.Groups) == 0 {
+			return Policy{}, fmt.Errorf("rule %d must list at least one subject or group", i+1)
+		}
+		if len(rule.Tools) == 0 || len(rule.Programs) == 0 {
+			return Policy{}, fmt.Errorf("rule %d must list at least one tool and program", i+1)
+		}
+	}
+	return policy, nil
+}
+
+// Authorize checks that the identity in the context may call a tool on a program.
+// An empty program authorizes the tool if it is granted on any program.
+func (p Policy) Authorize(ctx context.Context, tool, program string) error {
+	identity, ok := IdentityFromContext(ctx)
+	if !ok {
+		return ErrUnauthenticated
+	}
+	for _, rule := range p.Rules {
+		if rule.appliesTo(identity) && matches(rule.Tools, tool, false) && (program == "" || matches(rule.Programs, program, true)) {
+			return nil
+		}
+	}
+	if program == "" {
+		return fmt.Errorf("%w: %s may not call %s", ErrPermissionDenied, identity.Subject, tool)
+	}
+	return fmt.Errorf("%w: %s may not call %s on %s", ErrPermissionDenied, identity.Subject, tool, program)
+}
+
+// AuthorizeProgram checks that the identity in the context may read a program with at least one tool
+func (p Policy) AuthorizeProgram(ctx context.Context, program string) error {
+	identity, ok := IdentityFromContext(ctx)
+	if !ok {
+		return ErrUnauthenticated
+	}
+	for _, rule := range p.Rules {
+		if rule.appliesTo(identity) && matches(rule.Programs, program, true) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s may not access %s", ErrPermissionDenied, identity.Subject, program)
+}
+
+// appliesTo reports whether the rule applies to the identity
+func (r Rule) appliesTo(identity Identity) bool {
+	if matches(r.Subjects, identity.Subject, false) {
+		return true
+	}
+	if slices.Contains(r.Groups, Wildcard) {
+		return true
+	}
+	for _, group := range identity.Groups {
+		if slices.Contains(r.Groups, group) {
+			return true
+		}
+	}
+	return false
+}
+
+// matches reports whether a value is in a list of patterns, optionally ignoring case
+func matches(patterns []string, value string, ignoreCase bool) bool {
+	for _, pattern := range patterns {
+		if pattern == Wildcard || pattern == value || (ignoreCase && strings.EqualFold(pattern, value)) {
+			return true
+		}
+	}
+	return false
+}
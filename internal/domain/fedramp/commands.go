@@ -14,8 +14,9 @@ type WriteOutputCommand struct {
 
 // SearchControlsCommand represents a command to search for controls by keyword
 type SearchControlsCommand struct {
-	Program Program
-	Query   string
+	Program          Program
+	Query            string
+	IncludeWithdrawn bool
 }
 
 // GetControlCommand represents a command to get a control by ID
@@ -26,13 +27,15 @@ type GetControlCommand struct {
 
 // GetControlFamilyCommand represents a command to get a control family by ID
 type GetControlFamilyCommand struct {
-	Program  Program
-	FamilyID string
+	Program          Program
+	FamilyID         string
+	IncludeWithdrawn bool
 }
 
 // ListControlFamiliesCommand represents a command to list all control families
 type ListControlFamiliesCommand struct {
-	Program Program
+	Program          Program
+	IncludeWithdrawn bool
 }
 
 // GetControlEvidenceGuidanceCommand represents a command to get evidence guidance for a control
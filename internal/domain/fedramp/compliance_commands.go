@@ -10,6 +10,29 @@ type GetProgramCommand struct {
 	ProgramName string
 }
 
+// GetControlsCommand is a command to get the controls matched by a list of selectors
+type GetControlsCommand struct {
+	Program          Program
+	Selectors        []ControlSelector
+	IncludeWithdrawn bool
+}
+
+// DiffProgramsCommand is a command to compare the controls of two compliance programs
+type DiffProgramsCommand struct {
+	From Program
+	To   Program
+}
+
+// SaveOverlayCommand is a command to create or replace an overlay that tailors a program
+type SaveOverlayCommand struct {
+	Overlay Overlay
+}
+
+// GetOverlayCommand is a command to get an overlay by name
+type GetOverlayCommand struct {
+	Name string
+}
+
 // Note: The following commands are already defined in commands.go:
 // - GetControlCommand
 // - GetControlFamilyCommand
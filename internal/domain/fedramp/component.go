@@ -0,0 +1,137 @@
+package fedramp
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ComponentTypes lists the OSCAL types of the components that implement controls
+var ComponentTypes = []string{
+	"software",
+	"hardware",
+	"service",
+	"policy",
+	"process-procedure",
+	"plan",
+	"guidance",
+	"standard",
+	"validation",
+	"interconnection",
+	"physical",
+}
+
+// ParseComponentType parses a component type in any case, accepting spaces in place of hyphens
+func ParseComponentType(s string) (string, error) {
+	normalized := strings.ToLower(strings.Join(strings.Fields(s), "-"))
+	if slices.Contains(ComponentTypes, normalized) {
+		return normalized, nil
+	}
+	return "", NewError(ErrInvalidArgument, "unknown component type %q (valid types: %s)", s, strings.Join(ComponentTypes, ", ")).
+		WithSuggestions(ClosestMatches(s, ComponentTypes)...)
+}
+
+// Component is a part of a system that implements controls, such as a product, service or
+// process, e.g. "Grafana" or "Kubernetes"
+type Component struct {
+	Name        string             `json:"name"`
+	Type        string             `json:"type"` // OSCAL component type, e.g. "software" or "service"
+	Description string             `json:"description,omitempty"`
+	Controls    []ComponentControl `json:"controls,omitempty"` // Controls the component implements, by program
+	CreatedAt   time.Time          `json:"createdAt"`
+	UpdatedAt   time.Time          `json:"updatedAt"`
+}
+
+// ComponentControl maps a component to a control of a program it implements
+type ComponentControl struct {
+	Program   string `json:"program"`
+	ControlID string `json:"controlId"`
+	Narrative string `json:"narrative,omitempty"` // How the component implements the control
+	// Statements the component satisfies, with how it satisfies each, by statement ID. A component
+	// without statements satisfies the whole control.
+	Statements map[string]string `json:"statements,omitempty"`
+}
+
+// ComponentUpdate describes changes to a component. Empty and nil fields keep the values already
+// recorded.
+type ComponentUpdate struct {
+	Type        string
+	Description *string
+}
+
+// ComponentMapping is a component that implements a control, with how it implements it
+type ComponentMapping struct {
+	Component string `json:"component"`
+	Type      string `json:"type"`
+	ComponentControl
+}
+
+// FindControl returns the component's mapping to a control of a program
+func (c Component) FindControl(programName, controlID string) (ComponentControl, bool) {
+	index := slices.IndexFunc(c.Controls, func(control ComponentControl) bool {
+		return strings.EqualFold(control.Program, programName) && control.ControlID == controlID
+	})
+	if index < 0 {
+		return ComponentControl{}, false
+	}
+	return c.Controls[index], true
+}
+
+// SetControl adds or replaces the component's mapping to a control, keeping the mappings in
+// program and catalog order
+func (c *Component) SetControl(mapping ComponentControl) {
+	c.RemoveControl(mapping.Program, mapping.ControlID)
+	c.Controls = append(c.Controls, mapping)
+	slices.SortFunc(c.Controls, func(a, b ComponentControl) int {
+		return cmp.Or(strings.Compare(a.Program, b.Program), CompareControlIDs(a.ControlID, b.ControlID))
+	})
+}
+
+// RemoveControl removes the component's mapping to a control, if any
+func (c *Component) RemoveControl(programName, controlID string) {
+	c.Controls = slices.DeleteFunc(c.Controls, func(control ComponentControl) bool {
+		return strings.EqualFold(control.Program, programName) && control.ControlID == controlID
+	})
+	if len(c.Controls) == 0 {
+		c.Controls = nil
+	}
+}
+
+// Satisfies reports whether the component satisfies a statement of the control
+func (c ComponentControl) Satisfies(statementID string) bool {
+	if len(c.Statements) == 0 {
+		return true
+	}
+	_, ok := c.Statements[statementID]
+	return ok
+}
+
+// StatementNarrative returns how the component satisfies a statement, falling back to its
+// narrative for the control as a whole
+func (c ComponentControl) StatementNarrative(statementID string) string {
+	if narrative := c.Statements[statementID]; narrative != "" {
+		return narrative
+	}
+	return c.Narrative
+}
+
+// ComponentNames returns the names of components
+func ComponentNames(components []Component) []string {
+	names := make([]string, 0, len(components))
+	for _, component := range components {
+		names = append(names, component.Name)
+	}
+	return names
+}
+
+// FindComponent returns the component with a name in any case
+func FindComponent(components []Component, name string) (Component, bool) {
+	index := slices.IndexFunc(components, func(component Component) bool {
+		return strings.EqualFold(component.Name, strings.TrimSpace(name))
+	})
+	if index < 0 {
+		return Component{}, false
+	}
+	return components[index], true
+}
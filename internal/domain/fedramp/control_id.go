@@ -0,0 +1,94 @@
+package fedramp
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// controlIDPattern matches the common notations for NIST SP 800-53 control IDs: a two letter family,
+// a control number and an optional enhancement number. The separators between the parts are
+// optional and may be a hyphen, underscore, space or dot, and the enhancement may be written in
+// parentheses, e.g. "AC-2", "ac 2", "AC-02", "AC-2(1)", "AC-2 (1)", "ac-2.1" and "ac2_1".
+var controlIDPattern = regexp.MustCompile(`^([a-z]{2})[\s_.-]*(\d+)(?:\s*\(\s*(\d+)\s*\)|[\s_.-]+(\d+))?$`)
+
+// ControlID is a parsed NIST SP 800-53 control ID
+type ControlID struct {
+	Family      string // Lowercase family ID, e.g. "ac"
+	Number      int    // Control number within the family
+	Enhancement int    // Enhancement number, or 0 for a base control
+}
+
+// ParseControlID parses a control ID written in any common notation
+func ParseControlID(s string) (ControlID, error) {
+	match := controlIDPattern.FindStringSubmatch(strings.ToLower(strings.TrimSpace(s)))
+	if match == nil {
+		return ControlID{}, NewError(ErrInvalidArgument, "invalid control ID %q (expected a family and number such as AC-2 or AC-2(1))", s)
+	}
+
+	id := ControlID{Family: match[1]}
+	id.Number, _ = strconv.Atoi(match[2])
+	enhancement := match[3] + match[4]
+	if enhancement != "" {
+		id.Enhancement, _ = strconv.Atoi(enhancement)
+	}
+	if id.Number == 0 {
+		return ControlID{}, NewError(ErrInvalidArgument, "invalid control ID %q (control numbers start at 1)", s)
+	}
+	if enhancement != "" && id.Enhancement == 0 {
+		return ControlID{}, NewError(ErrInvalidArgument, "invalid control ID %q (enhancement numbers start at 1)", s)
+	}
+	return id, nil
+}
+
+// String returns the OSCAL ID of the control, e.g. "ac-2" or "ac-2.1"
+func (id ControlID) String() string {
+	if id.IsEnhancement() {
+		return fmt.Sprintf("%s-%d.%d", id.Family, id.Number, id.Enhancement)
+	}
+	return fmt.Sprintf("%s-%d", id.Family, id.Number)
+}
+
+// Label returns the display label of the control, e.g. "AC-2" or "AC-2(1)"
+func (id ControlID) Label() string {
+	if id.IsEnhancement() {
+		return fmt.Sprintf("%s-%d(%d)", strings.ToUpper(id.Family), id.Number, id.Enhancement)
+	}
+	return fmt.Sprintf("%s-%d", strings.ToUpper(id.Family), id.Number)
+}
+
+// IsEnhancement reports whether the ID is a control enhancement
+func (id ControlID) IsEnhancement() bool {
+	return id.Enhancement > 0
+}
+
+// Base returns the ID of the base control of an enhancement
+func (id ControlID) Base() ControlID {
+	return ControlID{Family: id.Family, Number: id.Number}
+}
+
+// NormalizeControlID returns the OSCAL ID for a control ID written in any common notation.
+// Values that are not control IDs are returned trimmed and lowercased so that they can still be
+// compared with IDs that do not follow the NIST convention.
+func NormalizeControlID(s string) string {
+	id, err := ParseControlID(s)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(s))
+	}
+	return id.String()
+}
+
+// ControlLabel returns the display label for a control ID written in any common notation
+func ControlLabel(s string) string {
+	id, err := ParseControlID(s)
+	if err != nil {
+		return strings.ToUpper(strings.TrimSpace(s))
+	}
+	return id.Label()
+}
+
+// NormalizeFamilyID returns the lowercase family ID for a family written as "AC", "ac" or " Ac "
+func NormalizeFamilyID(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
@@ -0,0 +1,87 @@
+package fedramp
+
+import "testing"
+
+func TestParseControlID(t *testing.T) {
+	tests := []struct {
+		input string
+		id    string
+		label string
+	}{
+		// Base controls
+		{"AC-2", "ac-2", "AC-2"},
+		{"ac-2", "ac-2", "AC-2"},
+		{"AC-02", "ac-2", "AC-2"},
+		{"AC 2", "ac-2", "AC-2"},
+		{"ac2", "ac-2", "AC-2"},
+		{"AC_2", "ac-2", "AC-2"},
+		{"  ac-2  ", "ac-2", "AC-2"},
+		{"SC-12", "sc-12", "SC-12"},
+		{"SC-012", "sc-12", "SC-12"},
+		{"pm-30", "pm-30", "PM-30"},
+
+		// Enhancements
+		{"AC-2(1)", "ac-2.1", "AC-2(1)"},
+		{"AC-2 (1)", "ac-2.1", "AC-2(1)"},
+		{"AC-2( 1 )", "ac-2.1", "AC-2(1)"},
+		{"ac-2.1", "ac-2.1", "AC-2(1)"},
+		{"AC-02(01)", "ac-2.1", "AC-2(1)"},
+		{"AC-2.01", "ac-2.1", "AC-2(1)"},
+		{"ac 2 1", "ac-2.1", "AC-2(1)"},
+		{"ac2_1", "ac-2.1", "AC-2(1)"},
+		{"SI-4(12)", "si-4.12", "SI-4(12)"},
+		{"si-4.12", "si-4.12", "SI-4(12)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			id, err := ParseControlID(tt.input)
+			if err != nil {
+				t.Fatalf("ParseControlID(%q) returned error: %v", tt.input, err)
+			}
+			if got := id.String(); got != tt.id {
+				t.Errorf("ParseControlID(%q).String() = %q, want %q", tt.input, got, tt.id)
+			}
+			if got := id.Label(); got != tt.label {
+				t.Errorf("ParseControlID(%q).Label() = %q, want %q", tt.input, got, tt.label)
+			}
+
+			// The OSCAL ID and the label must both parse back to the same control
+			for _, notation := range []string{id.String(), id.Label()} {
+				roundTrip, err := ParseControlID(notation)
+				if err != nil || roundTrip != id {
+					t.Errorf("ParseControlID(%q) = %v, %v, want %v", notation, roundTrip, err, id)
+				}
+			}
+		})
+	}
+}
+
+func TestParseControlIDInvalid(t *testing.T) {
+	for _, input := range []string{"", "AC", "AC-", "A-2", "ACC-2", "AC-0", "AC-2(0)", "AC-2(00)", "ac-2.0", "ac 2 0", "AC-2(", "AC-2(1", "AC-2(x)", "ac-02_odp.01", "2-AC"} {
+		t.Run(input, func(t *testing.T) {
+			if id, err := ParseControlID(input); err == nil {
+				t.Errorf("ParseControlID(%q) = %v, want an error", input, id)
+			} else if KindOf(err) != ErrInvalidArgument {
+				t.Errorf("ParseControlID(%q) error kind = %s, want %s", input, KindOf(err), ErrInvalidArgument)
+			}
+		})
+	}
+}
+
+func TestNormalizeControlID(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"AC-2(1)", "ac-2.1"},
+		{"AC 2", "ac-2"},
+		// Values that are not control IDs are compared as-is
+		{" Custom-Control ", "custom-control"},
+	}
+	for _, tt := range tests {
+		if got := NormalizeControlID(tt.input); got != tt.want {
+			t.Errorf("NormalizeControlID(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package fedramp
+
+import (
+	"regexp"
+	"strings"
+)
+
+// controlRangePattern splits a control range such as "AC-2 through AC-6" into its endpoints
+var controlRangePattern = regexp.MustCompile(`(?i)^(.+?)\s*(?:\s(?:through|thru|to)\s|\.\.|–|—)\s*(.+)$`)
+
+// familyWildcardPattern matches a whole-family selector such as "AC-*" or "AC(*)"
+var familyWildcardPattern = regexp.MustCompile(`(?i)^([a-z]{2})\s*[-\s]?\s*\(?\*\)?$`)
+
+// enhancementWildcardPattern matches a selector for a control and its enhancements such as "AC-2(*)"
+var enhancementWildcardPattern = regexp.MustCompile(`(?i)^(.+?)\s*(?:\(\s*\*\s*\)|\.\*)$`)
+
+// ControlSelector selects controls from a program by ID. It is one of:
+//   - a single control, e.g. "AC-2" or "AC-2(1)"
+//   - a range of controls in one family, e.g. "AC-2 through AC-6", "AC-2 to AC-6" or "AC-2..AC-6".
+//     A range of base controls does not include their enhancements.
+//   - a control and all of its enhancements, e.g. "AC-2(*)"
+//   - every control in a family, e.g. "AC-*"
+//
+// Selectors that are not in any of these notations select the control with exactly that ID.
+type ControlSelector struct {
+	Raw string // The selector as written by the caller
+
+	literal  string    // Normalized ID for selectors that are not NIST control IDs
+	from, to ControlID // Inclusive bounds of the selected controls
+	family   string    // Family selected as a whole
+	withEnh  bool      // Whether enhancements of the selected base controls are included
+}
+
+// ParseControlSelector parses a control selector. An empty selector is an invalid argument.
+func ParseControlSelector(s string) (ControlSelector, error) {
+	selector := ControlSelector{Raw: s}
+	trimmed := strings.TrimSpace(s)
+	if trimmed == "" {
+		return ControlSelector{}, NewError(ErrInvalidArgument, "control ID cannot be empty")
+	}
+
+	if match := familyWildcardPattern.FindStringSubmatch(trimmed); match != nil {
+		selector.family = NormalizeFamilyID(match[1])
+		return selector, nil
+	}
+
+	if match := enhancementWildcardPattern.FindStringSubmatch(trimmed); match != nil {
+		id, err := ParseControlID(match[1])
+		if err != nil {
+			return ControlSelector{}, err
+		}
+		selector.from, selector.to, selector.withEnh = id.Base(), id.Base(), true
+		return selector, nil
+	}
+
+	if match := controlRangePattern.FindStringSubmatch(trimmed); match != nil {
+		from, err := ParseControlID(match[1])
+		if err != nil {
+			return ControlSelector{}, err
+		}
+		to, err := ParseControlID(match[2])
+		if err != nil {
+			return ControlSelector{}, err
+		}
+		if from.Family != to.Family {
+			return ControlSelector{}, NewError(ErrInvalidArgument, "invalid range %q: both ends must be in the same family", s)
+		}
+		if to.Number < from.Number || (to.Number == from.Number && to.Enhancement < from.Enhancement) {
+			return ControlSelector{}, NewError(ErrInvalidArgument, "invalid range %q: the range ends before it starts", s)
+		}
+		selector.from, selector.to = from, to
+		return selector, nil
+	}
+
+	id, err := ParseControlID(trimmed)
+	if err != nil {
+		// Not a NIST control ID, so match it literally
+		selector.literal = NormalizeControlID(trimmed)
+		return selector, nil
+	}
+	selector.from, selector.to = id, id
+	return selector, nil
+}
+
+// Matches reports whether the selector selects a control ID
+func (s ControlSelector) Matches(controlID string) bool {
+	if s.literal != "" {
+		return NormalizeControlID(controlID) == s.literal
+	}
+
+	id, err := ParseControlID(controlID)
+	if err != nil {
+		return false
+	}
+	if s.family != "" {
+		return id.Family == s.family
+	}
+	if id.Family != s.from.Family {
+		return false
+	}
+	if s.withEnh {
+		return id.Number == s.from.Number
+	}
+	// A range of base controls does not include their enhancements
+	if id.IsEnhancement() && !s.from.IsEnhancement() && !s.to.IsEnhancement() {
+		return false
+	}
+	return !id.less(s.from) && !s.to.less(id)
+}
+
+// single reports whether the selector selects at most one control
+func (s ControlSelector) single() bool {
+	return s.literal != "" || (s.family == "" && !s.withEnh && s.from == s.to)
+}
+
+// less reports whether a control ID sorts before another in the same family
+func (id ControlID) less(other ControlID) bool {
+	if id.Number != other.Number {
+		return id.Number < other.Number
+	}
+	return id.Enhancement < other.Enhancement
+}
+
+// ControlSelection is the result of selecting controls from a program
+type ControlSelection struct {
+	Controls []Control `json:"controls"`
+	Unknown  []string  `json:"unknown"` // Selectors that did not match any control
+}
+
+// SelectControls returns the controls in a program matched by the selectors, in the order the
+// selectors were given and then in program order. Each control is returned once. Ranges and
+// wildcards skip withdrawn controls unless includeWithdrawn is set, while a selector for a single
+// control always returns it so that callers can learn where its requirements went.
+func SelectControls(program Program, selectors []ControlSelector, includeWithdrawn bool) ControlSelection {
+	selection := ControlSelection{Controls: []Control{}, Unknown: []string{}}
+	selected := make(map[string]bool)
+
+	for _, selector := range selectors {
+		matched := false
+		for _, family := range program.Families {
+			for _, control := range family.Controls {
+				if !selector.Matches(control.ID) {
+					continue
+				}
+				matched = true
+				if control.IsWithdrawn() && !includeWithdrawn && !selector.single() {
+					continue
+				}
+				if !selected[control.ID] {
+					selected[control.ID] = true
+					selection.Controls = append(selection.Controls, control)
+				}
+			}
+		}
+		if !matched {
+			selection.Unknown = append(selection.Unknown, selector.Raw)
+		}
+	}
+
+	return selection
+}
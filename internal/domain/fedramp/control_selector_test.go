@@ -0,0 +1,120 @@
+package fedramp
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestControlSelectorMatches(t *testing.T) {
+	tests := []struct {
+		selector string
+		matches  []string
+		misses   []string
+	}{
+		// Single controls
+		{"AC-2", []string{"ac-2", "AC-02"}, []string{"ac-2.1", "ac-3"}},
+		{"AC-2(1)", []string{"ac-2.1"}, []string{"ac-2", "ac-2.10"}},
+
+		// Ranges of base controls leave out their enhancements
+		{"AC-2 through AC-4", []string{"ac-2", "ac-3", "ac-4"}, []string{"ac-1", "ac-5", "ac-3.1", "au-3"}},
+		{"AC-2 thru AC-4", []string{"ac-3"}, []string{"ac-5"}},
+		{"ac-2 to ac-4", []string{"ac-3"}, []string{"ac-5"}},
+		{"AC-2..AC-4", []string{"ac-4"}, []string{"ac-1"}},
+		{"AC-2–AC-4", []string{"ac-2"}, []string{"ac-5"}},
+		{"AC-3 through AC-3", []string{"ac-3"}, []string{"ac-3.1"}},
+
+		// Ranges with enhancements at either end include the enhancements between them
+		{"AC-2(2) through AC-2(4)", []string{"ac-2.2", "ac-2.3", "ac-2.4"}, []string{"ac-2", "ac-2.1", "ac-2.5"}},
+		{"AC-2(12) through AC-3", []string{"ac-2.12", "ac-2.13", "ac-3"}, []string{"ac-2.11", "ac-3.1"}},
+
+		// Family wildcards
+		{"AC-*", []string{"ac-1", "ac-2.1", "ac-25"}, []string{"au-1"}},
+		{"ac(*)", []string{"ac-2"}, []string{"au-2"}},
+		{"AC *", []string{"ac-2"}, []string{"au-2"}},
+
+		// Enhancement wildcards select the base control and all of its enhancements
+		{"AC-2(*)", []string{"ac-2", "ac-2.1", "ac-2.13"}, []string{"ac-20", "ac-3", "ac-21.1"}},
+		{"ac-2.*", []string{"ac-2", "ac-2.5"}, []string{"ac-3"}},
+
+		// Selectors that are not NIST control IDs match literally
+		{"Custom-Control", []string{"custom-control", " CUSTOM-CONTROL "}, []string{"custom-control-2", "ac-2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			selector, err := ParseControlSelector(tt.selector)
+			if err != nil {
+				t.Fatalf("ParseControlSelector(%q) returned error: %v", tt.selector, err)
+			}
+			for _, id := range tt.matches {
+				if !selector.Matches(id) {
+					t.Errorf("ParseControlSelector(%q).Matches(%q) = false, want true", tt.selector, id)
+				}
+			}
+			for _, id := range tt.misses {
+				if selector.Matches(id) {
+					t.Errorf("ParseControlSelector(%q).Matches(%q) = true, want false", tt.selector, id)
+				}
+			}
+		})
+	}
+}
+
+func TestParseControlSelectorInvalid(t *testing.T) {
+	tests := []string{
+		"",
+		"   ",
+		"AC-6 through AC-2",       // reversed range
+		"AC-2(4) through AC-2(2)", // reversed range of enhancements
+		"AC-2 through AU-6",       // cross-family range
+		"AC-2 through nothing",    // range end that is not a control ID
+		"AC-0(*)",                 // wildcard on an invalid control
+	}
+	for _, input := range tests {
+		t.Run(input, func(t *testing.T) {
+			if selector, err := ParseControlSelector(input); err == nil {
+				t.Errorf("ParseControlSelector(%q) = %+v, want an error", input, selector)
+			} else if KindOf(err) != ErrInvalidArgument {
+				t.Errorf("ParseControlSelector(%q) error kind = %s, want %s", input, KindOf(err), ErrInvalidArgument)
+			}
+		})
+	}
+}
+
+func TestSelectControls(t *testing.T) {
+	program := Program{Name: "Test", Families: []ControlFamily{{ID: "ac", Controls: []Control{
+		{ID: "ac-1"}, {ID: "ac-2"}, {ID: "ac-2.1"}, {ID: "ac-2.2", Status: "withdrawn"}, {ID: "ac-3"},
+	}}}}
+
+	tests := []struct {
+		selectors        []string
+		includeWithdrawn bool
+		want             []string
+		unknown          []string
+	}{
+		{[]string{"AC-2(*)"}, false, []string{"ac-2", "ac-2.1"}, nil},
+		{[]string{"AC-2(*)"}, true, []string{"ac-2", "ac-2.1", "ac-2.2"}, nil},
+		// A single withdrawn control is returned so callers can see where it went
+		{[]string{"AC-2(2)"}, false, []string{"ac-2.2"}, nil},
+		// Controls are returned once, in the order of the selectors
+		{[]string{"AC-3", "AC-*"}, false, []string{"ac-3", "ac-1", "ac-2", "ac-2.1"}, nil},
+		{[]string{"AC-2(7)", "AC-9 through AC-12", "ac-1"}, false, []string{"ac-1"}, []string{"AC-2(7)", "AC-9 through AC-12"}},
+	}
+	for _, tt := range tests {
+		selectors := make([]ControlSelector, len(tt.selectors))
+		for i, s := range tt.selectors {
+			var err error
+			if selectors[i], err = ParseControlSelector(s); err != nil {
+				t.Fatalf("ParseControlSelector(%q) returned error: %v", s, err)
+			}
+		}
+		selection := SelectControls(program, selectors, tt.includeWithdrawn)
+		var got []string
+		for _, control := range selection.Controls {
+			got = append(got, control.ID)
+		}
+		if !slices.Equal(got, tt.want) || !slices.Equal(selection.Unknown, tt.unknown) {
+			t.Errorf("SelectControls(%q, %v) = %v (unknown %v), want %v (unknown %v)", tt.selectors, tt.includeWithdrawn, got, selection.Unknown, tt.want, tt.unknown)
+		}
+	}
+}
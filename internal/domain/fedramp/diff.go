@@ -0,0 +1,115 @@
+package fedramp
+
+import (
+	"slices"
+)
+
+// ProgramDiff describes how the controls of one program differ from those of another, e.g. what
+// moving from FedRAMP Moderate to FedRAMP High adds
+type ProgramDiff struct {
+	From    string           `json:"from"`
+	To      string           `json:"to"`
+	Added   []ControlSummary `json:"added"`   // Controls only in the To program
+	Removed []ControlSummary `json:"removed"` // Controls only in the From program
+	Changed []ControlChange  `json:"changed"` // Controls in both programs whose parameter values differ
+}
+
+// ControlSummary identifies a control by its ID and title
+type ControlSummary struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+// ControlChange describes how the parameters of a control differ between two programs
+type ControlChange struct {
+	ID         string            `json:"id"`
+	Title      string            `json:"title"`
+	Parameters []ParameterChange `json:"parameters"`
+}
+
+// ParameterChange describes a parameter whose values differ between two programs
+type ParameterChange struct {
+	ID    string   `json:"id"`
+	Label string   `json:"label,omitempty"`
+	From  []string `json:"from"`
+	To    []string `json:"to"`
+}
+
+// DiffPrograms compares the active controls of two programs, listing controls in program order
+func DiffPrograms(from, to Program) ProgramDiff {
+	diff := ProgramDiff{
+		From:    from.Name,
+		To:      to.Name,
+		Added:   []ControlSummary{},
+		Removed: []ControlSummary{},
+		Changed: []ControlChange{},
+	}
+
+	fromControls := activeControlsByID(from)
+	toControls := activeControlsByID(to)
+
+	for _, family := range to.Families {
+		for _, control := range ActiveControls(family.Controls) {
+			previous, ok := fromControls[control.ID]
+			if !ok {
+				diff.Added = append(diff.Added, ControlSummary{ID: control.ID, Title: control.Title})
+				continue
+			}
+			if changes := parameterChanges(previous.Parameters, control.Parameters); len(changes) > 0 {
+				diff.Changed = append(diff.Changed, ControlChange{ID: control.ID, Title: control.Title, Parameters: changes})
+			}
+		}
+	}
+	for _, family := range from.Families {
+		for _, control := range ActiveControls(family.Controls) {
+			if _, ok := toControls[control.ID]; !ok {
+				diff.Removed = append(diff.Removed, ControlSummary{ID: control.ID, Title: control.Title})
+			}
+		}
+	}
+
+	return diff
+}
+
+// activeControlsByID indexes the controls of a program that have not been withdrawn
+func activeControlsByID(program Program) map[string]Control {
+	controls := make(map[string]Control)
+	for _, family := range program.Families {
+		for _, control := range ActiveControls(family.Controls) {
+			controls[control.ID] = control
+		}
+	}
+	return controls
+}
+
+// parameterChanges lists the parameters whose values differ, in the order of the newer control.
+// A parameter that only one control has is compared against no values.
+func parameterChanges(from, to []ControlParameter) []ParameterChange {
+	fromValues := make(map[string][]string, len(from))
+	for _, param := range from {
+		fromValues[param.ID] = param.Values
+	}
+
+	var changes []ParameterChange
+	seen := make(map[string]bool, len(to))
+	for _, param := range to {
+		seen[param.ID] = true
+		if !slices.Equal(fromValues[param.ID], param.Values) {
+			changes = append(changes, ParameterChange{ID: param.ID, Label: param.Label, From: nonNil(fromValues[param.ID]), To: nonNil(param.Values)})
+		}
+	}
+	for _, param := range from {
+		if !seen[param.ID] && len(param.Values) > 0 {
+			changes = append(changes, ParameterChange{ID: param.ID, Label: param.Label, From: param.Values, To: []string{}})
+		}
+	}
+	return changes
+}
+
+// nonNil returns an empty slice in place of nil so that JSON output has [] rather than null
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -0,0 +1,84 @@
+package fedramp
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrorKind classifies errors so that callers can report them consistently
+type ErrorKind string
+
+const (
+	// ErrNotFound means the requested control, family or other object does not exist
+	ErrNotFound ErrorKind = "not_found"
+	// ErrInvalidArgument means the caller supplied a missing or malformed argument
+	ErrInvalidArgument ErrorKind = "invalid_argument"
+	// ErrProgramUnavailable means the requested compliance program is unknown or its data cannot be loaded
+	ErrProgramUnavailable ErrorKind = "program_unavailable"
+	// ErrInternal means an unexpected failure that the caller cannot fix
+	ErrInternal ErrorKind = "internal"
+)
+
+// Error is an error with a kind and optional suggestions for how the caller can correct it
+type Error struct {
+	Kind        ErrorKind
+	Message     string
+	Suggestions []string
+	Err         error
+}
+
+// NewError creates a new error of the given kind
+func NewError(kind ErrorKind, format string, args ...any) *Error {
+	return &Error{Kind: kind, Message: fmt.Sprintf(format, args...)}
+}
+
+// WrapError creates a new error of the given kind that wraps an underlying error
+func WrapError(kind ErrorKind, err error, format string, args ...any) *Error {
+	return &Error{Kind: kind, Message: fmt.Sprintf(format, args...), Err: err}
+}
+
+// WithSuggestions returns the error with suggestions for values the caller may have meant
+func (e *Error) WithSuggestions(suggestions ...string) *Error {
+	e.Suggestions = suggestions
+	return e
+}
+
+// FilterSuggestions returns a copy of an error that only keeps the suggestions for which keep
+// returns true. Errors without suggestions are returned unchanged.
+func FilterSuggestions(err error, keep func(string) bool) error {
+	var e *Error
+	if !errors.As(err, &e) || len(e.Suggestions) == 0 {
+		return err
+	}
+	filtered := *e
+	filtered.Suggestions = slices.DeleteFunc(slices.Clone(e.Suggestions), func(s string) bool { return !keep(s) })
+	return &filtered
+}
+
+// Error returns the error message, followed by the underlying error and any suggestions
+func (e *Error) Error() string {
+	message := e.Message
+	if e.Err != nil {
+		message += ": " + e.Err.Error()
+	}
+	if len(e.Suggestions) > 0 {
+		message += fmt.Sprintf(" (did you mean %s?)", strings.Join(e.Suggestions, ", "))
+	}
+	return message
+}
+
+// Unwrap returns the underlying error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// KindOf returns the kind of an error. Errors that are not classified are internal.
+func KindOf(err error) ErrorKind {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind
+	}
+	return ErrInternal
+}
@@ -0,0 +1,156 @@
+package fedramp
+
+import (
+	"slices"
+	"strings"
+)
+
+// Assessment methods defined by NIST SP 800-53A
+const (
+	MethodExamine   = "EXAMINE"
+	MethodInterview = "INTERVIEW"
+	MethodTest      = "TEST"
+)
+
+// EvidenceGuidance describes the evidence an assessor expects for a control, with one entry per
+// determination statement in the control's assessment objectives
+type EvidenceGuidance struct {
+	Methods        []string                `json:"methods,omitempty"` // Assessment methods used for the control as a whole
+	Determinations []EvidenceDetermination `json:"determinations"`
+}
+
+// EvidenceDetermination is the evidence expected for a single determination statement
+type EvidenceDetermination struct {
+	ID            string   `json:"id,omitempty"`
+	Label         string   `json:"label,omitempty"`
+	Statement     string   `json:"statement"`
+	Methods       []string `json:"methods"`
+	ArtifactTypes []string `json:"artifactTypes"`
+	Objects       []string `json:"objects,omitempty"` // Assessment objects, e.g. documents and roles, for the methods
+}
+
+// artifactKeywords maps words in a determination statement to the kinds of artifact that usually
+// evidence it, checked in order
+var artifactKeywords = []struct {
+	keywords []string
+	artifact string
+}{
+	{[]string{"policy", "policies"}, "Policy document"},
+	{[]string{"procedure"}, "Procedure document"},
+	{[]string{"plan"}, "Plan document, such as the system security plan"},
+	{[]string{"train"}, "Training records"},
+	{[]string{"account"}, "Account listings and access records"},
+	{[]string{"audit", "log"}, "Audit logs and audit records"},
+	{[]string{"configur", "setting", "baseline"}, "Configuration settings or baseline exports"},
+	{[]string{"review", "update"}, "Review records showing dates and reviewers"},
+	{[]string{"approv", "authoriz"}, "Approval and authorization records"},
+	{[]string{"notif", "report"}, "Notification and reporting records"},
+	{[]string{"monitor", "alert"}, "Monitoring records and alerts"},
+	{[]string{"agreement", "contract"}, "Signed agreements"},
+	{[]string{"incident"}, "Incident records"},
+	{[]string{"backup"}, "Backup logs"},
+	{[]string{"scan", "vulnerab"}, "Vulnerability scan reports"},
+	{[]string{"inventor"}, "Inventory records"},
+	{[]string{"risk"}, "Risk assessment reports"},
+	{[]string{"test", "exercise"}, "Test and exercise results"},
+	{[]string{"banner", "display", "screen"}, "Screenshots of the system"},
+}
+
+// BuildEvidenceGuidance builds the evidence guidance for a control from its assessment objectives.
+// Every objective with prose and no nested objectives is a determination statement, labelled with
+// the labels of the objectives above it, e.g. "a.1.". Statements without methods of their own use
+// the methods of the nearest objective above them, or the control's methods. objects lists the
+// assessment objects for each method, if known.
+func BuildEvidenceGuidance(objectives []AssessmentObjective, parameters []ControlParameter, objects map[string][]string) EvidenceGuidance {
+	guidance := EvidenceGuidance{Determinations: []EvidenceDetermination{}}
+
+	var walk func(objective AssessmentObjective, label string, inherited []string)
+	walk = func(objective AssessmentObjective, label string, inherited []string) {
+		label += objective.Label
+		methods := objectiveMethods(objective)
+		for _, method := range methods {
+			if !slices.Contains(guidance.Methods, method) {
+				guidance.Methods = append(guidance.Methods, method)
+			}
+		}
+		if len(methods) == 0 {
+			methods = inherited
+		}
+
+		prose := strings.TrimSpace(objective.Prose)
+		if prose != "" && len(objective.Parts) == 0 {
+			guidance.Determinations = append(guidance.Determinations, EvidenceDetermination{
+				ID:        objective.ID,
+				Label:     label,
+				Statement: ResolveParameters(prose, parameters),
+				Methods:   methods,
+			})
+		}
+		for _, part := range objective.Parts {
+			walk(part, label, methods)
+		}
+	}
+	for _, objective := range objectives {
+		walk(objective, "", nil)
+	}
+	for _, method := range []string{MethodExamine, MethodInterview, MethodTest} {
+		if len(objects[method]) > 0 && !slices.Contains(guidance.Methods, method) {
+			guidance.Methods = append(guidance.Methods, method)
+		}
+	}
+
+	// Fill in methods, artifact types and objects once every method of the control is known
+	for i := range guidance.Determinations {
+		determination := &guidance.Determinations[i]
+		if len(determination.Methods) == 0 {
+			determination.Methods = guidance.Methods
+		}
+		determination.Methods = append([]string{}, determination.Methods...)
+		determination.ArtifactTypes = suggestArtifactTypes(determination.Statement, determination.Methods)
+		for _, method := range determination.Methods {
+			for _, object := range objects[method] {
+				if !slices.Contains(determination.Objects, object) {
+					determination.Objects = append(determination.Objects, object)
+				}
+			}
+		}
+	}
+
+	return guidance
+}
+
+// objectiveMethods returns the distinct assessment methods of an objective, in order
+func objectiveMethods(objective AssessmentObjective) []string {
+	var methods []string
+	for _, method := range objective.Methods {
+		value := strings.ToUpper(strings.TrimSpace(method.Value))
+		if value != "" && !slices.Contains(methods, value) {
+			methods = append(methods, value)
+		}
+	}
+	return methods
+}
+
+// suggestArtifactTypes suggests the kinds of artifact that evidence a determination statement
+// assessed with the given methods
+func suggestArtifactTypes(statement string, methods []string) []string {
+	artifacts := []string{}
+	lower := strings.ToLower(statement)
+	if slices.Contains(methods, MethodExamine) || len(methods) == 0 {
+		for _, entry := range artifactKeywords {
+			if slices.ContainsFunc(entry.keywords, func(keyword string) bool { return strings.Contains(lower, keyword) }) {
+				artifacts = append(artifacts, entry.artifact)
+			}
+		}
+		if len(artifacts) == 0 {
+			artifacts = append(artifacts, "Documentation describing the implementation")
+		}
+	}
+	if slices.Contains(methods, MethodInterview) {
+		artifacts = append(artifacts, "Interview notes with the personnel responsible")
+	}
+	if slices.Contains(methods, MethodTest) {
+		artifacts = append(artifacts, "Test results or screenshots showing the mechanism in operation")
+	}
+	return artifacts
+}
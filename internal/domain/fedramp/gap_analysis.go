@@ -0,0 +1,246 @@
+package fedramp
+
+import (
+	"cmp"
+	"encoding/csv"
+	"io"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Gap analysis statuses of controls without an implementation status of their own
+const (
+	StatusInherited   = "inherited"    // The control is inherited from a leveraged authorization
+	StatusNotRecorded = "not-recorded" // The control has no implementation record, or one without a status
+)
+
+// GapStatuses lists the statuses counted by a gap analysis, from addressed to unaddressed
+var GapStatuses = []string{
+	string(StatusImplemented),
+	StatusInherited,
+	string(StatusAlternative),
+	string(StatusNotApplicable),
+	string(StatusPartial),
+	string(StatusPlanned),
+	StatusNotRecorded,
+}
+
+// GapAnalysis compares a system's implementation records with the controls of a program
+type GapAnalysis struct {
+	System    string        `json:"system"`
+	Program   string        `json:"program"`
+	Total     int           `json:"total"`     // Active controls of the program
+	Addressed int           `json:"addressed"` // Controls that are implemented, inherited, implemented by an alternative or not applicable
+	Readiness float64       `json:"readiness"` // Percentage of controls addressed
+	Statuses  []StatusCount `json:"statuses"`
+	Families  []FamilyGaps  `json:"families"`
+	Gaps      []ControlGap  `json:"gaps"` // Controls that are not addressed, most connected first
+}
+
+// StatusCount is the number and percentage of controls with a status
+type StatusCount struct {
+	Status  string  `json:"status"`
+	Count   int     `json:"count"`
+	Percent float64 `json:"percent"`
+}
+
+// FamilyGaps summarizes the gap analysis of a control family
+type FamilyGaps struct {
+	ID        string        `json:"id"`
+	Title     string        `json:"title"`
+	Total     int           `json:"total"`
+	Addressed int           `json:"addressed"`
+	Readiness float64       `json:"readiness"`
+	Statuses  []StatusCount `json:"statuses"`
+}
+
+// ControlGap is a control the system has not addressed
+type ControlGap struct {
+	ID                    string   `json:"id"`
+	Title                 string   `json:"title"`
+	Family                string   `json:"family"`
+	Status                string   `json:"status"`                          // partial, planned or not-recorded
+	UnaddressedStatements []string `json:"unaddressedStatements,omitempty"` // Statements without a narrative that are not inherited
+	FanOut                int      `json:"fanOut"`                          // Number of related controls in the program
+	RelatedControls       []string `json:"relatedControls,omitempty"`
+}
+
+// controlMentionPattern matches a control ID mentioned in prose, e.g. "AC-3" or "SI-4(2)"
+var controlMentionPattern = regexp.MustCompile(`\b[A-Z]{2}-\d+(?:\(\d+\))?`)
+
+// RelatedControlIDs returns the controls related to a control: those the catalog lists as related,
+// or, for catalogs without related links, the controls its statement and guidance mention
+func (c Control) RelatedControlIDs() []string {
+	if len(c.RelatedControls) > 0 {
+		return c.RelatedControls
+	}
+	text := c.FullText + "\n" + c.Guidance
+	for _, statement := range c.Statements {
+		text += "\n" + statementText(statement)
+	}
+	var related []string
+	for _, mention := range controlMentionPattern.FindAllString(text, -1) {
+		if id := NormalizeControlID(mention); id != NormalizeControlID(c.ID) && !slices.Contains(related, id) {
+			related = append(related, id)
+		}
+	}
+	return related
+}
+
+// statementText returns the prose of a statement and its parts
+func statementText(statement ControlStatement) string {
+	text := statement.Prose
+	for _, part := range statement.Parts {
+		text += "\n" + statementText(part)
+	}
+	return text
+}
+
+// BuildGapAnalysis compares a system's implementation records with the active controls of a
+// program. Controls that are implemented, inherited from a leveraged authorization, implemented by
+// an alternative or not applicable are addressed, as are controls without a record that an overlay
+// marked not applicable; the others are gaps, ranked by how many related controls they have in the
+// program, as gaps in widely related controls hold back the most.
+func BuildGapAnalysis(program Program, records []ImplementationRecord, system string) GapAnalysis {
+	recordsByControl := make(map[string]ImplementationRecord, len(records))
+	for _, record := range records {
+		recordsByControl[record.ControlID] = record
+	}
+
+	// Relate the program's controls in both directions
+	related := make(map[string][]string)
+	relate := func(a, b string) {
+		if !slices.Contains(related[a], b) {
+			related[a] = append(related[a], b)
+		}
+	}
+	for _, family := range program.Families {
+		for _, control := range ActiveControls(family.Controls) {
+			for _, id := range control.RelatedControlIDs() {
+				if other, ok := program.FindControl(id); ok && !other.IsWithdrawn() && other.ID != control.ID {
+					relate(control.ID, other.ID)
+					relate(other.ID, control.ID)
+				}
+			}
+		}
+	}
+
+	analysis := GapAnalysis{System: system, Program: program.Name, Gaps: []ControlGap{}}
+	overall := make(map[string]int)
+	for _, family := range program.Families {
+		controls := ActiveControls(family.Controls)
+		if len(controls) == 0 {
+			continue
+		}
+		counts := make(map[string]int)
+		for _, control := range controls {
+			record, recorded := recordsByControl[control.ID]
+			status := StatusNotRecorded
+			switch {
+			case recorded && record.Inherited(control):
+				status = StatusInherited
+			case recorded && record.Status != "":
+				status = string(record.Status)
+			case control.NotApplicable():
+				status = string(StatusNotApplicable)
+			}
+			counts[status]++
+			overall[status]++
+			if addressed(status) {
+				continue
+			}
+
+			gap := ControlGap{
+				ID:              control.ID,
+				Title:           control.Title,
+				Family:          family.ID,
+				Status:          status,
+				FanOut:          len(related[control.ID]),
+				RelatedControls: slices.SortedFunc(slices.Values(related[control.ID]), CompareControlIDs),
+			}
+			for _, statement := range control.ImplementationStatements() {
+				responsibility := record.StatementResponsibility(statement.ID)
+				inherited := responsibility != nil && responsibility.Role == ResponsibilityInherited
+				if !inherited && record.StatementNarrative(statement.ID) == "" {
+					gap.UnaddressedStatements = append(gap.UnaddressedStatements, statement.ID)
+				}
+			}
+			analysis.Gaps = append(analysis.Gaps, gap)
+		}
+
+		summary := FamilyGaps{ID: family.ID, Title: family.Title, Total: len(controls), Statuses: statusCounts(counts, len(controls))}
+		summary.Addressed = addressedCount(counts)
+		summary.Readiness = percent(summary.Addressed, summary.Total)
+		analysis.Families = append(analysis.Families, summary)
+		analysis.Total += len(controls)
+	}
+	analysis.Statuses = statusCounts(overall, analysis.Total)
+	analysis.Addressed = addressedCount(overall)
+	analysis.Readiness = percent(analysis.Addressed, analysis.Total)
+
+	slices.SortStableFunc(analysis.Gaps, func(a, b ControlGap) int {
+		return cmp.Or(
+			cmp.Compare(b.FanOut, a.FanOut),
+			cmp.Compare(slices.Index(GapStatuses, b.Status), slices.Index(GapStatuses, a.Status)),
+			CompareControlIDs(a.ID, b.ID),
+		)
+	})
+	return analysis
+}
+
+// addressed reports whether a control with a gap analysis status needs no further work
+func addressed(status string) bool {
+	return status == string(StatusImplemented) || status == StatusInherited || status == string(StatusAlternative) || status == string(StatusNotApplicable)
+}
+
+// addressedCount returns the number of addressed controls from counts by status
+func addressedCount(counts map[string]int) int {
+	return counts[string(StatusImplemented)] + counts[StatusInherited] + counts[string(StatusAlternative)] + counts[string(StatusNotApplicable)]
+}
+
+// statusCounts returns the count and percentage of every gap analysis status
+func statusCounts(counts map[string]int, total int) []StatusCount {
+	statuses := make([]StatusCount, 0, len(GapStatuses))
+	for _, status := range GapStatuses {
+		statuses = append(statuses, StatusCount{Status: status, Count: counts[status], Percent: percent(counts[status], total)})
+	}
+	return statuses
+}
+
+// percent returns part as a percentage of total, rounded to one decimal place
+func percent(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(part)*1000/float64(total)) / 10
+}
+
+// WriteCSV writes the gap analysis as CSV with one row per gap, in rank order
+func (a GapAnalysis) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"rank", "control", "title", "family", "status", "fan_out", "unaddressed_statements", "related_controls"})
+	for i, gap := range a.Gaps {
+		related := make([]string, 0, len(gap.RelatedControls))
+		for _, id := range gap.RelatedControls {
+			related = append(related, ControlLabel(id))
+		}
+		writer.Write([]string{
+			strconv.Itoa(i + 1),
+			ControlLabel(gap.ID),
+			gap.Title,
+			strings.ToUpper(gap.Family),
+			gap.Status,
+			strconv.Itoa(gap.FanOut),
+			strings.Join(gap.UnaddressedStatements, " "),
+			strings.Join(related, " "),
+		})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return WrapError(ErrInternal, err, "failed to write the gap analysis as CSV")
+	}
+	return nil
+}
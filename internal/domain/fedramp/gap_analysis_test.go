@@ -0,0 +1,93 @@
+package fedramp
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// gapTestProgram returns a program whose controls are related by the catalog's links, by mentions
+// in their guidance and by a withdrawn control whose links are not counted
+func gapTestProgram() Program {
+	return Program{Name: "FedRAMP Moderate", Families: []ControlFamily{
+		{ID: "ac", Title: "Access Control", Controls: []Control{
+			{ID: "ac-1"},
+			{ID: "ac-2", RelatedControls: []string{"ac-3", "ac-6", "au-2", "sc-7"}},
+			{ID: "ac-3", RelatedControls: []string{"ac-2"}, Statements: []ControlStatement{{ID: "ac-3_smt", Name: "statement", Parts: []ControlStatement{
+				{ID: "ac-3_smt.a", Name: "item", Label: "a."},
+				{ID: "ac-3_smt.b", Name: "item", Label: "b."},
+			}}}},
+			{ID: "ac-4"},
+			{ID: "ac-6", Guidance: "Least privilege applies to the accounts of AC-2 and to the events of AU-2."},
+			{ID: "ac-7", Status: StatusWithdrawn, RelatedControls: []string{"ac-2", "ac-8"}},
+			{ID: "ac-8"},
+		}},
+		{ID: "au", Title: "Audit and Accountability", Controls: []Control{
+			{ID: "au-2"},
+			{ID: "au-3"},
+		}},
+	}}
+}
+
+func TestBuildGapAnalysis(t *testing.T) {
+	program := gapTestProgram()
+	record := func(controlID string, status ImplementationStatus) ImplementationRecord {
+		return ImplementationRecord{ImplementationKey: ImplementationKey{System: "Acme Cloud", Program: program.Name, ControlID: controlID}, Status: status}
+	}
+	inherited := record("au-3", "")
+	inherited.Responsibility = &Responsibility{Role: ResponsibilityInherited, LeveragedAuthorization: "IaaS P-ATO"}
+	partial := record("ac-3", StatusPartial)
+	partial.Statements = map[string]string{"ac-3_smt.a": "Access is enforced by the directory."}
+	records := []ImplementationRecord{record("ac-1", StatusImplemented), inherited, partial, record("au-2", StatusPlanned)}
+
+	analysis := BuildGapAnalysis(program, records, "Acme Cloud")
+
+	if analysis.Total != 8 || analysis.Addressed != 2 || analysis.Readiness != 25 {
+		t.Errorf("total = %d, addressed = %d, readiness = %v, want 8, 2 and 25", analysis.Total, analysis.Addressed, analysis.Readiness)
+	}
+
+	// Gaps are ranked by fan-out, then the least addressed status, then control ID
+	want := []struct {
+		id     string
+		status string
+		fanOut int
+	}{
+		{"ac-2", StatusNotRecorded, 3},
+		{"ac-6", StatusNotRecorded, 2},
+		{"au-2", string(StatusPlanned), 2},
+		{"ac-3", string(StatusPartial), 1},
+		{"ac-4", StatusNotRecorded, 0},
+		{"ac-8", StatusNotRecorded, 0},
+	}
+	if len(analysis.Gaps) != len(want) {
+		t.Fatalf("gaps = %+v, want %d", analysis.Gaps, len(want))
+	}
+	for i, gap := range analysis.Gaps {
+		if gap.ID != want[i].id || gap.Status != want[i].status || gap.FanOut != want[i].fanOut {
+			t.Errorf("gap %d = %s %s with fan-out %d, want %s %s with fan-out %d", i+1, gap.ID, gap.Status, gap.FanOut, want[i].id, want[i].status, want[i].fanOut)
+		}
+	}
+
+	// Related controls outside the program and withdrawn controls are not counted
+	if got := analysis.Gaps[0].RelatedControls; !slices.Equal(got, []string{"ac-3", "ac-6", "au-2"}) {
+		t.Errorf("ac-2 related controls = %v, want ac-3, ac-6 and au-2", got)
+	}
+	if got := analysis.Gaps[3].UnaddressedStatements; !slices.Equal(got, []string{"ac-3_smt.b"}) {
+		t.Errorf("ac-3 unaddressed statements = %v, want ac-3_smt.b", got)
+	}
+}
+
+func TestGapAnalysisWriteCSV(t *testing.T) {
+	analysis := BuildGapAnalysis(gapTestProgram(), nil, "Acme Cloud")
+	var csv strings.Builder
+	if err := analysis.WriteCSV(&csv); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(csv.String()), "\n")
+	if len(lines) != len(analysis.Gaps)+1 {
+		t.Fatalf("CSV has %d lines, want a heading and %d gaps", len(lines), len(analysis.Gaps))
+	}
+	if want := "1,AC-2,,AC,not-recorded,3,,AC-3 AC-6 AU-2"; lines[1] != want {
+		t.Errorf("first row = %q, want %q", lines[1], want)
+	}
+}
@@ -0,0 +1,172 @@
+package fedramp
+
+import (
+	"strings"
+	"time"
+)
+
+// ImplementationStatus is how far a system has implemented a control. The values are the OSCAL
+// implementation-status states.
+type ImplementationStatus string
+
+const (
+	StatusPlanned       ImplementationStatus = "planned"
+	StatusPartial       ImplementationStatus = "partial"
+	StatusImplemented   ImplementationStatus = "implemented"
+	StatusAlternative   ImplementationStatus = "alternative"
+	StatusNotApplicable ImplementationStatus = "not-applicable"
+)
+
+// ImplementationStatuses lists the names of the implementation statuses
+var ImplementationStatuses = []string{
+	string(StatusPlanned),
+	string(StatusPartial),
+	string(StatusImplemented),
+	string(StatusAlternative),
+	string(StatusNotApplicable),
+}
+
+// ParseImplementationStatus parses an implementation status, accepting spaces or underscores in
+// place of hyphens, e.g. "not applicable"
+func ParseImplementationStatus(s string) (ImplementationStatus, error) {
+	normalized := strings.ToLower(strings.Join(strings.FieldsFunc(s, func(r rune) bool {
+		return r == ' ' || r == '_' || r == '-'
+	}), "-"))
+	for _, status := range ImplementationStatuses {
+		if normalized == status {
+			return ImplementationStatus(status), nil
+		}
+	}
+	if normalized == "n/a" || normalized == "na" {
+		return StatusNotApplicable, nil
+	}
+	return "", NewError(ErrInvalidArgument, "unknown implementation status %q (valid statuses: %s)", s, strings.Join(ImplementationStatuses, ", ")).
+		WithSuggestions(ClosestMatches(s, ImplementationStatuses)...)
+}
+
+// ImplementationKey identifies the implementation of a control by a system under a program
+type ImplementationKey struct {
+	System    string `json:"system"`
+	Program   string `json:"program"`
+	ControlID string `json:"controlId"`
+}
+
+// ImplementationRecord records how a system implements a control
+type ImplementationRecord struct {
+	ImplementationKey
+	Status           ImplementationStatus `json:"status,omitempty"`           // Empty until a status is recorded, e.g. for a record of ODP values only
+	ResponsibleRoles []string             `json:"responsibleRoles,omitempty"` // Roles responsible for the control, e.g. "System Owner"
+	Narrative        string               `json:"narrative,omitempty"`        // How the system implements the control
+	Statements       map[string]string    `json:"statements,omitempty"`       // Narratives for individual statements, by statement ID, e.g. "ac-2_smt.a"
+	Parameters       map[string][]string  `json:"parameters,omitempty"`       // Values the system sets for parameters, by parameter ID
+	Components       []string             `json:"components,omitempty"`       // Components that implement the control, by title
+	Responsibility   *Responsibility      `json:"responsibility,omitempty"`   // Who implements the control, e.g. inherited from a leveraged authorization
+	// Responsibilities for individual statements that differ from the control's, by statement ID
+	StatementResponsibilities map[string]Responsibility `json:"statementResponsibilities,omitempty"`
+	CreatedAt                 time.Time                 `json:"createdAt,omitzero"`
+	UpdatedAt                 time.Time                 `json:"updatedAt,omitzero"`
+}
+
+// ImplementationUpdate describes changes to an implementation record. Empty and nil fields keep
+// the values already recorded. Statement narratives, parameter values and statement
+// responsibilities are merged into the recorded ones, and an empty narrative, list of values or
+// responsibility role removes a statement's narrative, a parameter's values or a statement's
+// responsibility. A responsibility with an empty role removes the control's responsibility.
+type ImplementationUpdate struct {
+	Status                    ImplementationStatus
+	ResponsibleRoles          []string
+	Narrative                 *string
+	Statements                map[string]string
+	Parameters                map[string][]string
+	Components                []string
+	Responsibility            *Responsibility
+	StatementResponsibilities map[string]Responsibility
+}
+
+// IsEmpty reports whether the record records nothing besides its key and timestamps
+func (r ImplementationRecord) IsEmpty() bool {
+	return r.Status == "" && len(r.ResponsibleRoles) == 0 && r.Narrative == "" && len(r.Statements) == 0 &&
+		len(r.Parameters) == 0 && len(r.Components) == 0 && r.Responsibility == nil && len(r.StatementResponsibilities) == 0
+}
+
+// StatementNarrative returns the narrative for a statement, falling back to the narrative of the
+// control as a whole
+func (r ImplementationRecord) StatementNarrative(statementID string) string {
+	if narrative := r.Statements[statementID]; narrative != "" {
+		return narrative
+	}
+	return r.Narrative
+}
+
+// ParameterValues returns the values of a control parameter for the system: the values the system
+// sets, or otherwise the values set by the program
+func (r ImplementationRecord) ParameterValues(param ControlParameter) []string {
+	if values := r.Parameters[param.ID]; len(values) > 0 {
+		return values
+	}
+	return param.Values
+}
+
+// ImplementationFilter selects implementation records. Empty fields match every record.
+type ImplementationFilter struct {
+	System  string
+	Program string
+	Status  ImplementationStatus
+}
+
+// Matches reports whether a record is selected by the filter
+func (f ImplementationFilter) Matches(record ImplementationRecord) bool {
+	return (f.System == "" || f.System == record.System) &&
+		(f.Program == "" || strings.EqualFold(f.Program, record.Program)) &&
+		(f.Status == "" || f.Status == record.Status)
+}
+
+// CompareImplementationKeys orders implementation keys by system, program and then control in
+// catalog order, e.g. AC-2 before AC-2(1) before AC-10
+func CompareImplementationKeys(a, b ImplementationKey) int {
+	if c := strings.Compare(a.System, b.System); c != 0 {
+		return c
+	}
+	if c := strings.Compare(a.Program, b.Program); c != 0 {
+		return c
+	}
+	return CompareControlIDs(a.ControlID, b.ControlID)
+}
+
+// CompareControlIDs orders control IDs by family, number and enhancement. IDs that are not NIST
+// control IDs sort after those that are, in string order.
+func CompareControlIDs(a, b string) int {
+	idA, errA := ParseControlID(a)
+	idB, errB := ParseControlID(b)
+	switch {
+	case errA != nil && errB != nil:
+		return strings.Compare(a, b)
+	case errA != nil:
+		return 1
+	case errB != nil:
+		return -1
+	}
+	if c := strings.Compare(idA.Family, idB.Family); c != 0 {
+		return c
+	}
+	switch {
+	case idA.less(idB):
+		return -1
+	case idB.less(idA):
+		return 1
+	}
+	return 0
+}
+
+// FindControl returns the control of a program with an ID written in any common notation
+func (p Program) FindControl(controlID string) (Control, bool) {
+	normalized := NormalizeControlID(controlID)
+	for _, family := range p.Families {
+		for _, control := range family.Controls {
+			if NormalizeControlID(control.ID) == normalized {
+				return control, true
+			}
+		}
+	}
+	return Control{}, false
+}
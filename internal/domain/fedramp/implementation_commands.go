@@ -0,0 +1,144 @@
+package fedramp
+
+// GetImplementationCommand is a command to get the implementation record of a control
+type GetImplementationCommand struct {
+	Program Program
+	Key     ImplementationKey
+}
+
+// SetImplementationCommand is a command to create or update the implementation record of a control
+type SetImplementationCommand struct {
+	Program Program
+	Key     ImplementationKey
+	Update  ImplementationUpdate
+}
+
+// ListImplementationsCommand is a command to list the implementation records selected by a filter
+type ListImplementationsCommand struct {
+	Filter ImplementationFilter
+}
+
+// ExportSSPCommand is a command to generate the OSCAL system security plan of a system
+type ExportSSPCommand struct {
+	Program Program
+	Options SSPOptions
+}
+
+// ImportSSPCommand is a command to record the implementations described by an OSCAL system
+// security plan
+type ImportSSPCommand struct {
+	Program Program
+	SSP     OSCALSystemSecurityPlan
+	System  string // Name of the system; the plan's system name if empty
+	DryRun  bool   // Only report what would be imported
+}
+
+// AssignParameterCommand is a command to assign a system's values to a parameter of a program
+type AssignParameterCommand struct {
+	Program     Program
+	System      string
+	ParameterID string
+	Values      []string // Values to assign; none removes the assignment
+}
+
+// ParameterReportCommand is a command to report the parameters of a program a system has not
+// assigned values to
+type ParameterReportCommand struct {
+	Program Program
+	System  string
+}
+
+// GapAnalysisCommand is a command to compare a system's implementation records with a program
+type GapAnalysisCommand struct {
+	Program Program
+	System  string
+}
+
+// LintNarrativeCommand is a command to check the implementation narrative of a control against
+// the control's statement parts. The narrative is the system's recorded one unless a draft is given.
+type LintNarrativeCommand struct {
+	Program    Program
+	Key        ImplementationKey // The system may be empty when linting a draft
+	Narrative  *string           // Draft narrative of the control, in place of the recorded one
+	Statements map[string]string // Draft narratives of individual statements, in place of the recorded ones
+}
+
+// SetResponsibilityCommand is a command to record who is responsible for a control or one of its
+// statements
+type SetResponsibilityCommand struct {
+	Program        Program
+	Key            ImplementationKey
+	StatementID    string // Statement of the control, e.g. "a"; the control as a whole if empty
+	Responsibility Responsibility
+}
+
+// ImportCRMCommand is a command to record the responsibilities described by a leveraged system's
+// customer responsibility matrix (CRM)
+type ImportCRMCommand struct {
+	Program                Program
+	CRM                    []byte // The CRM as CSV
+	System                 string
+	LeveragedAuthorization string // Name of the leveraged system's authorization
+	DryRun                 bool   // Only report what would be imported
+}
+
+// ExportCRMCommand is a command to write the customer responsibility matrix of a system
+type ExportCRMCommand struct {
+	Program Program
+	System  string
+}
+
+// SetComponentCommand is a command to create or update a component
+type SetComponentCommand struct {
+	Name   string
+	Update ComponentUpdate
+}
+
+// MapComponentCommand is a command to map a component to a control it implements, or to remove
+// the mapping
+type MapComponentCommand struct {
+	Program    Program
+	Component  string
+	ControlID  string
+	Narrative  string            // How the component implements the control
+	Statements map[string]string // Statements the component satisfies, with how it satisfies each
+	Remove     bool              // Remove the mapping instead
+}
+
+// ComponentsForControlCommand is a command to list the components that implement a control
+type ComponentsForControlCommand struct {
+	Program   Program
+	ControlID string
+}
+
+// ControlsForComponentCommand is a command to get a component with the controls it implements
+type ControlsForComponentCommand struct {
+	Component string
+	Programs  []string // Programs whose controls are listed
+}
+
+// ImportComponentDefinitionCommand is a command to record the components of an OSCAL component
+// definition
+type ImportComponentDefinitionCommand struct {
+	Definition     OSCALComponentDefinition
+	Programs       []Program // Programs the definition's controls may belong to
+	DefaultProgram string    // Program of control implementations whose source is not a known program
+	DryRun         bool      // Only report what would be imported
+}
+
+// ExportComponentDefinitionCommand is a command to generate the OSCAL component definition of
+// components
+type ExportComponentDefinitionCommand struct {
+	Components []string // Names of the components; every component if empty
+	Programs   []string // Programs whose controls are included
+}
+
+// GeneratePolicyDocumentsCommand is a command to generate the policy and procedure documents of
+// control families from templates
+type GeneratePolicyDocumentsCommand struct {
+	Program   Program
+	System    string                        // System whose ODP assignments are used; the program's values only if empty
+	Families  []string                      // Control families; every family with a policy and procedures control if empty
+	Kinds     []PolicyDocumentKind          // Documents to generate for each family
+	Templates map[PolicyDocumentKind]string // Template texts to use in place of the stored templates
+}
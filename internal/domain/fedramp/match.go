@@ -0,0 +1,187 @@
+package fedramp
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// maxSuggestions is the number of suggestions offered for a value that was not found
+const maxSuggestions = 3
+
+// Match quality, from best to worst. Lower is better.
+const (
+	matchExact = iota
+	matchPrefix
+	matchSubsequence
+	matchTypo
+	noMatch
+)
+
+// MatchCandidates ranks the candidates that match a partial or mistyped value, best match first.
+// Values are compared by a key that ignores case, punctuation and leading zeros, so "AC-02",
+// "ac2" and "AC 2" all match "ac-2". Candidates match if their key equals the value's key,
+// starts with it, contains its characters in order, or is within a small edit distance of it.
+// Candidates of equal quality keep their original order. An empty value matches every candidate.
+func MatchCandidates(value string, candidates []string) []string {
+	key := matchKey(value)
+	if key == "" {
+		return append([]string(nil), candidates...)
+	}
+
+	type match struct {
+		candidate string
+		quality   int
+		distance  int
+	}
+	var matches []match
+	for _, candidate := range candidates {
+		candidateKey := matchKey(candidate)
+		quality, distance := matchQuality(key, candidateKey)
+		if quality != noMatch {
+			matches = append(matches, match{candidate, quality, distance})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].quality != matches[j].quality {
+			return matches[i].quality < matches[j].quality
+		}
+		return matches[i].distance < matches[j].distance
+	})
+
+	results := make([]string, 0, len(matches))
+	for _, m := range matches {
+		results = append(results, m.candidate)
+	}
+	return results
+}
+
+// ClosestMatches returns the few candidates that best match a value, for "did you mean" suggestions
+func ClosestMatches(value string, candidates []string) []string {
+	if matchKey(value) == "" {
+		return nil
+	}
+	matches := MatchCandidates(value, candidates)
+	return matches[:min(len(matches), maxSuggestions)]
+}
+
+// ClosestControls returns the few controls that best match a control ID, by their labels. The
+// control itself comes first, then its base control and the other enhancements of that base, and
+// then the controls whose IDs are otherwise close, so "AC-02" suggests AC-2(1) before AC-21.
+func ClosestControls(controlID string, controlIDs []string) []string {
+	if matchKey(controlID) == "" {
+		return nil
+	}
+	matches := MatchCandidates(controlID, controlIDs)
+
+	if id, err := ParseControlID(controlID); err == nil {
+		rank := func(candidate string) int {
+			c, err := ParseControlID(candidate)
+			switch {
+			case err != nil || c.Base() != id.Base():
+				return 3
+			case c == id:
+				return 0
+			case c == id.Base():
+				return 1
+			}
+			return 2
+		}
+		// Enhancements of the same base are suggested even if their numbers are not close
+		for _, candidate := range controlIDs {
+			if rank(candidate) < 3 && !slices.Contains(matches, candidate) {
+				matches = append(matches, candidate)
+			}
+		}
+		slices.SortStableFunc(matches, func(a, b string) int { return rank(a) - rank(b) })
+	}
+
+	matches = matches[:min(len(matches), maxSuggestions)]
+	for i, id := range matches {
+		matches[i] = ControlLabel(id)
+	}
+	return matches
+}
+
+// matchQuality returns how well a candidate key matches a value key, and a distance used to
+// order candidates of the same quality
+func matchQuality(key, candidateKey string) (int, int) {
+	switch {
+	case candidateKey == key:
+		return matchExact, 0
+	case strings.HasPrefix(candidateKey, key):
+		return matchPrefix, len(candidateKey) - len(key)
+	case isSubsequence(key, candidateKey):
+		return matchSubsequence, len(candidateKey) - len(key)
+	}
+	if distance := editDistance(key, candidateKey); distance <= max(1, len(key)/3) {
+		return matchTypo, distance
+	}
+	return noMatch, 0
+}
+
+// matchKey normalizes a value for matching: letters are lowercased, punctuation and spaces are
+// dropped and leading zeros are removed from numbers. Consecutive numbers are kept apart with a
+// dot, so "AC-2(1)" and "ac-2.1" share the key "ac2.1" while "AC-21" has the key "ac21".
+func matchKey(value string) string {
+	var key strings.Builder
+	inNumber, separated := false, false
+	runes := []rune(strings.ToLower(value))
+	for i, r := range runes {
+		switch {
+		case unicode.IsLetter(r):
+			key.WriteRune(r)
+			inNumber, separated = false, false
+		case unicode.IsDigit(r):
+			// Drop a leading zero unless it is the last digit of the number
+			nextIsDigit := i+1 < len(runes) && unicode.IsDigit(runes[i+1])
+			if r == '0' && !inNumber && nextIsDigit {
+				continue
+			}
+			if separated {
+				key.WriteRune('.')
+			}
+			key.WriteRune(r)
+			inNumber, separated = true, false
+		default:
+			// A separator ends a number; remember it if it follows one
+			separated = separated || inNumber
+			inNumber = false
+		}
+	}
+	return key.String()
+}
+
+// isSubsequence reports whether the characters of s appear in t in order
+func isSubsequence(s, t string) bool {
+	i := 0
+	for j := 0; i < len(s) && j < len(t); j++ {
+		if s[i] == t[j] {
+			i++
+		}
+	}
+	return i == len(s)
+}
+
+// editDistance returns the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
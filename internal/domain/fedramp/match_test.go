@@ -0,0 +1,29 @@
+package fedramp
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestClosestControls(t *testing.T) {
+	controlIDs := []string{"ac-2", "ac-2.1", "ac-21", "au-2", "sc-7"}
+
+	tests := []struct {
+		controlID string
+		want      []string
+	}{
+		{"AC-02", []string{"AC-2", "AC-2(1)", "AC-21"}},
+		{"ac-2(1)", []string{"AC-2(1)", "AC-2", "AC-21"}},
+		// An enhancement that is not in the program suggests its base control first
+		{"AC-2(7)", []string{"AC-2", "AC-2(1)"}},
+		{"ac-21(1)", []string{"AC-21", "AC-2(1)"}},
+		{"sc7", []string{"SC-7"}},
+		{"custom", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := ClosestControls(tt.controlID, controlIDs); !slices.Equal(got, tt.want) {
+			t.Errorf("ClosestControls(%q) = %q, want %q", tt.controlID, got, tt.want)
+		}
+	}
+}
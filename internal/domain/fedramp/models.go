@@ -2,19 +2,29 @@ package fedramp
 
 // ControlParameter represents a parameter for a control
 type ControlParameter struct {
-	ID         string   `json:"id"`
-	Label      string   `json:"label,omitempty"`
-	Guidelines []string `json:"guidelines,omitempty"`
+	ID          string              `json:"id"`
+	Label       string              `json:"label,omitempty"`
+	Guidelines  []string            `json:"guidelines,omitempty"`
+	Values      []string            `json:"values,omitempty"`      // Values set for the parameter by the baseline, if any
+	Select      *ParameterSelection `json:"select,omitempty"`      // Choices the value is selected from, if any
+	Constraints []string            `json:"constraints,omitempty"` // Constraints on the value, e.g. "at least every 90 days"
+	LegacyID    string              `json:"legacyId,omitempty"`    // ID of the parameter in earlier catalogs, e.g. "ac-2_prm_1"
 }
 
-// ControlStatement represents a statement or requirement in a control
+// ParameterSelection is the set of choices a parameter's value is selected from
+type ParameterSelection struct {
+	HowMany string   `json:"howMany,omitempty"` // "one" or "one-or-more"; one if empty
+	Choices []string `json:"choices"`
+}
+
+// ControlStatement represents a statement or requirement in a control. Statements form a tree:
+// each part keeps its own label (e.g. "a.", "1." or "(a)") and its nested parts in order.
 type ControlStatement struct {
-	ID       string             `json:"id"`
-	Name     string             `json:"name"`
-	Prose    string             `json:"prose,omitempty"`
-	Parts    []ControlStatement `json:"parts,omitempty"`
-	Label    string             `json:"label,omitempty"`
-	SubParts []ControlStatement `json:"subParts,omitempty"`
+	ID    string             `json:"id"`
+	Name  string             `json:"name"`
+	Prose string             `json:"prose,omitempty"`
+	Parts []ControlStatement `json:"parts,omitempty"`
+	Label string             `json:"label,omitempty"`
 }
 
 // AssessmentMethod represents a method for assessing a control
@@ -23,10 +33,12 @@ type AssessmentMethod struct {
 	Value string `json:"value"`
 }
 
-// AssessmentObjective represents an objective for assessing a control
+// AssessmentObjective represents an objective for assessing a control. Objectives form a tree
+// whose leaves are the determination statements an assessor checks.
 type AssessmentObjective struct {
 	ID      string                `json:"id"`
 	Name    string                `json:"name"`
+	Label   string                `json:"label,omitempty"`
 	Prose   string                `json:"prose,omitempty"`
 	Methods []AssessmentMethod    `json:"methods,omitempty"`
 	Parts   []AssessmentObjective `json:"parts,omitempty"`
@@ -41,7 +53,12 @@ type Control struct {
 	Guidance             string                `json:"guidance,omitempty"`
 	AssessmentObjectives []AssessmentObjective `json:"assessmentObjectives,omitempty"`
 	FullText             string                `json:"fullText,omitempty"`         // Combined prose text of the control
-	EvidenceGuidance     string                `json:"evidenceGuidance,omitempty"` // Guidance for evidence collection
+	EvidenceGuidance     EvidenceGuidance      `json:"evidenceGuidance,omitzero"`  // Evidence expected for each determination statement
+	Status               string                `json:"status,omitempty"`           // "withdrawn" for controls that are no longer part of the catalog
+	IncorporatedInto     []string              `json:"incorporatedInto,omitempty"` // Controls that now contain the requirements of a withdrawn control
+	MovedTo              string                `json:"movedTo,omitempty"`          // Control that a withdrawn control was moved to
+	RelatedControls      []string              `json:"relatedControls,omitempty"`  // Controls the catalog lists as related
+	Tailoring            *ControlTailoring     `json:"tailoring,omitempty"`        // How an overlay tailored the control, in a derived program
 	SearchIndex          string                `json:"-"`                          // Combined text for searching (not included in JSON output)
 }
 
@@ -54,8 +71,10 @@ type ControlFamily struct {
 
 // Program represents a compliance program
 type Program struct {
-	Name     string          `json:"name"`
-	Families []ControlFamily `json:"families"`
+	Name        string          `json:"name"`
+	Base        string          `json:"base,omitempty"`        // Program an overlay tailored into this one, if any
+	Description string          `json:"description,omitempty"` // Description of a tailored program
+	Families    []ControlFamily `json:"families"`
 }
 
 // OSCALCatalog represents the structure of the OSCAL catalog
@@ -66,45 +85,62 @@ type OSCALCatalog struct {
 			Title string `json:"title"`
 		} `json:"metadata"`
 		Groups []struct {
-			ID       string `json:"id"`
-			Class    string `json:"class"`
-			Title    string `json:"title"`
-			Controls []struct {
-				ID     string `json:"id"`
-				Class  string `json:"class"`
-				Title  string `json:"title"`
-				Params []struct {
-					ID         string `json:"id"`
-					Label      string `json:"label,omitempty"`
-					Guidelines []struct {
-						Prose string `json:"prose,omitempty"`
-					} `json:"guidelines,omitempty"`
-				} `json:"params,omitempty"`
-				Parts []struct {
-					ID    string `json:"id"`
-					Name  string `json:"name"`
-					Prose string `json:"prose,omitempty"`
-					Parts []struct {
-						ID    string `json:"id"`
-						Name  string `json:"name"`
-						Prose string `json:"prose,omitempty"`
-						Props []struct {
-							Name  string `json:"name"`
-							Value string `json:"value"`
-						} `json:"props,omitempty"`
-						Parts []struct {
-							ID    string `json:"id"`
-							Name  string `json:"name"`
-							Prose string `json:"prose,omitempty"`
-							Parts []struct {
-								ID    string `json:"id"`
-								Name  string `json:"name"`
-								Prose string `json:"prose,omitempty"`
-							} `json:"parts,omitempty"`
-						} `json:"parts,omitempty"`
-					} `json:"parts,omitempty"`
-				} `json:"parts,omitempty"`
-			} `json:"controls"`
+			ID       string         `json:"id"`
+			Class    string         `json:"class"`
+			Title    string         `json:"title"`
+			Controls []OSCALControl `json:"controls"`
 		} `json:"groups"`
 	} `json:"catalog"`
 }
+
+// OSCALControl represents a control of an OSCAL catalog. Control enhancements, such as AC-2(1), are
+// nested in the controls of their base control.
+type OSCALControl struct {
+	ID     string `json:"id"`
+	Class  string `json:"class"`
+	Title  string `json:"title"`
+	Params []struct {
+		ID         string `json:"id"`
+		Label      string `json:"label,omitempty"`
+		Guidelines []struct {
+			Prose string `json:"prose,omitempty"`
+		} `json:"guidelines,omitempty"`
+		Values []string `json:"values,omitempty"`
+		Select *struct {
+			HowMany string   `json:"how-many,omitempty"`
+			Choice  []string `json:"choice,omitempty"`
+		} `json:"select,omitempty"`
+		Constraints []struct {
+			Description string `json:"description,omitempty"`
+		} `json:"constraints,omitempty"`
+		Props []OSCALProperty `json:"props,omitempty"`
+	} `json:"params,omitempty"`
+	Props    []OSCALProperty `json:"props,omitempty"`
+	Links    []OSCALLink     `json:"links,omitempty"`
+	Parts    []OSCALPart     `json:"parts,omitempty"`
+	Controls []OSCALControl  `json:"controls,omitempty"`
+}
+
+// OSCALPart represents a part of an OSCAL control, such as a statement item, guidance or an
+// assessment objective, along with its nested parts
+type OSCALPart struct {
+	ID    string          `json:"id"`
+	Name  string          `json:"name"`
+	Prose string          `json:"prose,omitempty"`
+	Props []OSCALProperty `json:"props,omitempty"`
+	Parts []OSCALPart     `json:"parts,omitempty"`
+}
+
+// OSCALProperty represents a name/value property of an OSCAL object
+type OSCALProperty struct {
+	Name    string `json:"name"`
+	Ns      string `json:"ns,omitempty"` // Namespace of properties defined outside OSCAL, e.g. by FedRAMP
+	Value   string `json:"value"`
+	Remarks string `json:"remarks,omitempty"`
+}
+
+// OSCALLink represents a link from an OSCAL object to another resource or control
+type OSCALLink struct {
+	Href string `json:"href"`
+	Rel  string `json:"rel,omitempty"`
+}
@@ -0,0 +1,423 @@
+package fedramp
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// NarrativeFindingKind is the kind of problem the narrative linter reports
+type NarrativeFindingKind string
+
+const (
+	FindingNoNarrative         NarrativeFindingKind = "no-narrative"            // The control has no narrative at all
+	FindingMissingPart         NarrativeFindingKind = "missing-part"            // A statement part is not addressed
+	FindingUnlabelledPart      NarrativeFindingKind = "unlabelled-part"         // A part is only covered by unlabelled text
+	FindingMissingValue        NarrativeFindingKind = "missing-parameter-value" // A part's parameter value is not mentioned
+	FindingUnassignedParameter NarrativeFindingKind = "unassigned-parameter"    // A part's parameter has no value to mention
+	FindingVagueLanguage       NarrativeFindingKind = "vague-language"          // Wording where FedRAMP expects specifics
+)
+
+// PartCoverage is how a narrative covers a statement part
+type PartCoverage string
+
+const (
+	CoverageAddressed     PartCoverage = "addressed"      // Described by its own statement narrative or a labelled section
+	CoverageImplied       PartCoverage = "implied"        // Only the unlabelled narrative uses the part's terms
+	CoverageMissing       PartCoverage = "missing"        // Not described at all
+	CoverageInherited     PartCoverage = "inherited"      // Inherited from a leveraged authorization
+	CoverageNotApplicable PartCoverage = "not-applicable" // The control is not applicable
+)
+
+// NarrativeLint reports how well the implementation narrative of a control covers the control's
+// statement parts
+type NarrativeLint struct {
+	System    string              `json:"system,omitempty"`
+	Program   string              `json:"program"`
+	ControlID string              `json:"controlId"`
+	Title     string              `json:"title"`
+	Addressed int                 `json:"addressed"` // Parts that are addressed, inherited or not applicable
+	Total     int                 `json:"total"`
+	Findings  []NarrativeFinding  `json:"findings"` // Findings about the narrative as a whole
+	Parts     []NarrativePartLint `json:"parts"`
+}
+
+// NarrativePartLint reports how a narrative covers one statement part of a control
+type NarrativePartLint struct {
+	StatementID string             `json:"statementId"`
+	Label       string             `json:"label,omitempty"`
+	Coverage    PartCoverage       `json:"coverage"`
+	Findings    []NarrativeFinding `json:"findings"`
+}
+
+// NarrativeFinding is a problem the linter found in a narrative
+type NarrativeFinding struct {
+	Kind        NarrativeFindingKind `json:"kind"`
+	Message     string               `json:"message"`
+	ParameterID string               `json:"parameterId,omitempty"`
+	Phrase      string               `json:"phrase,omitempty"` // Vague phrase as written
+}
+
+// FindingCount returns the number of findings about the narrative and its parts
+func (l NarrativeLint) FindingCount() int {
+	count := len(l.Findings)
+	for _, part := range l.Parts {
+		count += len(part.Findings)
+	}
+	return count
+}
+
+// vaguePhrase is wording that leaves out what FedRAMP reviewers expect to be stated, with what to
+// state instead
+type vaguePhrase struct {
+	phrase     string
+	suggestion string
+}
+
+// vaguePhrases lists vague wording, longer phrases before the phrases they contain
+var vaguePhrases = []vaguePhrase{
+	{"as appropriate", "state who decides and on what criteria"},
+	{"as needed", "state the conditions that call for it"},
+	{"as necessary", "state the conditions that call for it"},
+	{"when necessary", "state the conditions that call for it"},
+	{"as required", "state the requirement"},
+	{"where applicable", "state where it applies"},
+	{"if applicable", "state where it applies"},
+	{"on a regular basis", "state the frequency, e.g. \"weekly\""},
+	{"periodically", "state the frequency, e.g. \"every 30 days\""},
+	{"regularly", "state the frequency, e.g. \"weekly\""},
+	{"routinely", "state the frequency, e.g. \"daily\""},
+	{"from time to time", "state the frequency, e.g. \"quarterly\""},
+	{"in a timely manner", "state the time frame, e.g. \"within 24 hours\""},
+	{"timely", "state the time frame, e.g. \"within 24 hours\""},
+	{"promptly", "state the time frame, e.g. \"within one hour\""},
+	{"industry best practices", "name the standard or benchmark followed"},
+	{"best practices", "name the standard or benchmark followed"},
+	{"industry standard", "name the standard followed"},
+	{"adequate", "state the measure that makes it adequate"},
+	{"sufficient", "state the measure that makes it sufficient"},
+	{"reasonable", "state the measure that makes it reasonable"},
+	{"etc", "list every item"},
+	{"and so on", "list every item"},
+	{"should", "state what the system does; a narrative describes the implementation, not an intention"},
+}
+
+// vaguePattern matches any of the vague phrases as whole words
+var vaguePattern = func() *regexp.Regexp {
+	phrases := make([]string, len(vaguePhrases))
+	for i, vague := range vaguePhrases {
+		phrases[i] = strings.ReplaceAll(regexp.QuoteMeta(vague.phrase), " ", `\s+`)
+	}
+	return regexp.MustCompile(`(?i)\b(?:` + strings.Join(phrases, "|") + `)\b`)
+}()
+
+// stopWords are words too common in controls and narratives to tell whether a narrative describes
+// a statement part
+var stopWords = []string{
+	"about", "after", "also", "based", "been", "before", "being", "between", "control", "controls",
+	"defined", "during", "each", "ensure", "ensures", "following", "from", "have", "including",
+	"into", "must", "only", "organization", "organizational", "other", "over", "shall", "such",
+	"system", "systems", "than", "that", "their", "them", "then", "there", "these", "they", "this",
+	"those", "through", "under", "upon", "when", "where", "which", "will", "with", "within",
+}
+
+// LintNarrative checks the implementation narrative of a control against the control's statement
+// parts. A part is addressed by its own statement narrative or by a section of the control's
+// narrative that starts with its label, e.g. "a.", "(b)", "Part c:" or "AC-2(d)", at the start of
+// a line or sentence. A part with neither is implied when the unlabelled narrative uses enough of
+// its terms, and missing otherwise. The text describing each part must mention the values the
+// system assigns to, or the program sets for, the part's parameters, and vague language is
+// reported where it appears.
+func LintNarrative(control Control, record ImplementationRecord) NarrativeLint {
+	lint := NarrativeLint{
+		System:    record.System,
+		Program:   record.Program,
+		ControlID: control.ID,
+		Title:     control.Title,
+		Findings:  []NarrativeFinding{},
+		Parts:     []NarrativePartLint{},
+	}
+	statements := control.ImplementationStatements()
+	preamble, sections := splitNarrative(control, statements, record.Narrative)
+	notApplicable := record.Status == StatusNotApplicable || (record.Status == "" && control.NotApplicable())
+	parameters := record.AssignParameters(control).Parameters
+
+	hasStatementNarratives := slices.ContainsFunc(statements, func(statement ControlStatement) bool {
+		return strings.TrimSpace(record.Statements[statement.ID]) != ""
+	})
+	if strings.TrimSpace(record.Narrative) == "" && !hasStatementNarratives {
+		lint.Findings = append(lint.Findings, NarrativeFinding{
+			Kind:    FindingNoNarrative,
+			Message: ControlLabel(control.ID) + " has no implementation narrative",
+		})
+	}
+	lint.Findings = append(lint.Findings, vagueLanguage(preamble)...)
+
+	for _, statement := range statements {
+		part := NarrativePartLint{StatementID: statement.ID, Label: statement.Label, Findings: []NarrativeFinding{}}
+		responsibility := record.StatementResponsibility(statement.ID)
+		name := partName(statement)
+
+		text := strings.TrimSpace(record.Statements[statement.ID])
+		if text == "" {
+			text = sections[statement.ID]
+		}
+		switch {
+		case notApplicable:
+			part.Coverage = CoverageNotApplicable
+		case text != "":
+			part.Coverage = CoverageAddressed
+			part.Findings = append(part.Findings, vagueLanguage(text)...)
+		case responsibility != nil && responsibility.Role == ResponsibilityInherited:
+			part.Coverage = CoverageInherited
+		case len(statements) == 1 && preamble != "":
+			part.Coverage = CoverageAddressed
+			text = preamble
+		case preamble != "" && sharesTerms(preamble, statementText(statement)):
+			part.Coverage = CoverageImplied
+			text = preamble
+			part.Findings = append(part.Findings, NarrativeFinding{
+				Kind:    FindingUnlabelledPart,
+				Message: fmt.Sprintf("%s is only covered by unlabelled text; describe it in a section labelled %q or in its own statement narrative", capitalize(name), statement.Label),
+			})
+		default:
+			part.Coverage = CoverageMissing
+			part.Findings = append(part.Findings, NarrativeFinding{
+				Kind:    FindingMissingPart,
+				Message: fmt.Sprintf("%s is not addressed: %s", capitalize(name), ResolveParameters(strings.Join(strings.Fields(statementText(statement)), " "), parameters)),
+			})
+		}
+
+		if part.Coverage == CoverageAddressed || part.Coverage == CoverageImplied {
+			part.Findings = append(part.Findings, parameterFindings(statement, parameters, text)...)
+		}
+		if part.Coverage != CoverageImplied && part.Coverage != CoverageMissing {
+			lint.Addressed++
+		}
+		lint.Parts = append(lint.Parts, part)
+	}
+	lint.Total = len(lint.Parts)
+	return lint
+}
+
+// splitNarrative splits a narrative into the sections that start with the labels of statement
+// parts, by statement ID, and the text that belongs to no part
+func splitNarrative(control Control, statements []ControlStatement, narrative string) (string, map[string]string) {
+	statementsByLetter := make(map[string]string, len(statements))
+	for _, statement := range statements {
+		if letter := strings.ToLower(strings.Trim(statement.Label, "().")); letter != "" {
+			statementsByLetter[letter] = statement.ID
+		}
+	}
+
+	type marker struct {
+		start, end  int
+		statementID string
+	}
+	var markers []marker
+	if len(statementsByLetter) > 0 {
+		for _, match := range partLabelPattern(control.ID).FindAllStringSubmatchIndex(narrative, -1) {
+			for group := 1; group < len(match)/2; group++ {
+				if match[2*group] < 0 {
+					continue
+				}
+				letter := strings.ToLower(narrative[match[2*group]:match[2*group+1]])
+				if statementID, ok := statementsByLetter[letter]; ok {
+					markers = append(markers, marker{start: match[0], end: match[1], statementID: statementID})
+				}
+				break
+			}
+		}
+	}
+	if len(markers) == 0 {
+		return strings.TrimSpace(narrative), nil
+	}
+
+	sections := make(map[string]string)
+	for i, m := range markers {
+		end := len(narrative)
+		if i+1 < len(markers) {
+			end = markers[i+1].start
+		}
+		section := strings.TrimSpace(narrative[m.end:end])
+		if section == "" {
+			continue
+		}
+		if sections[m.statementID] != "" {
+			section = sections[m.statementID] + "\n" + section
+		}
+		sections[m.statementID] = section
+	}
+	return strings.TrimSpace(narrative[:markers[0].start]), sections
+}
+
+// partLabelPattern matches the label of a statement part of a control at the start of a line or
+// sentence, e.g. "a.", "(b)", "c)", "Part d:", "AC-2(e)", "AC-2f." or "ac-2_smt.g". The part's
+// letter is in the first group that matched.
+func partLabelPattern(controlID string) *regexp.Regexp {
+	label := strings.Replace(regexp.QuoteMeta(ControlLabel(controlID)), "-", "-0?", 1)
+	label = strings.ReplaceAll(label, `\(`, `\s*\(`)
+	statementID := regexp.QuoteMeta(strings.ToLower(controlID)) + `_smt\.`
+	return regexp.MustCompile(`(?:^|\n|[.;:]\s)[\s#*>-]*(?:` +
+		`(?i:part|item|statement)\s+\(?([a-zA-Z])\)?[.):]?` +
+		`|(?i:` + label + `)\s*(?:\(([a-zA-Z])\)|([a-zA-Z])[.):]?)` +
+		`|(?i:` + statementID + `)([a-zA-Z])` +
+		`|\(([a-z])\)|([a-z])[.):]` +
+		`)\**(?:[.:)]?\s|$)`)
+}
+
+// parameterFindings reports the parameters of a statement part whose values the text describing
+// the part does not mention, and those without values to mention
+func parameterFindings(statement ControlStatement, parameters []ControlParameter, text string) []NarrativeFinding {
+	var findings []NarrativeFinding
+	var paramIDs []string
+	for _, match := range parameterPattern.FindAllStringSubmatch(statementText(statement), -1) {
+		if !slices.Contains(paramIDs, match[1]) {
+			paramIDs = append(paramIDs, match[1])
+		}
+	}
+	for _, paramID := range paramIDs {
+		index := slices.IndexFunc(parameters, func(param ControlParameter) bool { return param.ID == paramID })
+		if index < 0 {
+			continue
+		}
+		param := parameters[index]
+		if len(param.Values) == 0 {
+			findings = append(findings, NarrativeFinding{
+				Kind:        FindingUnassignedParameter,
+				Message:     fmt.Sprintf("%s (%s) has no value from the system or the program, so the narrative cannot state it", param.ID, param.Label),
+				ParameterID: param.ID,
+			})
+			continue
+		}
+		var missing []string
+		for _, value := range param.Values {
+			value = ResolveParameters(value, parameters)
+			if !strings.Contains(value, "[Assignment:") && !mentionsValue(text, value) {
+				missing = append(missing, value)
+			}
+		}
+		if len(missing) > 0 {
+			findings = append(findings, NarrativeFinding{
+				Kind:        FindingMissingValue,
+				Message:     fmt.Sprintf("The narrative does not mention %s, the value of %s (%s)", strings.Join(missing, "; "), param.ID, param.Label),
+				ParameterID: param.ID,
+			})
+		}
+	}
+	return findings
+}
+
+// vagueLanguage reports each vague phrase in a text once
+func vagueLanguage(text string) []NarrativeFinding {
+	var findings []NarrativeFinding
+	var found []string
+	for _, match := range vaguePattern.FindAllString(text, -1) {
+		phrase := strings.ToLower(strings.Join(strings.Fields(match), " "))
+		if slices.Contains(found, phrase) {
+			continue
+		}
+		found = append(found, phrase)
+		index := slices.IndexFunc(vaguePhrases, func(vague vaguePhrase) bool { return vague.phrase == phrase })
+		findings = append(findings, NarrativeFinding{
+			Kind:    FindingVagueLanguage,
+			Message: fmt.Sprintf("%q is vague; %s", match, vaguePhrases[index].suggestion),
+			Phrase:  match,
+		})
+	}
+	return findings
+}
+
+// mentionsValue reports whether a text mentions a parameter value: the value itself, the same
+// period written another way (e.g. "every 30 days" or "monthly" for "30 days"), or, for values
+// written as prose, enough of its terms
+func mentionsValue(text, value string) bool {
+	normalizedText := strings.ToLower(strings.Join(strings.Fields(text), " "))
+	normalizedValue := strings.TrimSuffix(strings.ToLower(strings.Join(strings.Fields(value), " ")), ".")
+	if strings.Contains(normalizedText, normalizedValue) {
+		return true
+	}
+	if period, _, ok := parsePeriod(value); ok {
+		return slices.ContainsFunc(statedPeriods(text), func(stated float64) bool {
+			return math.Abs(stated-period) <= period*0.02
+		})
+	}
+	return sharesTerms(text, value)
+}
+
+// statedPeriods returns every period and frequency stated in a text, in hours
+func statedPeriods(text string) []float64 {
+	var periods []float64
+	for _, match := range periodPattern.FindAllString(text, -1) {
+		if period, _, ok := parsePeriod(match); ok {
+			periods = append(periods, period)
+		}
+	}
+	for _, match := range frequencyPattern.FindAllString(text, -1) {
+		periods = append(periods, periodHours[strings.ToLower(match)])
+	}
+	return periods
+}
+
+// sharesTerms reports whether a text uses at least a third of the significant terms of some prose,
+// and at least two of them when the prose has two or more
+func sharesTerms(text, prose string) bool {
+	terms := significantTerms(parameterPattern.ReplaceAllString(prose, " "))
+	if len(terms) == 0 {
+		return false
+	}
+	textTerms := significantTerms(text)
+	shared := 0
+	for _, term := range terms {
+		if slices.Contains(textTerms, term) {
+			shared++
+		}
+	}
+	return shared >= min(2, len(terms)) && shared*3 >= len(terms)
+}
+
+// significantTerms returns the distinct stems of the words in a text that are long enough and not
+// stop words, so that e.g. "accounts", "account" and "accounting" share a term
+func significantTerms(text string) []string {
+	var terms []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len(word) < 4 || slices.Contains(stopWords, word) {
+			continue
+		}
+		term := word
+		for _, suffix := range []string{"ing", "ed", "es", "s", "e"} {
+			if len(term) > len(suffix)+3 && strings.HasSuffix(term, suffix) {
+				term = strings.TrimSuffix(term, suffix)
+				break
+			}
+		}
+		if len(term) > 6 {
+			term = term[:6]
+		}
+		if !slices.Contains(terms, term) {
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+// partName names a statement part in findings, e.g. "part a" or "the control statement"
+func partName(statement ControlStatement) string {
+	if letter := strings.Trim(statement.Label, "()."); letter != "" {
+		return "part " + letter
+	}
+	return "the control statement"
+}
+
+// capitalize returns text with its first letter in upper case
+func capitalize(text string) string {
+	if text == "" {
+		return text
+	}
+	return strings.ToUpper(text[:1]) + text[1:]
+}
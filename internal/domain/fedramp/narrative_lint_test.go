@@ -0,0 +1,114 @@
+package fedramp
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPartLabelPattern(t *testing.T) {
+	tests := []struct {
+		text string
+		want string // letter of the first label, or empty if there is none
+	}{
+		{"a. Accounts are typed as user, service or emergency.", "a"},
+		{"(b) Each team names its account managers.", "b"},
+		{"b) Each team names its account managers.", "b"},
+		{"Part c: Membership is approved.", "c"},
+		{"**Part c:** Membership is approved.", "c"},
+		{"Item d. Access is authorized.", "d"},
+		{"AC-2(d) Access is authorized.", "d"},
+		{"AC-02 (d): Access is authorized.", "d"},
+		{"ac-2 d. Access is authorized.", "d"},
+		{"AC-2e. Requests are approved.", "e"},
+		{"ac-2_smt.e Requests are approved.", "e"},
+		{"- f. Accounts are disabled.", "f"},
+		{"## g. Usage is monitored.", "g"},
+		{"Accounts are typed.\n(h) Managers are notified.", "h"},
+		{"Accounts are typed. i. Access is authorized.", "i"},
+		{"Accounts are typed; j) reviews are monthly.", "j"},
+
+		// Letters that do not start a line or sentence, and labels of other controls
+		{"The system manages accounts.", ""},
+		{"Accounts are managed as in AC-3 (a) and AC-6.", ""},
+		{"AC-3(a) Access is enforced.", ""},
+		{"A. Smith manages accounts.", ""},
+		{"See e.g. the account policy.", ""},
+	}
+	pattern := partLabelPattern("ac-2")
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			var got string
+			if match := pattern.FindStringSubmatch(tt.text); match != nil {
+				for _, group := range match[1:] {
+					if group != "" {
+						got = group
+						break
+					}
+				}
+			}
+			if got != tt.want {
+				t.Errorf("partLabelPattern(ac-2) in %q = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVagueLanguage(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"Accounts are reviewed periodically and disabled as needed.", []string{"periodically", "as needed"}},
+		{"Logs are reviewed regularly. Alerts are regularly triaged.", []string{"regularly"}},
+		{"Access is revoked As  Appropriate.", []string{"As  Appropriate"}},
+		{"Incidents are reported in a timely manner.", []string{"in a timely manner"}},
+		{"We follow industry best practices, etc.", []string{"industry best practices", "etc"}},
+		{"Administrators should review access.", []string{"should"}},
+		// Words that merely contain a vague phrase
+		{"Shoulder surfing is covered by training; see the etcd cluster runbook.", nil},
+		{"Accounts are reviewed every 30 days and disabled within 24 hours.", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			var got []string
+			for _, finding := range vagueLanguage(tt.text) {
+				if finding.Kind != FindingVagueLanguage || finding.Message == "" {
+					t.Errorf("finding = %+v, want vague language with a message", finding)
+				}
+				got = append(got, finding.Phrase)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("vagueLanguage(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMentionsValue(t *testing.T) {
+	tests := []struct {
+		text, value string
+		want        bool
+	}{
+		{"Accounts are reviewed every 30 days.", "30 days", true},
+		{"Accounts are reviewed every  30\nDays.", "30 days", true},
+
+		// The same period written another way
+		{"Accounts are reviewed monthly.", "30 days", true},
+		{"Accounts are reviewed every thirty (30) days.", "monthly", true},
+		{"Access is reviewed annually.", "365 days", true},
+		{"Access is reviewed every 12 months.", "at least annually", true},
+		{"Accounts are disabled within one (1) day.", "24 hours", true},
+		{"Accounts are reviewed weekly.", "30 days", false},
+		{"Accounts are reviewed every 90 days.", "30 days", false},
+		{"Accounts are reviewed.", "30 days", false},
+
+		// Values written as prose
+		{"The ISSO and the system owner approve new accounts.", "the system owner and the ISSO", true},
+		{"Managers approve new accounts.", "the system owner and the ISSO", false},
+	}
+	for _, tt := range tests {
+		if got := mentionsValue(tt.text, tt.value); got != tt.want {
+			t.Errorf("mentionsValue(%q, %q) = %v, want %v", tt.text, tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,255 @@
+package fedramp
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OSCALComponentDefinition is an OSCAL component definition document
+type OSCALComponentDefinition struct {
+	ComponentDefinition OSCALComponentDefinitionContent `json:"component-definition"`
+}
+
+// OSCALComponentDefinitionContent is the content of an OSCAL component definition
+type OSCALComponentDefinitionContent struct {
+	UUID       string                  `json:"uuid"`
+	Metadata   OSCALMetadata           `json:"metadata"`
+	Components []OSCALDefinedComponent `json:"components,omitempty"`
+}
+
+// OSCALDefinedComponent is a component with the controls it implements
+type OSCALDefinedComponent struct {
+	UUID                   string                                `json:"uuid"`
+	Type                   string                                `json:"type"`
+	Title                  string                                `json:"title"`
+	Description            string                                `json:"description"`
+	ControlImplementations []OSCALComponentControlImplementation `json:"control-implementations,omitempty"`
+}
+
+// OSCALComponentControlImplementation describes how a component implements the controls of a
+// baseline, identified by its source
+type OSCALComponentControlImplementation struct {
+	UUID                    string                      `json:"uuid"`
+	Source                  string                      `json:"source"` // URL of the profile or catalog, or the name of the program
+	Description             string                      `json:"description"`
+	ImplementedRequirements []OSCALComponentRequirement `json:"implemented-requirements"`
+}
+
+// OSCALComponentRequirement describes how a component implements a control
+type OSCALComponentRequirement struct {
+	UUID        string                    `json:"uuid"`
+	ControlID   string                    `json:"control-id"`
+	Description string                    `json:"description"`
+	Statements  []OSCALComponentStatement `json:"statements,omitempty"`
+}
+
+// OSCALComponentStatement describes how a component satisfies a statement of a control
+type OSCALComponentStatement struct {
+	StatementID string `json:"statement-id"`
+	UUID        string `json:"uuid"`
+	Description string `json:"description"`
+}
+
+// ProgramSource returns the source of a program's controls in OSCAL documents: the URL of its
+// FedRAMP profile, or the program's name for programs without one
+func ProgramSource(programName string) string {
+	if profile, ok := fedRAMPProfiles[ProgramImpactLevel(programName)]; ok {
+		return profile
+	}
+	return programName
+}
+
+// BuildComponentDefinition builds an OSCAL component definition of components, with a control
+// implementation for each program whose controls they implement. UUIDs are derived from the
+// component, program and control, so that regenerating a definition keeps them stable.
+func BuildComponentDefinition(components []Component, now time.Time) OSCALComponentDefinition {
+	nameUUID := func(parts ...string) string {
+		return uuid.NewSHA1(oscalNamespace, []byte("component-definition/"+strings.Join(parts, "/"))).String()
+	}
+
+	title := "Component Definition"
+	if len(components) == 1 {
+		title = components[0].Name + " Component Definition"
+	}
+	definition := OSCALComponentDefinitionContent{
+		UUID: nameUUID(append([]string{"definition"}, ComponentNames(components)...)...),
+		Metadata: OSCALMetadata{
+			Title:        title,
+			LastModified: now.UTC().Format(time.RFC3339),
+			Version:      now.UTC().Format("2006-01-02"),
+			OSCALVersion: OSCALVersion,
+		},
+	}
+	for _, component := range components {
+		defined := OSCALDefinedComponent{
+			UUID:        nameUUID(component.Name),
+			Type:        component.Type,
+			Title:       component.Name,
+			Description: component.Description,
+		}
+		if defined.Description == "" {
+			defined.Description = component.Name + "."
+		}
+
+		// Group the mapped controls by program, in the order they are kept
+		var programs []string
+		for _, control := range component.Controls {
+			if !slices.Contains(programs, control.Program) {
+				programs = append(programs, control.Program)
+			}
+		}
+		for _, program := range programs {
+			implementation := OSCALComponentControlImplementation{
+				UUID:        nameUUID(component.Name, program),
+				Source:      ProgramSource(program),
+				Description: "How " + component.Name + " implements the controls of " + program + ".",
+			}
+			for _, control := range component.Controls {
+				if control.Program != program {
+					continue
+				}
+				requirement := OSCALComponentRequirement{
+					UUID:        nameUUID(component.Name, program, control.ControlID),
+					ControlID:   control.ControlID,
+					Description: control.Narrative,
+				}
+				if requirement.Description == "" {
+					requirement.Description = SSPPlaceholderNarrative
+				}
+				for _, statementID := range slices.Sorted(maps.Keys(control.Statements)) {
+					description := control.StatementNarrative(statementID)
+					if description == "" {
+						description = SSPPlaceholderNarrative
+					}
+					requirement.Statements = append(requirement.Statements, OSCALComponentStatement{
+						StatementID: statementID,
+						UUID:        nameUUID(component.Name, program, control.ControlID, statementID),
+						Description: description,
+					})
+				}
+				implementation.ImplementedRequirements = append(implementation.ImplementedRequirements, requirement)
+			}
+			defined.ControlImplementations = append(defined.ControlImplementations, implementation)
+		}
+		definition.Components = append(definition.Components, defined)
+	}
+	return OSCALComponentDefinition{ComponentDefinition: definition}
+}
+
+// ComponentImport is what an OSCAL component definition says about the components it defines
+type ComponentImport struct {
+	DryRun        bool                `json:"dryRun,omitempty"` // Whether the components were left unchanged
+	Components    []ImportedComponent `json:"components"`
+	NotInBaseline []string            `json:"notInBaseline,omitempty"` // Controls the definition maps that are not in their program
+	Warnings      []string            `json:"warnings,omitempty"`      // Parts of the definition that could not be imported
+	Imported      []Component         `json:"-"`                       // The components read from the definition
+}
+
+// ImportedComponent summarizes what was imported for a component
+type ImportedComponent struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Controls int    `json:"controls"` // Number of controls mapped to the component
+}
+
+// ParseOSCALComponentDefinition parses an OSCAL component definition in JSON
+func ParseOSCALComponentDefinition(data []byte) (OSCALComponentDefinition, error) {
+	var definition OSCALComponentDefinition
+	if err := json.Unmarshal(data, &definition); err != nil {
+		return OSCALComponentDefinition{}, WrapError(ErrInvalidArgument, err, "failed to parse the OSCAL component definition")
+	}
+	if definition.ComponentDefinition.UUID == "" && len(definition.ComponentDefinition.Components) == 0 {
+		return OSCALComponentDefinition{}, NewError(ErrInvalidArgument, "the document is not an OSCAL component definition (no component-definition object)")
+	}
+	return definition, nil
+}
+
+// ReadComponentDefinition reads the components of an OSCAL component definition and the controls
+// they implement. Each control implementation is matched to the program whose FedRAMP profile or
+// name is its source, or otherwise to the default program, if any. Narratives written by
+// BuildComponentDefinition for undocumented controls are ignored, and statement narratives that
+// repeat the control's narrative are kept only as the statements the component satisfies.
+func ReadComponentDefinition(definition OSCALComponentDefinition, programs []Program, defaultProgram string) ComponentImport {
+	result := ComponentImport{Components: []ImportedComponent{}}
+	program := func(source string) (Program, bool) {
+		for _, program := range programs {
+			if source != "" && (source == ProgramSource(program.Name) || strings.EqualFold(source, program.Name)) {
+				return program, true
+			}
+		}
+		for _, program := range programs {
+			if defaultProgram != "" && strings.EqualFold(defaultProgram, program.Name) {
+				return program, true
+			}
+		}
+		return Program{}, false
+	}
+
+	for _, defined := range definition.ComponentDefinition.Components {
+		name := strings.TrimSpace(defined.Title)
+		if name == "" {
+			result.Warnings = append(result.Warnings, "component "+defined.UUID+" has no title")
+			continue
+		}
+		componentType, err := ParseComponentType(defined.Type)
+		if err != nil {
+			result.Warnings = append(result.Warnings, name+": "+err.Error()+"; imported as software")
+			componentType = "software"
+		}
+		component := Component{Name: name, Type: componentType, Description: strings.TrimSpace(defined.Description)}
+
+		for _, implementation := range defined.ControlImplementations {
+			program, ok := program(implementation.Source)
+			if !ok {
+				result.Warnings = append(result.Warnings, name+": the controls of "+implementation.Source+" are not in a known program; pass the program they belong to")
+				continue
+			}
+			for _, requirement := range implementation.ImplementedRequirements {
+				control, ok := program.FindControl(requirement.ControlID)
+				if !ok {
+					if !slices.Contains(result.NotInBaseline, requirement.ControlID) {
+						result.NotInBaseline = append(result.NotInBaseline, requirement.ControlID)
+					}
+					continue
+				}
+				mapping := ComponentControl{Program: program.Name, ControlID: control.ID, Narrative: componentNarrative(requirement.Description)}
+				for _, implemented := range requirement.Statements {
+					statement, ok := control.FindImplementationStatement(implemented.StatementID)
+					if !ok {
+						result.Warnings = append(result.Warnings, name+": statement "+implemented.StatementID+" is not a statement of "+ControlLabel(control.ID))
+						continue
+					}
+					if mapping.Statements == nil {
+						mapping.Statements = make(map[string]string)
+					}
+					if narrative := componentNarrative(implemented.Description); narrative != mapping.Narrative {
+						mapping.Statements[statement.ID] = narrative
+					} else {
+						mapping.Statements[statement.ID] = ""
+					}
+				}
+				component.SetControl(mapping)
+			}
+		}
+
+		result.Imported = append(result.Imported, component)
+		result.Components = append(result.Components, ImportedComponent{Name: name, Type: componentType, Controls: len(component.Controls)})
+	}
+	slices.SortFunc(result.NotInBaseline, CompareControlIDs)
+	return result
+}
+
+// componentNarrative returns a narrative read from a component definition, without the
+// placeholder written for undocumented controls
+func componentNarrative(description string) string {
+	description = strings.TrimSpace(description)
+	if description == SSPPlaceholderNarrative {
+		return ""
+	}
+	return description
+}
@@ -0,0 +1,581 @@
+package fedramp
+
+import (
+	"cmp"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OSCALVersion is the version of the OSCAL schemas that generated documents conform to
+const OSCALVersion = "1.1.2"
+
+// oscalNamespace is the namespace of the name-based UUIDs in generated OSCAL documents, so that
+// regenerating a document for the same system keeps the UUIDs of its parts
+var oscalNamespace = uuid.MustParse("5d6e2f0a-6c1b-4b9e-8a57-3f1c2d9e7b40")
+
+// OSCALSystemSecurityPlan is an OSCAL system security plan (SSP) document
+type OSCALSystemSecurityPlan struct {
+	SystemSecurityPlan OSCALSSP `json:"system-security-plan"`
+}
+
+// OSCALSSP is the content of an OSCAL system security plan
+type OSCALSSP struct {
+	UUID                  string                     `json:"uuid"`
+	Metadata              OSCALMetadata              `json:"metadata"`
+	ImportProfile         OSCALImportProfile         `json:"import-profile"`
+	SystemCharacteristics OSCALSystemCharacteristics `json:"system-characteristics"`
+	SystemImplementation  OSCALSystemImplementation  `json:"system-implementation"`
+	ControlImplementation OSCALControlImplementation `json:"control-implementation"`
+}
+
+// OSCALMetadata is the metadata of an OSCAL document
+type OSCALMetadata struct {
+	Title        string      `json:"title"`
+	LastModified string      `json:"last-modified"`
+	Version      string      `json:"version"`
+	OSCALVersion string      `json:"oscal-version"`
+	Roles        []OSCALRole `json:"roles,omitempty"`
+}
+
+// OSCALRole is a role that parties, users and control responsibilities refer to
+type OSCALRole struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+// OSCALImportProfile references the profile, i.e. the baseline, that an SSP implements
+type OSCALImportProfile struct {
+	Href string `json:"href"`
+}
+
+// OSCALSystemCharacteristics describes the system an SSP covers
+type OSCALSystemCharacteristics struct {
+	SystemIDs                []OSCALSystemID           `json:"system-ids"`
+	SystemName               string                    `json:"system-name"`
+	Description              string                    `json:"description"`
+	SecuritySensitivityLevel string                    `json:"security-sensitivity-level,omitempty"`
+	SystemInformation        OSCALSystemInformation    `json:"system-information"`
+	SecurityImpactLevel      *OSCALSecurityImpactLevel `json:"security-impact-level,omitempty"`
+	Status                   OSCALStatus               `json:"status"`
+	AuthorizationBoundary    OSCALDescription          `json:"authorization-boundary"`
+}
+
+// OSCALSystemID identifies a system
+type OSCALSystemID struct {
+	IdentifierType string `json:"identifier-type,omitempty"`
+	ID             string `json:"id"`
+}
+
+// OSCALSystemInformation lists the types of information a system processes
+type OSCALSystemInformation struct {
+	InformationTypes []OSCALInformationType `json:"information-types"`
+}
+
+// OSCALInformationType is a type of information with its FIPS 199 impact levels
+type OSCALInformationType struct {
+	UUID                  string      `json:"uuid"`
+	Title                 string      `json:"title"`
+	Description           string      `json:"description"`
+	ConfidentialityImpact OSCALImpact `json:"confidentiality-impact"`
+	IntegrityImpact       OSCALImpact `json:"integrity-impact"`
+	AvailabilityImpact    OSCALImpact `json:"availability-impact"`
+}
+
+// OSCALImpact is the impact level of an information type for one security objective
+type OSCALImpact struct {
+	Base string `json:"base"`
+}
+
+// OSCALSecurityImpactLevel is the overall impact level of a system for each security objective
+type OSCALSecurityImpactLevel struct {
+	Confidentiality string `json:"security-objective-confidentiality"`
+	Integrity       string `json:"security-objective-integrity"`
+	Availability    string `json:"security-objective-availability"`
+}
+
+// OSCALStatus is the operational or implementation state of a system or component
+type OSCALStatus struct {
+	State string `json:"state"`
+}
+
+// implementationStatus returns the OSCAL implementation status of a record, or nil for a record
+// without a status
+func implementationStatus(record ImplementationRecord) *OSCALStatus {
+	if record.Status == "" {
+		return nil
+	}
+	return &OSCALStatus{State: string(record.Status)}
+}
+
+// OSCALDescription is an object that only carries a description
+type OSCALDescription struct {
+	Description string `json:"description"`
+}
+
+// OSCALSystemImplementation lists the users and components of a system
+type OSCALSystemImplementation struct {
+	Users      []OSCALUser      `json:"users"`
+	Components []OSCALComponent `json:"components"`
+}
+
+// OSCALUser is a type of user of a system
+type OSCALUser struct {
+	UUID    string   `json:"uuid"`
+	Title   string   `json:"title,omitempty"`
+	RoleIDs []string `json:"role-ids,omitempty"`
+}
+
+// OSCALComponent is a component of a system
+type OSCALComponent struct {
+	UUID        string          `json:"uuid"`
+	Type        string          `json:"type"`
+	Title       string          `json:"title"`
+	Description string          `json:"description"`
+	Props       []OSCALProperty `json:"props,omitempty"`
+	Status      OSCALStatus     `json:"status"`
+}
+
+// OSCALControlImplementation describes how a system implements the controls of its baseline
+type OSCALControlImplementation struct {
+	Description             string                        `json:"description"`
+	ImplementedRequirements []OSCALImplementedRequirement `json:"implemented-requirements"`
+}
+
+// OSCALImplementedRequirement describes how a system implements one control
+type OSCALImplementedRequirement struct {
+	UUID             string                      `json:"uuid"`
+	ControlID        string                      `json:"control-id"`
+	SetParameters    []OSCALSetParameter         `json:"set-parameters,omitempty"`
+	ResponsibleRoles []OSCALResponsibleRole      `json:"responsible-roles,omitempty"`
+	Props            []OSCALProperty             `json:"props,omitempty"`
+	ByComponents     []OSCALByComponent          `json:"by-components,omitempty"`
+	Statements       []OSCALImplementedStatement `json:"statements,omitempty"`
+}
+
+// ImplementationState returns the implementation status of the requirement, or an empty string if
+// no implementation is documented
+func (r OSCALImplementedRequirement) ImplementationState() string {
+	byComponents := r.ByComponents
+	if len(r.Statements) > 0 {
+		byComponents = r.Statements[0].ByComponents
+	}
+	for _, component := range byComponents {
+		if component.ImplementationStatus != nil {
+			return component.ImplementationStatus.State
+		}
+	}
+	return ""
+}
+
+// OSCALSetParameter sets the values of a control parameter
+type OSCALSetParameter struct {
+	ParamID string   `json:"param-id"`
+	Values  []string `json:"values"`
+}
+
+// OSCALResponsibleRole names a role responsible for a control
+type OSCALResponsibleRole struct {
+	RoleID string `json:"role-id"`
+}
+
+// OSCALImplementedStatement describes how a system implements one statement of a control
+type OSCALImplementedStatement struct {
+	StatementID  string             `json:"statement-id"`
+	UUID         string             `json:"uuid"`
+	Props        []OSCALProperty    `json:"props,omitempty"`
+	ByComponents []OSCALByComponent `json:"by-components"`
+}
+
+// OSCALByComponent describes how a component implements a control or statement
+type OSCALByComponent struct {
+	ComponentUUID        string       `json:"component-uuid"`
+	UUID                 string       `json:"uuid"`
+	Description          string       `json:"description"`
+	ImplementationStatus *OSCALStatus `json:"implementation-status,omitempty"`
+}
+
+// fedRAMPNamespace is the namespace of the FedRAMP extensions to OSCAL
+const fedRAMPNamespace = "https://fedramp.gov/ns/oscal"
+
+// controlOriginations are the FedRAMP control origination values of each responsibility role
+var controlOriginations = map[ResponsibilityRole][]string{
+	ResponsibilityProvider:  {"sp-system"},
+	ResponsibilityCustomer:  {"customer-provided"},
+	ResponsibilityInherited: {"inherited"},
+}
+
+// originationProps returns the FedRAMP control origination properties of a responsibility. A
+// shared responsibility originates with the provider and with the leveraged system or, without a
+// leveraged authorization, the customer.
+func originationProps(responsibility *Responsibility) []OSCALProperty {
+	if responsibility == nil {
+		return nil
+	}
+	origins := controlOriginations[responsibility.Role]
+	if responsibility.Role == ResponsibilityShared {
+		origins = []string{"sp-system", "customer-provided"}
+		if responsibility.LeveragedAuthorization != "" {
+			origins = []string{"sp-system", "inherited"}
+		}
+	}
+	var props []OSCALProperty
+	for _, origin := range origins {
+		props = append(props, OSCALProperty{Name: "control-origination", Ns: fedRAMPNamespace, Value: origin})
+	}
+	props[0].Remarks = responsibility.Description
+	return props
+}
+
+// originationRole returns the responsibility role of a set of FedRAMP control origination values,
+// or an empty string if there are none
+func originationRole(origins []string) ResponsibilityRole {
+	var roles []ResponsibilityRole
+	for _, origin := range origins {
+		role := ResponsibilityShared
+		switch {
+		case strings.HasPrefix(origin, "sp-"):
+			role = ResponsibilityProvider
+		case strings.HasPrefix(origin, "customer"):
+			role = ResponsibilityCustomer
+		case origin == "inherited":
+			role = ResponsibilityInherited
+		}
+		if !slices.Contains(roles, role) {
+			roles = append(roles, role)
+		}
+	}
+	switch len(roles) {
+	case 0:
+		return ""
+	case 1:
+		return roles[0]
+	default:
+		return ResponsibilityShared
+	}
+}
+
+// leveragedDescription describes how a control or statement relies on a leveraged authorization
+func leveragedDescription(responsibility Responsibility) string {
+	description := "Inherited from " + responsibility.LeveragedAuthorization + "."
+	if responsibility.Role == ResponsibilityShared {
+		description = "Shared with " + responsibility.LeveragedAuthorization + "."
+	}
+	if responsibility.Description != "" {
+		description += " " + responsibility.Description
+	}
+	return description
+}
+
+// SSPPlaceholderNarrative describes the implementation of a control or statement whose
+// implementation has not been documented
+const SSPPlaceholderNarrative = "No implementation has been documented."
+
+// SSPOptions describes the system an SSP is generated for
+type SSPOptions struct {
+	System      string // Name of the system, as used in its implementation records
+	Description string // Description of the system; a placeholder is used if empty
+	ProfileHref string // URL of the baseline profile; the FedRAMP profile for the program if empty
+	Status      string // Operational state of the system; "operational" if empty
+	// Components whose narratives describe how they implement controls. A component named by a
+	// record is described by its own narrative for the control, where it has one.
+	Components []Component
+}
+
+// fedRAMPProfiles are the URLs of the FedRAMP Rev 5 baseline profiles by impact level
+var fedRAMPProfiles = map[string]string{
+	"low":      "https://raw.githubusercontent.com/GSA/fedramp-automation/master/dist/content/rev5/baselines/json/FedRAMP_rev5_LOW-baseline_profile.json",
+	"moderate": "https://raw.githubusercontent.com/GSA/fedramp-automation/master/dist/content/rev5/baselines/json/FedRAMP_rev5_MODERATE-baseline_profile.json",
+	"high":     "https://raw.githubusercontent.com/GSA/fedramp-automation/master/dist/content/rev5/baselines/json/FedRAMP_rev5_HIGH-baseline_profile.json",
+}
+
+// ProgramImpactLevel returns the FIPS 199 impact level of a program from its name, e.g. "high" for
+// "FedRAMP High", or an empty string if the name does not end with an impact level
+func ProgramImpactLevel(programName string) string {
+	words := strings.Fields(strings.ToLower(programName))
+	if len(words) == 0 {
+		return ""
+	}
+	if _, ok := fedRAMPProfiles[words[len(words)-1]]; ok {
+		return words[len(words)-1]
+	}
+	return ""
+}
+
+// BuildSSP builds an OSCAL system security plan for a system from a program and the system's
+// implementation records. Every active control of the program gets an implemented requirement,
+// described statement by statement and component by component, with the parameter values and the
+// recorded status, narratives and responsible roles. Controls without a record are marked as not
+// yet documented. Leveraged authorizations are components of their own, and the controls and
+// statements inherited from or shared with them are described by those components, with FedRAMP
+// control origination properties recording who is responsible.
+func BuildSSP(program Program, records []ImplementationRecord, options SSPOptions, now time.Time) OSCALSystemSecurityPlan {
+	system := options.System
+	impact := cmp.Or(ProgramImpactLevel(program.Name), ProgramImpactLevel(program.Base))
+	nameUUID := func(parts ...string) string {
+		return uuid.NewSHA1(oscalNamespace, []byte(strings.Join(append([]string{system, program.Name}, parts...), "/"))).String()
+	}
+
+	recordsByControl := make(map[string]ImplementationRecord, len(records))
+	for _, record := range records {
+		recordsByControl[record.ControlID] = record
+	}
+
+	// Collect the responsible roles of every record, with a system owner if none are recorded
+	var roles []OSCALRole
+	addRole := func(title string) string {
+		id := roleID(title)
+		if !slices.ContainsFunc(roles, func(role OSCALRole) bool { return role.ID == id }) {
+			roles = append(roles, OSCALRole{ID: id, Title: title})
+		}
+		return id
+	}
+	for _, record := range records {
+		for _, role := range record.ResponsibleRoles {
+			addRole(role)
+		}
+	}
+	if len(roles) == 0 {
+		addRole("System Owner")
+	}
+	users := make([]OSCALUser, 0, len(roles))
+	for _, role := range roles {
+		users = append(users, OSCALUser{UUID: nameUUID("user", role.ID), Title: role.Title, RoleIDs: []string{role.ID}})
+	}
+
+	// Describe the system as a whole, and each component named by a record
+	thisSystem := OSCALComponent{
+		UUID:        nameUUID("component", "this-system"),
+		Type:        "this-system",
+		Title:       "This System",
+		Description: "The system as a whole, covering controls that are not implemented by a separate component.",
+		Status:      OSCALStatus{State: "operational"},
+	}
+	components := []OSCALComponent{thisSystem}
+	componentUUIDs := map[string]string{}
+	for _, record := range records {
+		for _, title := range record.Components {
+			if _, ok := componentUUIDs[title]; ok {
+				continue
+			}
+			componentUUIDs[title] = nameUUID("component", title)
+			described := OSCALComponent{
+				UUID:        componentUUIDs[title],
+				Type:        "software",
+				Title:       title,
+				Description: title + ", a component of " + system + ".",
+				Status:      OSCALStatus{State: "operational"},
+			}
+			if component, ok := FindComponent(options.Components, title); ok {
+				described.Type = component.Type
+				if component.Description != "" {
+					described.Description = component.Description
+				}
+			}
+			components = append(components, described)
+		}
+	}
+
+	// Describe each leveraged authorization that controls are inherited from or shared with
+	leveragedUUIDs := map[string]string{}
+	addLeveraged := func(responsibility Responsibility) {
+		name := responsibility.LeveragedAuthorization
+		if name == "" || responsibility.Role == ResponsibilityProvider || responsibility.Role == ResponsibilityCustomer {
+			return
+		}
+		if _, ok := leveragedUUIDs[name]; ok {
+			return
+		}
+		leveragedUUIDs[name] = nameUUID("leveraged-authorization", name)
+		components = append(components, OSCALComponent{
+			UUID:        leveragedUUIDs[name],
+			Type:        "system",
+			Title:       name,
+			Description: "The leveraged authorization " + name + ", which " + system + " inherits controls from.",
+			Props:       []OSCALProperty{{Name: "implementation-point", Value: "external"}},
+			Status:      OSCALStatus{State: "operational"},
+		})
+	}
+	for _, record := range records {
+		if record.Responsibility != nil {
+			addLeveraged(*record.Responsibility)
+		}
+		for _, statementID := range slices.Sorted(maps.Keys(record.StatementResponsibilities)) {
+			addLeveraged(record.StatementResponsibilities[statementID])
+		}
+	}
+
+	// Describe every active control, statement by statement
+	var requirements []OSCALImplementedRequirement
+	for _, family := range program.Families {
+		for _, control := range ActiveControls(family.Controls) {
+			record, recorded := recordsByControl[control.ID]
+			byComponents := func(statementID string) []OSCALByComponent {
+				if !recorded && control.NotApplicable() {
+					return []OSCALByComponent{{
+						ComponentUUID:        thisSystem.UUID,
+						UUID:                 nameUUID("by-component", control.ID, statementID),
+						Description:          "Not applicable: " + control.Tailoring.Justification,
+						ImplementationStatus: &OSCALStatus{State: string(StatusNotApplicable)},
+					}}
+				}
+				if !recorded {
+					return []OSCALByComponent{{
+						ComponentUUID: thisSystem.UUID,
+						UUID:          nameUUID("by-component", control.ID, statementID),
+						Description:   SSPPlaceholderNarrative,
+					}}
+				}
+				var byComponents []OSCALByComponent
+				description := record.StatementNarrative(statementID)
+				responsibility := record.StatementResponsibility(statementID)
+				leveraged, ok := "", false
+				if responsibility != nil {
+					leveraged, ok = leveragedUUIDs[responsibility.LeveragedAuthorization]
+				}
+				if ok {
+					byComponents = append(byComponents, OSCALByComponent{
+						ComponentUUID:        leveraged,
+						UUID:                 nameUUID("by-component", control.ID, statementID, responsibility.LeveragedAuthorization),
+						Description:          leveragedDescription(*responsibility),
+						ImplementationStatus: implementationStatus(record),
+					})
+					if description == "" && responsibility.Role == ResponsibilityInherited {
+						return byComponents // The leveraged system implements it on its own
+					}
+				}
+				if description == "" {
+					description = SSPPlaceholderNarrative
+				}
+				if len(record.Components) == 0 {
+					return append(byComponents, OSCALByComponent{
+						ComponentUUID:        thisSystem.UUID,
+						UUID:                 nameUUID("by-component", control.ID, statementID),
+						Description:          description,
+						ImplementationStatus: implementationStatus(record),
+					})
+				}
+				for _, title := range record.Components {
+					componentDescription := description
+					if component, ok := FindComponent(options.Components, title); ok {
+						mapping, ok := component.FindControl(program.Name, control.ID)
+						if narrative := mapping.StatementNarrative(statementID); ok && mapping.Satisfies(statementID) && narrative != "" {
+							componentDescription = narrative
+						}
+					}
+					byComponents = append(byComponents, OSCALByComponent{
+						ComponentUUID:        componentUUIDs[title],
+						UUID:                 nameUUID("by-component", control.ID, statementID, title),
+						Description:          componentDescription,
+						ImplementationStatus: implementationStatus(record),
+					})
+				}
+				return byComponents
+			}
+
+			requirement := OSCALImplementedRequirement{
+				UUID:      nameUUID("implemented-requirement", control.ID),
+				ControlID: control.ID,
+			}
+			for _, param := range control.Parameters {
+				if values := record.ParameterValues(param); len(values) > 0 {
+					requirement.SetParameters = append(requirement.SetParameters, OSCALSetParameter{ParamID: param.ID, Values: values})
+				}
+			}
+			if recorded {
+				for _, role := range record.ResponsibleRoles {
+					requirement.ResponsibleRoles = append(requirement.ResponsibleRoles, OSCALResponsibleRole{RoleID: roleID(role)})
+				}
+				requirement.Props = originationProps(record.Responsibility)
+			}
+			statements := control.ImplementationStatements()
+			for _, statement := range statements {
+				implemented := OSCALImplementedStatement{
+					StatementID:  statement.ID,
+					UUID:         nameUUID("statement", statement.ID),
+					ByComponents: byComponents(statement.ID),
+				}
+				if responsibility, ok := record.StatementResponsibilities[statement.ID]; ok {
+					implemented.Props = originationProps(&responsibility)
+				}
+				requirement.Statements = append(requirement.Statements, implemented)
+			}
+			if len(statements) == 0 {
+				requirement.ByComponents = byComponents("")
+			}
+			requirements = append(requirements, requirement)
+		}
+	}
+
+	description := strings.TrimSpace(options.Description)
+	if description == "" {
+		description = "System security plan for " + system + "."
+	}
+	profile := options.ProfileHref
+	if profile == "" {
+		profile = fedRAMPProfiles[impact]
+	}
+	state := options.Status
+	if state == "" {
+		state = "operational"
+	}
+
+	characteristics := OSCALSystemCharacteristics{
+		SystemIDs:   []OSCALSystemID{{IdentifierType: "http://ietf.org/rfc/rfc4122", ID: nameUUID("system")}},
+		SystemName:  system,
+		Description: description,
+		SystemInformation: OSCALSystemInformation{InformationTypes: []OSCALInformationType{{
+			UUID:        nameUUID("information-type"),
+			Title:       "System Information",
+			Description: "Information processed, stored and transmitted by " + system + ".",
+		}}},
+		Status:                OSCALStatus{State: state},
+		AuthorizationBoundary: OSCALDescription{Description: "The authorization boundary of " + system + "."},
+	}
+	if impact != "" {
+		level := "fips-199-" + impact
+		characteristics.SecuritySensitivityLevel = level
+		information := &characteristics.SystemInformation.InformationTypes[0]
+		information.ConfidentialityImpact = OSCALImpact{Base: level}
+		information.IntegrityImpact = OSCALImpact{Base: level}
+		information.AvailabilityImpact = OSCALImpact{Base: level}
+		characteristics.SecurityImpactLevel = &OSCALSecurityImpactLevel{Confidentiality: level, Integrity: level, Availability: level}
+	}
+
+	return OSCALSystemSecurityPlan{SystemSecurityPlan: OSCALSSP{
+		UUID: nameUUID("ssp"),
+		Metadata: OSCALMetadata{
+			Title:        system + " System Security Plan",
+			LastModified: now.UTC().Format(time.RFC3339),
+			Version:      now.UTC().Format("2006-01-02"),
+			OSCALVersion: OSCALVersion,
+			Roles:        roles,
+		},
+		ImportProfile:         OSCALImportProfile{Href: profile},
+		SystemCharacteristics: characteristics,
+		SystemImplementation: OSCALSystemImplementation{
+			Users:      users,
+			Components: components,
+		},
+		ControlImplementation: OSCALControlImplementation{
+			Description:             "How " + system + " implements the controls of the " + program.Name + " baseline.",
+			ImplementedRequirements: requirements,
+		},
+	}}
+}
+
+// roleIDPattern matches the characters that are not allowed in an OSCAL role ID
+var roleIDPattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+// roleID returns the OSCAL role ID for a role title, e.g. "system-owner" for "System Owner"
+func roleID(title string) string {
+	id := strings.Trim(roleIDPattern.ReplaceAllString(strings.ToLower(title), "-"), "-")
+	if id == "" || (id[0] >= '0' && id[0] <= '9') {
+		id = "role-" + id
+	}
+	return id
+}
@@ -0,0 +1,184 @@
+package fedramp
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// sspTestRecords returns records for the program of sspTestProgram that name a component, a
+// leveraged authorization and responsible roles
+func sspTestRecords(program Program) []ImplementationRecord {
+	key := func(controlID string) ImplementationKey {
+		return ImplementationKey{System: "Acme Cloud", Program: program.Name, ControlID: controlID}
+	}
+	return []ImplementationRecord{
+		{
+			ImplementationKey: key("ac-2"),
+			Status:            StatusImplemented,
+			ResponsibleRoles:  []string{"System Owner", "Account Manager"},
+			Narrative:         "Accounts are managed in the directory.",
+			Parameters:        map[string][]string{"ac-02_odp.02": {"30 days"}},
+			Components:        []string{"Directory"},
+		},
+		{
+			ImplementationKey: key("ac-2.1"),
+			Status:            StatusPlanned,
+			Responsibility:    &Responsibility{Role: ResponsibilityInherited, LeveragedAuthorization: "IaaS P-ATO"},
+		},
+	}
+}
+
+func TestBuildSSP(t *testing.T) {
+	program := sspTestProgram()
+	program.Families[0].Controls = append(program.Families[0].Controls, Control{ID: "ac-4", Status: "withdrawn"})
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	document := BuildSSP(program, sspTestRecords(program), SSPOptions{System: "Acme Cloud"}, now)
+	ssp := document.SystemSecurityPlan
+
+	// Required fields
+	if ssp.Metadata.Title == "" || ssp.Metadata.LastModified != "2025-03-01T12:00:00Z" || ssp.Metadata.Version == "" || ssp.Metadata.OSCALVersion != OSCALVersion {
+		t.Errorf("metadata = %+v, want a title, the time, a version and the OSCAL version", ssp.Metadata)
+	}
+	if ssp.ImportProfile.Href != fedRAMPProfiles["moderate"] {
+		t.Errorf("import-profile = %q, want the FedRAMP Moderate profile", ssp.ImportProfile.Href)
+	}
+	characteristics := ssp.SystemCharacteristics
+	if len(characteristics.SystemIDs) != 1 || characteristics.SystemName != "Acme Cloud" || characteristics.Description == "" ||
+		characteristics.Status.State != "operational" || characteristics.AuthorizationBoundary.Description == "" ||
+		len(characteristics.SystemInformation.InformationTypes) != 1 {
+		t.Errorf("system-characteristics = %+v, want every required field", characteristics)
+	}
+	if characteristics.SecuritySensitivityLevel != "fips-199-moderate" || characteristics.SecurityImpactLevel == nil ||
+		characteristics.SystemInformation.InformationTypes[0].ConfidentialityImpact.Base != "fips-199-moderate" {
+		t.Errorf("system-characteristics = %+v, want the moderate impact level", characteristics)
+	}
+	if len(ssp.SystemImplementation.Users) == 0 || len(ssp.SystemImplementation.Components) == 0 || ssp.ControlImplementation.Description == "" {
+		t.Errorf("system-implementation = %+v, want users and components", ssp.SystemImplementation)
+	}
+
+	// Every active control gets a requirement, and withdrawn controls none
+	var controlIDs []string
+	for _, requirement := range ssp.ControlImplementation.ImplementedRequirements {
+		controlIDs = append(controlIDs, requirement.ControlID)
+	}
+	if want := []string{"ac-2", "ac-2.1", "ac-3"}; !slices.Equal(controlIDs, want) {
+		t.Errorf("implemented requirements = %v, want %v", controlIDs, want)
+	}
+
+	checkSSPReferences(t, program, ssp)
+
+	// UUIDs are derived from the system and program, so that a regenerated plan keeps them
+	again := BuildSSP(program, sspTestRecords(program), SSPOptions{System: "Acme Cloud"}, now.Add(time.Hour))
+	if again.SystemSecurityPlan.UUID != ssp.UUID ||
+		again.SystemSecurityPlan.ControlImplementation.ImplementedRequirements[0].UUID != ssp.ControlImplementation.ImplementedRequirements[0].UUID {
+		t.Error("regenerating the plan changed its UUIDs")
+	}
+	other := BuildSSP(program, nil, SSPOptions{System: "Other Cloud"}, now)
+	if other.SystemSecurityPlan.UUID == ssp.UUID {
+		t.Error("plans of different systems share a UUID")
+	}
+
+	// Required fields are written even when empty
+	data, err := json.Marshal(document)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var written struct {
+		SystemSecurityPlan map[string]json.RawMessage `json:"system-security-plan"`
+	}
+	if err := json.Unmarshal(data, &written); err != nil {
+		t.Fatal(err)
+	}
+	for _, field := range []string{"uuid", "metadata", "import-profile", "system-characteristics", "system-implementation", "control-implementation"} {
+		if _, ok := written.SystemSecurityPlan[field]; !ok {
+			t.Errorf("the plan has no %q", field)
+		}
+	}
+}
+
+// checkSSPReferences checks that every UUID in a plan is valid and unique, and that every
+// reference to a role, component, control, statement or parameter resolves
+func checkSSPReferences(t *testing.T, program Program, ssp OSCALSSP) {
+	t.Helper()
+	seen := map[string]bool{}
+	checkUUID := func(what, id string) {
+		if _, err := uuid.Parse(id); err != nil {
+			t.Errorf("%s uuid %q: %v", what, id, err)
+		}
+		if seen[id] {
+			t.Errorf("%s uuid %q is not unique", what, id)
+		}
+		seen[id] = true
+	}
+	checkUUID("ssp", ssp.UUID)
+	checkUUID("system-id", ssp.SystemCharacteristics.SystemIDs[0].ID)
+	for _, information := range ssp.SystemCharacteristics.SystemInformation.InformationTypes {
+		checkUUID("information-type", information.UUID)
+	}
+
+	roles := map[string]bool{}
+	for _, role := range ssp.Metadata.Roles {
+		roles[role.ID] = true
+	}
+	for _, user := range ssp.SystemImplementation.Users {
+		checkUUID("user", user.UUID)
+		for _, id := range user.RoleIDs {
+			if !roles[id] {
+				t.Errorf("user %q has the unknown role %q", user.Title, id)
+			}
+		}
+	}
+	components := map[string]bool{}
+	for _, component := range ssp.SystemImplementation.Components {
+		checkUUID("component", component.UUID)
+		components[component.UUID] = true
+	}
+	checkByComponents := func(controlID string, byComponents []OSCALByComponent) {
+		if len(byComponents) == 0 {
+			t.Errorf("%s has a statement no component describes", controlID)
+		}
+		for _, byComponent := range byComponents {
+			checkUUID("by-component", byComponent.UUID)
+			if !components[byComponent.ComponentUUID] {
+				t.Errorf("%s refers to the unknown component %q", controlID, byComponent.ComponentUUID)
+			}
+			if byComponent.Description == "" {
+				t.Errorf("%s has a by-component without a description", controlID)
+			}
+		}
+	}
+
+	for _, requirement := range ssp.ControlImplementation.ImplementedRequirements {
+		checkUUID("implemented-requirement", requirement.UUID)
+		control, ok := program.FindControl(requirement.ControlID)
+		if !ok {
+			t.Errorf("the plan implements the unknown control %q", requirement.ControlID)
+			continue
+		}
+		for _, role := range requirement.ResponsibleRoles {
+			if !roles[role.RoleID] {
+				t.Errorf("%s names the unknown role %q", control.ID, role.RoleID)
+			}
+		}
+		for _, set := range requirement.SetParameters {
+			if _, ok := control.FindParameter(set.ParamID); !ok {
+				t.Errorf("%s sets the unknown parameter %q", control.ID, set.ParamID)
+			}
+		}
+		for _, statement := range requirement.Statements {
+			checkUUID("statement", statement.UUID)
+			if !slices.ContainsFunc(control.ImplementationStatements(), func(s ControlStatement) bool { return s.ID == statement.StatementID }) {
+				t.Errorf("%s implements the unknown statement %q", control.ID, statement.StatementID)
+			}
+			checkByComponents(control.ID, statement.ByComponents)
+		}
+		if len(requirement.Statements) == 0 {
+			checkByComponents(control.ID, requirement.ByComponents)
+		}
+	}
+}
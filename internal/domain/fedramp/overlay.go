@@ -0,0 +1,234 @@
+package fedramp
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// Overlay tailors a base program for an organization: it adds controls from other programs,
+// marks controls not applicable and tightens parameter values. Applying it to the base program
+// produces a derived program with the overlay's name.
+type Overlay struct {
+	Name          string                 `json:"name"`
+	Base          string                 `json:"base"` // Program the overlay tailors, e.g. "FedRAMP Moderate"
+	Description   string                 `json:"description,omitempty"`
+	Add           []OverlayAddition      `json:"add,omitempty"`
+	NotApplicable []OverlayNotApplicable `json:"notApplicable,omitempty"`
+	Parameters    []OverlayParameter     `json:"parameters,omitempty"`
+}
+
+// OverlayAddition adds a control of another program to the base program
+type OverlayAddition struct {
+	ControlID string `json:"controlId"`
+	From      string `json:"from"` // Program the control is taken from, e.g. "FedRAMP High"
+}
+
+// OverlayNotApplicable marks a control of the program not applicable
+type OverlayNotApplicable struct {
+	ControlID     string `json:"controlId"`
+	Justification string `json:"justification"`
+}
+
+// OverlayParameter sets the values of a parameter in place of the base program's
+type OverlayParameter struct {
+	ParameterID string   `json:"parameterId"`
+	Values      []string `json:"values"`
+}
+
+// ControlTailoring records how an overlay tailored a control of a derived program
+type ControlTailoring struct {
+	Overlay       string               `json:"overlay"`
+	AddedFrom     string               `json:"addedFrom,omitempty"` // Program the control was added from
+	NotApplicable bool                 `json:"notApplicable,omitempty"`
+	Justification string               `json:"justification,omitempty"` // Why the control is not applicable
+	Parameters    []ParameterTailoring `json:"parameters,omitempty"`
+}
+
+// ParameterTailoring records the values a parameter had before an overlay set its own
+type ParameterTailoring struct {
+	ParameterID string   `json:"parameterId"`
+	BaseValues  []string `json:"baseValues,omitempty"` // Values set by the base program, if any
+}
+
+// NotApplicable reports whether an overlay marked the control not applicable
+func (c Control) NotApplicable() bool {
+	return c.Tailoring != nil && c.Tailoring.NotApplicable
+}
+
+// TailoringNotice explains how an overlay tailored the control, or returns an empty string for a
+// control that was not tailored
+func (c Control) TailoringNotice() string {
+	if c.Tailoring == nil {
+		return ""
+	}
+	var sentences []string
+	if c.Tailoring.AddedFrom != "" {
+		sentences = append(sentences, c.Tailoring.Overlay+" adds "+ControlLabel(c.ID)+" from "+c.Tailoring.AddedFrom+".")
+	}
+	if c.Tailoring.NotApplicable {
+		sentences = append(sentences, ControlLabel(c.ID)+" is not applicable in "+c.Tailoring.Overlay+": "+strings.TrimSuffix(c.Tailoring.Justification, ".")+".")
+	}
+	for _, param := range c.Tailoring.Parameters {
+		sentence := c.Tailoring.Overlay + " sets " + param.ParameterID
+		if len(param.BaseValues) > 0 {
+			sentence += " in place of " + strings.Join(param.BaseValues, "; ")
+		}
+		sentences = append(sentences, sentence+".")
+	}
+	return strings.Join(sentences, " ")
+}
+
+// OverlayPrograms returns the programs an overlay needs to be applied: its base and the programs
+// it adds controls from
+func (o Overlay) OverlayPrograms() []string {
+	programs := []string{o.Base}
+	for _, addition := range o.Add {
+		if !slices.ContainsFunc(programs, func(program string) bool { return strings.EqualFold(program, addition.From) }) {
+			programs = append(programs, addition.From)
+		}
+	}
+	return programs
+}
+
+// ApplyOverlay applies an overlay to its base program and returns the derived program, named
+// after the overlay. Sources are the programs controls are added from, by name. Each tailored
+// control records how it was tailored. Tightened parameter values must be valid for the
+// parameter and must not loosen a period that the base program's values or constraints set.
+func ApplyOverlay(base Program, overlay Overlay, sources []Program) (Program, error) {
+	if strings.TrimSpace(overlay.Name) == "" {
+		return Program{}, NewError(ErrInvalidArgument, "an overlay needs a name")
+	}
+	if base.Base != "" {
+		return Program{}, NewError(ErrInvalidArgument, "%s is itself tailored from %s; an overlay must tailor a published program", base.Name, base.Base)
+	}
+
+	program := Program{Name: overlay.Name, Base: base.Name, Description: overlay.Description, Families: slices.Clone(base.Families)}
+	for i := range program.Families {
+		program.Families[i].Controls = slices.Clone(program.Families[i].Controls)
+	}
+	tailor := func(control *Control) *ControlTailoring {
+		if control.Tailoring == nil {
+			control.Tailoring = &ControlTailoring{Overlay: overlay.Name}
+		}
+		return control.Tailoring
+	}
+	find := func(controlID string) (*Control, bool) {
+		normalized := NormalizeControlID(controlID)
+		for i := range program.Families {
+			for j := range program.Families[i].Controls {
+				if NormalizeControlID(program.Families[i].Controls[j].ID) == normalized {
+					return &program.Families[i].Controls[j], true
+				}
+			}
+		}
+		return nil, false
+	}
+	notFound := func(controlID string, in Program) error {
+		var controlIDs []string
+		for _, family := range in.Families {
+			for _, control := range family.Controls {
+				controlIDs = append(controlIDs, control.ID)
+			}
+		}
+		return NewError(ErrNotFound, "control %s not found in %s", ControlLabel(controlID), in.Name).
+			WithSuggestions(ClosestControls(controlID, controlIDs)...)
+	}
+
+	// Add controls from other programs, in their families and in catalog order
+	for _, addition := range overlay.Add {
+		index := slices.IndexFunc(sources, func(source Program) bool { return strings.EqualFold(source.Name, addition.From) })
+		if index < 0 {
+			return Program{}, NewError(ErrProgramUnavailable, "program not found: %s", addition.From)
+		}
+		source := sources[index]
+		control, sourceFamily, ok := source.findControlInFamily(addition.ControlID)
+		if !ok {
+			return Program{}, notFound(addition.ControlID, source)
+		}
+		if _, ok := find(control.ID); ok {
+			return Program{}, NewError(ErrInvalidArgument, "%s is already a control of %s", ControlLabel(control.ID), base.Name)
+		}
+		control.Tailoring = &ControlTailoring{Overlay: overlay.Name, AddedFrom: source.Name}
+
+		index = slices.IndexFunc(program.Families, func(family ControlFamily) bool { return family.ID == sourceFamily.ID })
+		if index < 0 {
+			program.Families = append(program.Families, ControlFamily{ID: sourceFamily.ID, Title: sourceFamily.Title})
+			slices.SortFunc(program.Families, func(a, b ControlFamily) int { return cmp.Compare(a.ID, b.ID) })
+			index = slices.IndexFunc(program.Families, func(family ControlFamily) bool { return family.ID == sourceFamily.ID })
+		}
+		family := &program.Families[index]
+		family.Controls = append(family.Controls, control)
+		slices.SortFunc(family.Controls, func(a, b Control) int { return CompareControlIDs(a.ID, b.ID) })
+	}
+
+	// Mark controls not applicable
+	for _, exclusion := range overlay.NotApplicable {
+		control, ok := find(exclusion.ControlID)
+		if !ok {
+			return Program{}, notFound(exclusion.ControlID, program)
+		}
+		justification := strings.TrimSpace(exclusion.Justification)
+		if justification == "" {
+			return Program{}, NewError(ErrInvalidArgument, "a justification is required to mark %s not applicable", ControlLabel(control.ID))
+		}
+		tailoring := tailor(control)
+		tailoring.NotApplicable = true
+		tailoring.Justification = justification
+	}
+
+	// Tighten parameter values
+	for _, assignment := range overlay.Parameters {
+		owner, param, ok := program.FindParameter(assignment.ParameterID)
+		if !ok {
+			return Program{}, NewError(ErrNotFound, "parameter %q not found in %s", assignment.ParameterID, program.Name).
+				WithSuggestions(ClosestMatches(assignment.ParameterID, program.ParameterIDs())...)
+		}
+		values := normalizeValues(assignment.Values)
+		if len(values) == 0 {
+			return Program{}, NewError(ErrInvalidArgument, "parameter %s needs at least one value", param.ID)
+		}
+		bounded := param
+		bounded.Constraints = append(slices.Clone(param.Constraints), param.Values...)
+		values, err := ValidateParameterValues(bounded, values)
+		if err != nil {
+			return Program{}, err
+		}
+
+		control, _ := find(owner.ID)
+		control.Parameters = slices.Clone(control.Parameters)
+		for i := range control.Parameters {
+			if control.Parameters[i].ID == param.ID {
+				control.Parameters[i].Values = values
+			}
+		}
+		tailoring := tailor(control)
+		tailoring.Parameters = append(tailoring.Parameters, ParameterTailoring{ParameterID: param.ID, BaseValues: param.Values})
+	}
+
+	return program, nil
+}
+
+// findControlInFamily returns a control of the program with the family it belongs to
+func (p Program) findControlInFamily(controlID string) (Control, ControlFamily, bool) {
+	normalized := NormalizeControlID(controlID)
+	for _, family := range p.Families {
+		for _, control := range family.Controls {
+			if NormalizeControlID(control.ID) == normalized {
+				return control, family, true
+			}
+		}
+	}
+	return Control{}, ControlFamily{}, false
+}
+
+// normalizeValues trims values and drops blanks
+func normalizeValues(values []string) []string {
+	normalized := make([]string, 0, len(values))
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			normalized = append(normalized, value)
+		}
+	}
+	return normalized
+}
@@ -0,0 +1,129 @@
+package fedramp
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+// overlayTestPrograms returns a moderate baseline and a high baseline with more controls
+func overlayTestPrograms() (Program, Program) {
+	moderate := Program{Name: "FedRAMP Moderate", Families: []ControlFamily{
+		{ID: "ac", Title: "Access Control", Controls: []Control{
+			{ID: "ac-2", Parameters: []ControlParameter{{ID: "ac-02_odp.10", Values: []string{"at least every 90 days"}}}},
+			{ID: "ac-3"},
+		}},
+		{ID: "au", Title: "Audit and Accountability", Controls: []Control{{ID: "au-2"}}},
+	}}
+	high := Program{Name: "FedRAMP High", Families: []ControlFamily{
+		{ID: "ac", Title: "Access Control", Controls: []Control{{ID: "ac-2"}, {ID: "ac-2.1"}, {ID: "ac-3"}, {ID: "ac-6"}}},
+		{ID: "sc", Title: "System and Communications Protection", Controls: []Control{{ID: "sc-7"}}},
+	}}
+	return moderate, high
+}
+
+func TestApplyOverlay(t *testing.T) {
+	moderate, high := overlayTestPrograms()
+	overlay := Overlay{
+		Name: "Acme Moderate",
+		Base: moderate.Name,
+		Add: []OverlayAddition{
+			{ControlID: "SC-7", From: "fedramp high"},
+			{ControlID: "AC-2(1)", From: "FedRAMP High"},
+		},
+		NotApplicable: []OverlayNotApplicable{{ControlID: "AU-2", Justification: " Logging is inherited. "}},
+		Parameters:    []OverlayParameter{{ParameterID: "ac-02_odp.10", Values: []string{"30 days"}}},
+	}
+
+	program, err := ApplyOverlay(moderate, overlay, []Program{moderate, high})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if program.Name != "Acme Moderate" || program.Base != "FedRAMP Moderate" {
+		t.Errorf("program = %q based on %q, want Acme Moderate based on FedRAMP Moderate", program.Name, program.Base)
+	}
+
+	// Added controls join their families, or families of their own, in catalog order
+	var ids []string
+	for _, family := range program.Families {
+		for _, control := range family.Controls {
+			ids = append(ids, control.ID)
+		}
+	}
+	if want := []string{"ac-2", "ac-2.1", "ac-3", "au-2", "sc-7"}; !slices.Equal(ids, want) {
+		t.Errorf("controls = %v, want %v", ids, want)
+	}
+	if family := program.Families[2]; family.ID != "sc" || family.Title != "System and Communications Protection" {
+		t.Errorf("families[2] = %s %q, want the SC family of FedRAMP High", family.ID, family.Title)
+	}
+	if control, _ := program.FindControl("sc-7"); control.Tailoring == nil || control.Tailoring.AddedFrom != "FedRAMP High" {
+		t.Errorf("sc-7 tailoring = %+v, want added from FedRAMP High", control.Tailoring)
+	}
+
+	// Controls not applicable keep their justification
+	if control, _ := program.FindControl("au-2"); !control.NotApplicable() || control.Tailoring.Justification != "Logging is inherited." {
+		t.Errorf("au-2 tailoring = %+v, want not applicable with its justification", control.Tailoring)
+	}
+
+	// Tightened parameters record the base program's values
+	control, _ := program.FindControl("ac-2")
+	if !slices.Equal(control.Parameters[0].Values, []string{"30 days"}) {
+		t.Errorf("ac-02_odp.10 values = %q, want the overlay's", control.Parameters[0].Values)
+	}
+	if control.Tailoring == nil || len(control.Tailoring.Parameters) != 1 || !slices.Equal(control.Tailoring.Parameters[0].BaseValues, []string{"at least every 90 days"}) {
+		t.Errorf("ac-2 tailoring = %+v, want the base values of ac-02_odp.10", control.Tailoring)
+	}
+
+	// The base program is left as it was
+	if control, _ := moderate.FindControl("ac-2"); control.Tailoring != nil || !slices.Equal(control.Parameters[0].Values, []string{"at least every 90 days"}) {
+		t.Errorf("base ac-2 = %+v, want it untailored", control)
+	}
+	if control, _ := moderate.FindControl("au-2"); control.Tailoring != nil || len(moderate.Families) != 2 || len(moderate.Families[0].Controls) != 2 {
+		t.Errorf("base program was changed: %+v", moderate)
+	}
+}
+
+func TestApplyOverlayInvalid(t *testing.T) {
+	moderate, high := overlayTestPrograms()
+	tests := []struct {
+		name    string
+		overlay Overlay
+		want    ErrorKind
+	}{
+		{"no name", Overlay{Name: " "}, ErrInvalidArgument},
+		{"unknown program to add from", Overlay{Add: []OverlayAddition{{ControlID: "ac-6", From: "FedRAMP Ultra"}}}, ErrProgramUnavailable},
+		{"unknown control to add", Overlay{Add: []OverlayAddition{{ControlID: "AC-99", From: "FedRAMP High"}}}, ErrNotFound},
+		{"control already in the base", Overlay{Add: []OverlayAddition{{ControlID: "AC-3", From: "FedRAMP High"}}}, ErrInvalidArgument},
+		{"unknown control not applicable", Overlay{NotApplicable: []OverlayNotApplicable{{ControlID: "AC-6", Justification: "Not used."}}}, ErrNotFound},
+		{"not applicable without a justification", Overlay{NotApplicable: []OverlayNotApplicable{{ControlID: "AU-2", Justification: "  "}}}, ErrInvalidArgument},
+		{"unknown parameter", Overlay{Parameters: []OverlayParameter{{ParameterID: "ac-02_odp.99", Values: []string{"30 days"}}}}, ErrNotFound},
+		{"parameter without values", Overlay{Parameters: []OverlayParameter{{ParameterID: "ac-02_odp.10", Values: []string{" "}}}}, ErrInvalidArgument},
+		{"loosened parameter", Overlay{Parameters: []OverlayParameter{{ParameterID: "ac-02_odp.10", Values: []string{"6 months"}}}}, ErrInvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.overlay.Name == "" {
+				tt.overlay.Name = "Acme Moderate"
+			}
+			if _, err := ApplyOverlay(moderate, tt.overlay, []Program{moderate, high}); KindOf(err) != tt.want {
+				t.Errorf("ApplyOverlay() error = %v, want %s", err, tt.want)
+			}
+		})
+	}
+
+	// Unknown controls are suggested by their labels
+	_, err := ApplyOverlay(moderate, Overlay{Name: "Acme Moderate", Add: []OverlayAddition{{ControlID: "AC-2(2)", From: "FedRAMP High"}}}, []Program{high})
+	var overlayErr *Error
+	if !errors.As(err, &overlayErr) || !slices.Contains(overlayErr.Suggestions, "AC-2(1)") {
+		t.Errorf("ApplyOverlay() error = %v, want a suggestion of AC-2(1)", err)
+	}
+
+	// Overlays tailor published programs, not other tailored programs
+	tailored, err := ApplyOverlay(moderate, Overlay{Name: "Acme Moderate"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ApplyOverlay(tailored, Overlay{Name: "Acme Moderate 2"}, nil); KindOf(err) != ErrInvalidArgument {
+		t.Errorf("ApplyOverlay() on a tailored program error = %v, want %s", err, ErrInvalidArgument)
+	}
+}
@@ -0,0 +1,114 @@
+package fedramp
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+)
+
+// DefaultPageSize is the number of items returned when a page size is not specified
+const DefaultPageSize = 25
+
+// MaxPageSize is the largest page size a caller may request
+const MaxPageSize = 200
+
+// cursorPrefix marks an encoded cursor so that arbitrary strings are rejected
+const cursorPrefix = "offset:"
+
+// PageRequest describes which page of a list operation to return
+type PageRequest struct {
+	Cursor   string
+	PageSize int
+}
+
+// Page represents a single page of results from a list operation
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	Total      int    `json:"total"`
+	NextCursor string `json:"nextCursor,omitempty"`
+	Offset     int    `json:"-"` // Position of the first item in the full result set
+}
+
+// EncodeCursor encodes an offset into an opaque continuation cursor
+func EncodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + strconv.Itoa(offset)))
+}
+
+// DecodeCursor decodes a continuation cursor into an offset. An empty cursor is the first page.
+func DecodeCursor(cursor string) (int, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(data), cursorPrefix) {
+		return 0, NewError(ErrInvalidArgument, "invalid cursor: %s", cursor)
+	}
+
+	offset, err := strconv.Atoi(strings.TrimPrefix(string(data), cursorPrefix))
+	if err != nil || offset < 0 {
+		return 0, NewError(ErrInvalidArgument, "invalid cursor: %s", cursor)
+	}
+
+	return offset, nil
+}
+
+// Paginate returns the page of items described by the page request
+func Paginate[T any](items []T, req PageRequest) (Page[T], error) {
+	offset, err := DecodeCursor(req.Cursor)
+	if err != nil {
+		return Page[T]{}, err
+	}
+	if offset > len(items) {
+		offset = len(items)
+	}
+
+	pageSize := req.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	if pageSize > MaxPageSize {
+		pageSize = MaxPageSize
+	}
+
+	end := min(offset+pageSize, len(items))
+	page := Page[T]{
+		Items:  items[offset:end],
+		Total:  len(items),
+		Offset: offset,
+	}
+	if page.Items == nil {
+		page.Items = []T{}
+	}
+	if end < len(items) {
+		page.NextCursor = EncodeCursor(end)
+	}
+
+	return page, nil
+}
+
+// Truncate shortens the page to its first n items and moves the continuation cursor to match
+func (p Page[T]) Truncate(n int) Page[T] {
+	if n < 0 || n >= len(p.Items) {
+		return p
+	}
+
+	p.Items = p.Items[:n]
+	p.NextCursor = EncodeCursor(p.Offset + n)
+	return p
+}
+
+// MapPage converts the items of a page, preserving its position and continuation cursor
+func MapPage[T, U any](page Page[T], convert func([]T) ([]U, error)) (Page[U], error) {
+	items, err := convert(page.Items)
+	if err != nil {
+		return Page[U]{}, err
+	}
+
+	return Page[U]{
+		Items:      items,
+		Total:      page.Total,
+		NextCursor: page.NextCursor,
+		Offset:     page.Offset,
+	}, nil
+}
@@ -0,0 +1,88 @@
+package fedramp
+
+import (
+	"encoding/base64"
+	"slices"
+	"testing"
+)
+
+func TestPaginate(t *testing.T) {
+	items := make([]int, 60)
+	for i := range items {
+		items[i] = i
+	}
+
+	tests := []struct {
+		name       string
+		req        PageRequest
+		wantFirst  int
+		wantLen    int
+		wantOffset int // of the next cursor, or -1 if there is none
+	}{
+		{"first page", PageRequest{}, 0, DefaultPageSize, DefaultPageSize},
+		{"page size", PageRequest{PageSize: 10}, 0, 10, 10},
+		{"cursor", PageRequest{Cursor: EncodeCursor(50), PageSize: 5}, 50, 5, 55},
+		{"last page", PageRequest{Cursor: EncodeCursor(50), PageSize: 20}, 50, 10, -1},
+		{"page size above the maximum", PageRequest{PageSize: MaxPageSize + 1}, 0, 60, -1},
+		{"cursor past the end", PageRequest{Cursor: EncodeCursor(100)}, 0, 0, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := Paginate(items, tt.req)
+			if err != nil {
+				t.Fatalf("Paginate() returned error: %v", err)
+			}
+			if len(page.Items) != tt.wantLen || len(page.Items) > 0 && page.Items[0] != tt.wantFirst {
+				t.Errorf("Paginate() items = %v, want %d items from %d", page.Items, tt.wantLen, tt.wantFirst)
+			}
+			if page.Total != len(items) || page.Items == nil {
+				t.Errorf("Paginate() total = %d, items = %#v, want a total of %d and a non-nil page", page.Total, page.Items, len(items))
+			}
+			if tt.wantOffset < 0 {
+				if page.NextCursor != "" {
+					t.Errorf("Paginate() next cursor = %q, want none", page.NextCursor)
+				}
+				return
+			}
+			if offset, err := DecodeCursor(page.NextCursor); err != nil || offset != tt.wantOffset {
+				t.Errorf("Paginate() next cursor offset = %d, %v, want %d", offset, err, tt.wantOffset)
+			}
+		})
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	encode := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+	tests := map[string]string{
+		"not base64":          "not base64!",
+		"padded base64":       base64.URLEncoding.EncodeToString([]byte("offset:1")),
+		"no prefix":           encode("25"),
+		"offset not integer":  encode("offset:ten"),
+		"negative offset":     encode("offset:-1"),
+		"offset out of range": encode("offset:99999999999999999999"),
+	}
+	for name, cursor := range tests {
+		t.Run(name, func(t *testing.T) {
+			if offset, err := DecodeCursor(cursor); KindOf(err) != ErrInvalidArgument {
+				t.Errorf("DecodeCursor(%q) = %d, %v, want an invalid argument", cursor, offset, err)
+			}
+			if _, err := Paginate([]int{1, 2, 3}, PageRequest{Cursor: cursor}); KindOf(err) != ErrInvalidArgument {
+				t.Errorf("Paginate(%q) error = %v, want an invalid argument", cursor, err)
+			}
+		})
+	}
+}
+
+func TestPageTruncate(t *testing.T) {
+	page, err := Paginate([]string{"a", "b", "c", "d", "e"}, PageRequest{Cursor: EncodeCursor(1), PageSize: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	truncated := page.Truncate(1)
+	if offset, _ := DecodeCursor(truncated.NextCursor); !slices.Equal(truncated.Items, []string{"b"}) || offset != 2 {
+		t.Errorf("Truncate(1) = %v with a cursor at %d, want [b] with a cursor at 2", truncated.Items, offset)
+	}
+	if got := page.Truncate(3); got.NextCursor != page.NextCursor {
+		t.Errorf("Truncate(3) next cursor = %q, want the page's own %q", got.NextCursor, page.NextCursor)
+	}
+}
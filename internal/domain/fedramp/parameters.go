@@ -0,0 +1,253 @@
+package fedramp
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// FindParameter returns a parameter of the program, with the control it belongs to, given its ID
+// in any case, e.g. "ac-02_odp.01", or its ID in earlier catalogs, e.g. "ac-2_prm_1"
+func (p Program) FindParameter(paramID string) (Control, ControlParameter, bool) {
+	for _, family := range p.Families {
+		for _, control := range family.Controls {
+			if param, ok := control.FindParameter(paramID); ok {
+				return control, param, true
+			}
+		}
+	}
+	return Control{}, ControlParameter{}, false
+}
+
+// FindParameter returns a parameter of the control given its ID in any case or its ID in earlier
+// catalogs. Catalogs that do not record the earlier IDs have the nth parameter of a control,
+// "ac-2_prm_4", numbered as the nth organization-defined parameter, "ac-02_odp.04".
+func (c Control) FindParameter(paramID string) (ControlParameter, bool) {
+	paramID = strings.TrimSpace(paramID)
+	legacy := false
+	for _, param := range c.Parameters {
+		if strings.EqualFold(param.ID, paramID) || (param.LegacyID != "" && strings.EqualFold(param.LegacyID, paramID)) {
+			return param, true
+		}
+		legacy = legacy || param.LegacyID != ""
+	}
+	if legacy {
+		return ControlParameter{}, false
+	}
+	for _, alias := range legacyParameterAliases(paramID) {
+		for _, param := range c.Parameters {
+			if strings.EqualFold(param.ID, alias) {
+				return param, true
+			}
+		}
+	}
+	return ControlParameter{}, false
+}
+
+// legacyParameterPattern matches a parameter ID of earlier catalogs, e.g. "ac-2_prm_4" or
+// "ac-2.4_prm_1"
+var legacyParameterPattern = regexp.MustCompile(`(?i)^([a-z]{2})-(\d+)(?:\.(\d+))?_prm_(\d+)$`)
+
+// legacyParameterAliases returns the organization-defined parameter IDs that a parameter ID of
+// earlier catalogs is numbered as, e.g. "ac-02_odp.04" for "ac-2_prm_4". The only parameter of an
+// enhancement has no number, e.g. "ac-02.04_odp".
+func legacyParameterAliases(paramID string) []string {
+	match := legacyParameterPattern.FindStringSubmatch(paramID)
+	if match == nil {
+		return nil
+	}
+	number, _ := strconv.Atoi(match[2])
+	n, _ := strconv.Atoi(match[4])
+	control := fmt.Sprintf("%s-%02d", strings.ToLower(match[1]), number)
+	if match[3] != "" {
+		enhancement, _ := strconv.Atoi(match[3])
+		control += fmt.Sprintf(".%02d", enhancement)
+	}
+	aliases := []string{fmt.Sprintf("%s_odp.%02d", control, n)}
+	if n == 1 {
+		aliases = append(aliases, control+"_odp")
+	}
+	return aliases
+}
+
+// ParameterIDs returns the IDs of the parameters of the program's active controls
+func (p Program) ParameterIDs() []string {
+	var paramIDs []string
+	for _, family := range p.Families {
+		for _, control := range ActiveControls(family.Controls) {
+			for _, param := range control.Parameters {
+				paramIDs = append(paramIDs, param.ID)
+			}
+		}
+	}
+	return paramIDs
+}
+
+// AssignParameters returns the control with the parameter values the system assigns in place of
+// the values set by the program, so that its prose reads with the system's values
+func (r ImplementationRecord) AssignParameters(control Control) Control {
+	if len(r.Parameters) == 0 {
+		return control
+	}
+	parameters := make([]ControlParameter, len(control.Parameters))
+	for i, param := range control.Parameters {
+		param.Values = r.ParameterValues(param)
+		parameters[i] = param
+	}
+	control.Parameters = parameters
+	return control
+}
+
+// ValidateParameterValues checks values assigned to a parameter against its choices and
+// constraints. Values selected from choices are returned as the choices are written.
+func ValidateParameterValues(param ControlParameter, values []string) ([]string, error) {
+	validated := slices.Clone(values)
+	if param.Select != nil {
+		choices := ResolveParameters(strings.Join(param.Select.Choices, "; "), nil)
+		if param.Select.HowMany != "one-or-more" && len(values) > 1 {
+			return nil, NewError(ErrInvalidArgument, "parameter %s takes one value selected from: %s", param.ID, choices)
+		}
+		for i, value := range values {
+			choice, ok := matchChoice(param.Select.Choices, value)
+			if !ok {
+				return nil, NewError(ErrInvalidArgument, "%q is not a choice of parameter %s (choices: %s)", value, param.ID, choices).
+					WithSuggestions(ClosestMatches(value, param.Select.Choices)...)
+			}
+			validated[i] = choice
+		}
+	}
+
+	for _, constraint := range param.Constraints {
+		limit, frequency, ok := parsePeriod(constraint)
+		if !ok {
+			continue // Constraints that do not state a period are left to the assessor
+		}
+		lower := strings.ToLower(constraint)
+		atMost := frequency || containsAny(lower, "every", "not to exceed", "no more than", "not more than", "maximum", "within", "at most", "up to", "or less")
+		atLeast := !atMost && containsAny(lower, "at least", "minimum", "no less than", "not less than", "or more", "or longer")
+		if !atMost && !atLeast {
+			continue
+		}
+		for _, value := range values {
+			period, _, ok := parsePeriod(value)
+			if !ok {
+				return nil, NewError(ErrInvalidArgument, "%q does not state a period to check against the constraint of parameter %s: %s", value, param.ID, constraint)
+			}
+			if (atMost && period > limit) || (atLeast && period < limit) {
+				return nil, NewError(ErrInvalidArgument, "%q does not satisfy the constraint of parameter %s: %s", value, param.ID, constraint)
+			}
+		}
+	}
+	return validated, nil
+}
+
+// matchChoice returns the choice a value selects, ignoring case. Parameter insertions in a choice,
+// such as "{{ insert: param, ac-02_odp.05 }}", match any text.
+func matchChoice(choices []string, value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	for _, choice := range choices {
+		if strings.EqualFold(choice, value) {
+			return choice, true
+		}
+	}
+	for _, choice := range choices {
+		parts := parameterPattern.Split(choice, -1)
+		if len(parts) == 1 {
+			continue
+		}
+		for i, part := range parts {
+			parts[i] = regexp.QuoteMeta(strings.TrimSpace(part))
+		}
+		if regexp.MustCompile(`(?i)^` + strings.Join(parts, `\s*.+?\s*`) + `$`).MatchString(value) {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// periodPattern matches a period such as "90 days" or "three (3) years"
+var periodPattern = regexp.MustCompile(`(?i)\(?(\d+(?:\.\d+)?)\)?\s*(seconds?|minutes?|mins?|hours?|hrs?|days?|weeks?|months?|years?)\b`)
+
+// frequencyPattern matches a frequency such as "annually" or "monthly"
+var frequencyPattern = regexp.MustCompile(`(?i)\b(semi-annually|annually|yearly|quarterly|monthly|weekly|daily|hourly)\b`)
+
+// periodHours is the length of each unit of a period, in hours
+var periodHours = map[string]float64{
+	"second": 1.0 / 3600, "sec": 1.0 / 3600, "minute": 1.0 / 60, "min": 1.0 / 60, "hour": 1, "hr": 1,
+	"day": 24, "week": 168, "month": 730, "year": 8760,
+	"semi-annually": 4380, "annually": 8760, "yearly": 8760, "quarterly": 2190, "monthly": 730,
+	"weekly": 168, "daily": 24, "hourly": 1,
+}
+
+// parsePeriod returns the first period stated in text, in hours, and whether it is stated as a
+// frequency such as "annually"
+func parsePeriod(text string) (float64, bool, bool) {
+	period := periodPattern.FindStringSubmatchIndex(text)
+	frequency := frequencyPattern.FindStringSubmatchIndex(text)
+	if frequency != nil && (period == nil || frequency[0] < period[0]) {
+		return periodHours[strings.ToLower(text[frequency[2]:frequency[3]])], true, true
+	}
+	if period == nil {
+		return 0, false, false
+	}
+	count, err := strconv.ParseFloat(text[period[2]:period[3]], 64)
+	if err != nil {
+		return 0, false, false
+	}
+	unit := strings.TrimSuffix(strings.ToLower(text[period[4]:period[5]]), "s")
+	return count * periodHours[unit], false, true
+}
+
+// containsAny reports whether s contains any of the substrings
+func containsAny(s string, substrings ...string) bool {
+	return slices.ContainsFunc(substrings, func(substring string) bool {
+		return strings.Contains(s, substring)
+	})
+}
+
+// ParameterReport reports which organization-defined parameters (ODPs) of a program a system has
+// assigned values to
+type ParameterReport struct {
+	System           string                `json:"system"`
+	Program          string                `json:"program"`
+	Total            int                   `json:"total"`            // Parameters of the program's active controls
+	AssignedBySystem int                   `json:"assignedBySystem"` // Parameters the system assigns values to
+	SetByProgram     int                   `json:"setByProgram"`     // Parameters without a system value that the program sets
+	Unassigned       []UnassignedParameter `json:"unassigned"`
+}
+
+// UnassignedParameter is a parameter that has no value for a system
+type UnassignedParameter struct {
+	ControlID string `json:"controlId"`
+	ControlParameter
+}
+
+// BuildParameterReport reports the parameters of a program's active controls that have no value
+// from either the system's implementation records or the program
+func BuildParameterReport(program Program, records []ImplementationRecord, system string) ParameterReport {
+	recordsByControl := make(map[string]ImplementationRecord, len(records))
+	for _, record := range records {
+		recordsByControl[record.ControlID] = record
+	}
+
+	report := ParameterReport{System: system, Program: program.Name, Unassigned: []UnassignedParameter{}}
+	for _, family := range program.Families {
+		for _, control := range ActiveControls(family.Controls) {
+			record := recordsByControl[control.ID]
+			for _, param := range control.Parameters {
+				report.Total++
+				switch {
+				case len(record.Parameters[param.ID]) > 0:
+					report.AssignedBySystem++
+				case len(param.Values) > 0:
+					report.SetByProgram++
+				default:
+					report.Unassigned = append(report.Unassigned, UnassignedParameter{ControlID: control.ID, ControlParameter: param})
+				}
+			}
+		}
+	}
+	return report
+}
@@ -0,0 +1,102 @@
+package fedramp
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestValidateParameterValues(t *testing.T) {
+	one := &ParameterSelection{Choices: []string{"organization-level", "mission/business process-level", "system-level"}}
+	oneOrMore := &ParameterSelection{HowMany: "one-or-more", Choices: one.Choices}
+	withInsertion := &ParameterSelection{Choices: []string{"disable", "notify {{ insert: param, ac-02.03_odp.02 }}"}}
+
+	tests := []struct {
+		name   string
+		param  ControlParameter
+		values []string
+		want   []string // nil if the values are rejected
+	}{
+		// Choices
+		{"choice", ControlParameter{Select: one}, []string{"system-level"}, []string{"system-level"}},
+		{"choice in another case", ControlParameter{Select: one}, []string{"System-Level"}, []string{"system-level"}},
+		{"not a choice", ControlParameter{Select: one}, []string{"banana"}, nil},
+		{"several values for one choice", ControlParameter{Select: one}, []string{"system-level", "organization-level"}, nil},
+		{"several choices", ControlParameter{Select: oneOrMore}, []string{"system-level", "organization-level"}, []string{"system-level", "organization-level"}},
+		{"choice with an insertion", ControlParameter{Select: withInsertion}, []string{"notify the ISSO"}, []string{"notify the ISSO"}},
+		{"choice without its insertion", ControlParameter{Select: withInsertion}, []string{"notify"}, nil},
+
+		// At most a period
+		{"every period", ControlParameter{Constraints: []string{"at least every 90 days"}}, []string{"30 days"}, []string{"30 days"}},
+		{"exactly the period", ControlParameter{Constraints: []string{"at least every 90 days"}}, []string{"90 days"}, []string{"90 days"}},
+		{"longer than every period", ControlParameter{Constraints: []string{"at least every 90 days"}}, []string{"6 months"}, nil},
+		{"period in other units", ControlParameter{Constraints: []string{"at least every 90 days"}}, []string{"2 months"}, []string{"2 months"}},
+		{"frequency within a frequency", ControlParameter{Constraints: []string{"at least annually"}}, []string{"quarterly"}, []string{"quarterly"}},
+		{"frequency beyond a frequency", ControlParameter{Constraints: []string{"at least monthly"}}, []string{"annually"}, nil},
+		{"within a period", ControlParameter{Constraints: []string{"within one (1) hour"}}, []string{"30 minutes"}, []string{"30 minutes"}},
+		{"beyond within a period", ControlParameter{Constraints: []string{"within one (1) hour"}}, []string{"2 hours"}, nil},
+		{"no period", ControlParameter{Constraints: []string{"at least every 90 days"}}, []string{"banana"}, nil},
+
+		// At least a period
+		{"retained long enough", ControlParameter{Constraints: []string{"at least one (1) year"}}, []string{"3 years"}, []string{"3 years"}},
+		{"not retained long enough", ControlParameter{Constraints: []string{"at least one (1) year"}}, []string{"90 days"}, nil},
+		{"minimum that is not a period", ControlParameter{Constraints: []string{"a minimum of 15 characters"}}, []string{"12 characters"}, []string{"12 characters"}},
+
+		// Constraints that state no period are left to the assessor
+		{"other constraint", ControlParameter{Constraints: []string{"to include the ISSO"}}, []string{"banana"}, []string{"banana"}},
+		{"no choices or constraints", ControlParameter{}, []string{"anything"}, []string{"anything"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.param.ID = "ac-02_odp.10"
+			got, err := ValidateParameterValues(tt.param, tt.values)
+			if tt.want == nil {
+				if err == nil {
+					t.Fatalf("ValidateParameterValues(%q) = %q, want an error", tt.values, got)
+				}
+				if KindOf(err) != ErrInvalidArgument {
+					t.Errorf("ValidateParameterValues(%q) error kind = %s, want %s", tt.values, KindOf(err), ErrInvalidArgument)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ValidateParameterValues(%q) returned error: %v", tt.values, err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ValidateParameterValues(%q) = %q, want %q", tt.values, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindParameter(t *testing.T) {
+	program := Program{Name: "Test", Families: []ControlFamily{{ID: "ac", Controls: []Control{
+		{ID: "ac-2", Parameters: []ControlParameter{{ID: "ac-02_odp.01"}, {ID: "ac-02_odp.04"}}},
+		{ID: "ac-2.4", Parameters: []ControlParameter{{ID: "ac-02.04_odp"}}},
+		{ID: "ac-3", Parameters: []ControlParameter{{ID: "ac-03_odp.01", LegacyID: "ac-3_prm_1"}, {ID: "ac-03_odp.02", LegacyID: "ac-3_prm_1"}, {ID: "ac-03_odp.03", LegacyID: "ac-3_prm_2"}}},
+	}}}}
+
+	tests := []struct {
+		paramID string
+		want    string // empty if not found
+	}{
+		{"ac-02_odp.04", "ac-02_odp.04"},
+		{" AC-02_ODP.04 ", "ac-02_odp.04"},
+		// Catalogs without earlier IDs number them as ODPs
+		{"ac-2_prm_4", "ac-02_odp.04"},
+		{"AC-2_PRM_1", "ac-02_odp.01"},
+		{"ac-2.4_prm_1", "ac-02.04_odp"},
+		{"ac-2_prm_9", ""},
+		// Catalogs with earlier IDs use them, as their numbering differs
+		{"ac-3_prm_2", "ac-03_odp.03"},
+		{"ac-3_prm_1", "ac-03_odp.01"},
+		{"ac-3_prm_3", ""},
+		{"ac-99_odp.01", ""},
+	}
+	for _, tt := range tests {
+		_, param, ok := program.FindParameter(tt.paramID)
+		if got := param.ID; !ok && tt.want != "" || ok && got != tt.want {
+			t.Errorf("FindParameter(%q) = %q, %v, want %q", tt.paramID, got, ok, tt.want)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package fedramp
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// PolicyDocumentKind is the kind of document a family's policy and procedures control (e.g. AC-1)
+// requires
+type PolicyDocumentKind string
+
+const (
+	PolicyDocumentPolicy    PolicyDocumentKind = "policy"
+	PolicyDocumentProcedure PolicyDocumentKind = "procedure"
+)
+
+// PolicyDocumentKinds lists the names of the policy document kinds
+var PolicyDocumentKinds = []string{
+	string(PolicyDocumentPolicy),
+	string(PolicyDocumentProcedure),
+}
+
+// ParsePolicyDocumentKind parses a policy document kind, accepting plurals such as "procedures"
+func ParsePolicyDocumentKind(s string) (PolicyDocumentKind, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "policy", "policies":
+		return PolicyDocumentPolicy, nil
+	case "procedure", "procedures":
+		return PolicyDocumentProcedure, nil
+	}
+	return "", NewError(ErrInvalidArgument, "unknown policy document kind %q (valid kinds: %s)", s, strings.Join(PolicyDocumentKinds, ", ")).
+		WithSuggestions(ClosestMatches(s, PolicyDocumentKinds)...)
+}
+
+// PolicyDocument is a Markdown policy or procedure document generated for a control family
+type PolicyDocument struct {
+	Kind     PolicyDocumentKind `json:"kind"`
+	FamilyID string             `json:"familyId"`
+	Title    string             `json:"title"`    // e.g. "Access Control Policy"
+	Template string             `json:"template"` // Name of the template the document was generated from
+	Markdown string             `json:"markdown"`
+}
+
+// PolicyTemplateData is the data a policy or procedure template is executed with. Parameters
+// without a value read as placeholders such as "[Assignment: frequency]", for the author to fill in.
+type PolicyTemplateData struct {
+	Kind            PolicyDocumentKind
+	Title           string // e.g. "Access Control Policy" or "Access Control Procedures"
+	Program         string
+	System          string          // System whose ODP assignments are used, if any
+	FamilyID        string          // e.g. "AC"
+	FamilyTitle     string          // e.g. "Access Control"
+	PolicyControl   PolicyControl   // The family's policy and procedures control, e.g. AC-1
+	Level           PolicyParameter // Level of the policy, e.g. "organization-level"
+	Official        PolicyParameter // Official who manages the policy and procedures
+	Recipients      PolicyParameter // Personnel or roles the document is disseminated to
+	ReviewFrequency PolicyParameter // How often the document is reviewed and updated
+	ReviewEvents    PolicyParameter // Events after which the document is reviewed and updated
+	Controls        []PolicyControl // The family's other applicable controls, in catalog order
+}
+
+// PolicyControl is a control as a policy or procedure template uses it
+type PolicyControl struct {
+	ID         string // e.g. "AC-2(1)"
+	Title      string
+	Statement  string // The statement as a Markdown list, with parameter values or placeholders
+	Parameters []PolicyParameter
+}
+
+// PolicyParameter is a parameter with its value for the system or program, or a placeholder
+type PolicyParameter struct {
+	ID       string
+	Label    string
+	Value    string // The values joined, or a placeholder if the parameter has none
+	Assigned bool   // Whether the system or program gives the parameter a value
+}
+
+// String returns the parameter's value or placeholder, so that templates can write {{.Official}}
+func (p PolicyParameter) String() string {
+	return p.Value
+}
+
+// policyTemplateFuncs are the functions available to policy and procedure templates
+var policyTemplateFuncs = template.FuncMap{
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"join":  strings.Join,
+}
+
+// PolicyControlID returns the ID of a family's policy and procedures control, e.g. "ac-1"
+func PolicyControlID(familyID string) string {
+	return NormalizeFamilyID(familyID) + "-1"
+}
+
+// BuildPolicyTemplateData collects what a family's policy or procedure document is generated from:
+// the family's policy and procedures control and its organization-defined parameters (ODPs), and
+// the family's other controls with their statements. Values the system assigns replace the
+// program's. Controls that an overlay or the system's records mark not applicable are left out.
+func BuildPolicyTemplateData(program Program, family ControlFamily, records []ImplementationRecord, system string, kind PolicyDocumentKind) (PolicyTemplateData, error) {
+	recordsByControl := make(map[string]ImplementationRecord, len(records))
+	for _, record := range records {
+		recordsByControl[record.ControlID] = record
+	}
+	controls := ActiveControls(family.Controls)
+	index := slices.IndexFunc(controls, func(control Control) bool {
+		return NormalizeControlID(control.ID) == NormalizeControlID(PolicyControlID(family.ID))
+	})
+	if index < 0 {
+		return PolicyTemplateData{}, NewError(ErrNotFound, "control family %s has no policy and procedures control in %s", strings.ToUpper(family.ID), program.Name)
+	}
+	policyControl := recordsByControl[controls[index].ID].AssignParameters(controls[index])
+
+	title := family.Title + " Policy"
+	recipients, frequency, events := 1, 5, 6
+	if kind == PolicyDocumentProcedure {
+		title = family.Title + " Procedures"
+		recipients, frequency, events = 2, 7, 8
+	}
+	data := PolicyTemplateData{
+		Kind:            kind,
+		Title:           title,
+		Program:         program.Name,
+		System:          system,
+		FamilyID:        strings.ToUpper(family.ID),
+		FamilyTitle:     family.Title,
+		PolicyControl:   newPolicyControl(policyControl),
+		Level:           policyParameter(policyControl, 3, "[Selection (one or more): organization-level; mission/business process-level; system-level]"),
+		Official:        policyParameter(policyControl, 4, "[Assignment: official]"),
+		Recipients:      policyParameter(policyControl, recipients, "[Assignment: personnel or roles]"),
+		ReviewFrequency: policyParameter(policyControl, frequency, "[Assignment: frequency]"),
+		ReviewEvents:    policyParameter(policyControl, events, "[Assignment: events]"),
+		Controls:        []PolicyControl{},
+	}
+	for i, control := range controls {
+		record, ok := recordsByControl[control.ID]
+		if i == index || control.NotApplicable() || (ok && record.Status == StatusNotApplicable) {
+			continue
+		}
+		data.Controls = append(data.Controls, newPolicyControl(record.AssignParameters(control)))
+	}
+	return data, nil
+}
+
+// RenderPolicyDocument executes a policy or procedure template with the data for a family
+func RenderPolicyDocument(data PolicyTemplateData, name, text string) (PolicyDocument, error) {
+	tmpl, err := template.New(name).Funcs(policyTemplateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return PolicyDocument{}, WrapError(ErrInvalidArgument, err, "failed to parse template %s", name)
+	}
+	var markdown strings.Builder
+	if err := tmpl.Execute(&markdown, data); err != nil {
+		return PolicyDocument{}, WrapError(ErrInvalidArgument, err, "failed to execute template %s", name)
+	}
+	return PolicyDocument{
+		Kind:     data.Kind,
+		FamilyID: data.FamilyID,
+		Title:    data.Title,
+		Template: name,
+		Markdown: strings.TrimSpace(markdown.String()) + "\n",
+	}, nil
+}
+
+// newPolicyControl returns a control as templates use it, with its statement as a Markdown list
+func newPolicyControl(control Control) PolicyControl {
+	statement := NewDocument(FormatMarkdown)
+	statement.statements(control)
+	policyControl := PolicyControl{
+		ID:        ControlLabel(control.ID),
+		Title:     control.Title,
+		Statement: strings.TrimSpace(statement.String()),
+	}
+	for _, param := range control.Parameters {
+		policyControl.Parameters = append(policyControl.Parameters, newPolicyParameter(param, ""))
+	}
+	return policyControl
+}
+
+// policyParameter returns the nth organization-defined parameter of a policy and procedures
+// control, e.g. ac-01_odp.04 for 4, which has the same meaning in every family
+func policyParameter(control Control, n int, placeholder string) PolicyParameter {
+	suffix := fmt.Sprintf("_odp.%02d", n)
+	for _, param := range control.Parameters {
+		if strings.HasSuffix(param.ID, suffix) {
+			return newPolicyParameter(param, placeholder)
+		}
+	}
+	return PolicyParameter{Value: placeholder}
+}
+
+// newPolicyParameter returns a parameter with its values, or a placeholder for a parameter without
+// any: the given one, or one made from the parameter's choices or label
+func newPolicyParameter(param ControlParameter, placeholder string) PolicyParameter {
+	policyParam := PolicyParameter{ID: param.ID, Label: param.Label}
+	switch {
+	case len(param.Values) > 0:
+		policyParam.Value = strings.Join(param.Values, ", ")
+		policyParam.Assigned = true
+	case placeholder != "":
+		policyParam.Value = placeholder
+	case param.Select != nil:
+		policyParam.Value = "[Selection: " + ResolveParameters(strings.Join(param.Select.Choices, "; "), nil) + "]"
+	default:
+		policyParam.Value = "[Assignment: " + cmp.Or(param.Label, param.ID) + "]"
+	}
+	return policyParam
+}
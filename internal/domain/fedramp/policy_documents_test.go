@@ -0,0 +1,142 @@
+package fedramp
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// policyTestFamily returns a family with a policy and procedures control, a control with a
+// parameter, a control a system may mark not applicable and a withdrawn control
+func policyTestFamily() ControlFamily {
+	var params []ControlParameter
+	for _, id := range []string{"01", "02", "03", "04", "05", "06", "07", "08"} {
+		params = append(params, ControlParameter{ID: "at-01_odp." + id})
+	}
+	params[3].Values = []string{"the CISO"}
+	params[4].Label = "frequency"
+	return ControlFamily{ID: "at", Title: "Awareness and Training", Controls: []Control{
+		{ID: "at-1", Title: "Policy and Procedures", Parameters: params},
+		{
+			ID:         "at-2",
+			Title:      "Literacy Training and Awareness",
+			Statements: []ControlStatement{{ID: "at-2_smt", Name: "statement", Prose: "Provide training {{ insert: param, at-02_odp.01 }}."}},
+			Parameters: []ControlParameter{{ID: "at-02_odp.01", Label: "frequency"}},
+		},
+		{ID: "at-3", Title: "Role-based Training"},
+		{ID: "at-4", Title: "Training Records", Status: "withdrawn"},
+	}}
+}
+
+func TestBuildPolicyTemplateData(t *testing.T) {
+	family := policyTestFamily()
+	program := Program{Name: "FedRAMP Moderate", Families: []ControlFamily{family}}
+	key := func(controlID string) ImplementationKey {
+		return ImplementationKey{System: "Acme Cloud", Program: program.Name, ControlID: controlID}
+	}
+	records := []ImplementationRecord{
+		{ImplementationKey: key("at-1"), Parameters: map[string][]string{"at-01_odp.05": {"annually"}}},
+		{ImplementationKey: key("at-2"), Parameters: map[string][]string{"at-02_odp.01": {"monthly"}}},
+		{ImplementationKey: key("at-3"), Status: StatusNotApplicable},
+	}
+
+	tests := []struct {
+		name            string
+		kind            PolicyDocumentKind
+		records         []ImplementationRecord
+		title           string
+		reviewFrequency PolicyParameter
+		controls        []string
+		statement       string
+	}{
+		{
+			name:            "policy with the system's assignments",
+			kind:            PolicyDocumentPolicy,
+			records:         records,
+			title:           "Awareness and Training Policy",
+			reviewFrequency: PolicyParameter{ID: "at-01_odp.05", Label: "frequency", Value: "annually", Assigned: true},
+			controls:        []string{"AT-2"},
+			statement:       "Provide training monthly.",
+		},
+		{
+			name:            "procedures without a system",
+			kind:            PolicyDocumentProcedure,
+			title:           "Awareness and Training Procedures",
+			reviewFrequency: PolicyParameter{ID: "at-01_odp.07", Value: "[Assignment: frequency]"},
+			controls:        []string{"AT-2", "AT-3"},
+			statement:       "Provide training [Assignment: frequency].",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := BuildPolicyTemplateData(program, family, tt.records, "Acme Cloud", tt.kind)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if data.Title != tt.title || data.FamilyID != "AT" || data.PolicyControl.ID != "AT-1" {
+				t.Errorf("data = %+v, want the title %q for AT-1", data, tt.title)
+			}
+			if want := (PolicyParameter{ID: "at-01_odp.04", Value: "the CISO", Assigned: true}); data.Official != want {
+				t.Errorf("official = %+v, want the program's value %+v", data.Official, want)
+			}
+			if data.ReviewFrequency != tt.reviewFrequency {
+				t.Errorf("review frequency = %+v, want %+v", data.ReviewFrequency, tt.reviewFrequency)
+			}
+			var controls []string
+			for _, control := range data.Controls {
+				controls = append(controls, control.ID)
+			}
+			if !slices.Equal(controls, tt.controls) {
+				t.Fatalf("controls = %v, want %v", controls, tt.controls)
+			}
+			if data.Controls[0].Statement != tt.statement {
+				t.Errorf("AT-2 statement = %q, want %q", data.Controls[0].Statement, tt.statement)
+			}
+		})
+	}
+
+	if _, err := BuildPolicyTemplateData(program, ControlFamily{ID: "pm", Title: "Program Management"}, nil, "", PolicyDocumentPolicy); KindOf(err) != ErrNotFound {
+		t.Errorf("error = %v, want not found for a family without a policy and procedures control", err)
+	}
+}
+
+func TestRenderPolicyDocument(t *testing.T) {
+	family := policyTestFamily()
+	data, err := BuildPolicyTemplateData(Program{Name: "FedRAMP Moderate", Families: []ControlFamily{family}}, family, nil, "", PolicyDocumentPolicy)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		want     string
+		kind     ErrorKind
+	}{
+		{"fields and functions", "# {{.Title}}\n\nOwned by {{.Official}}.\n{{range .Controls}}\n## {{.ID}} {{upper .Title}}\n{{end}}\n\n",
+			"# Awareness and Training Policy\n\nOwned by the CISO.\n\n## AT-2 LITERACY TRAINING AND AWARENESS\n\n## AT-3 ROLE-BASED TRAINING\n", ""},
+		{"placeholder", "Review {{.ReviewFrequency}}", "Review [Assignment: frequency]\n", ""},
+		{"unclosed action", "# {{.Title", "", ErrInvalidArgument},
+		{"unknown field", "{{.Owner}}", "", ErrInvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			document, err := RenderPolicyDocument(data, "test", tt.template)
+			if tt.kind != "" {
+				if KindOf(err) != tt.kind || !strings.Contains(err.Error(), "template test") {
+					t.Errorf("error = %v, want %s naming the template", err, tt.kind)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if document.Markdown != tt.want {
+				t.Errorf("markdown = %q, want %q", document.Markdown, tt.want)
+			}
+			if document.Kind != PolicyDocumentPolicy || document.FamilyID != "AT" || document.Template != "test" {
+				t.Errorf("document = %+v, want the policy of AT from template test", document)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package fedramp
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ControlFields lists the control fields that can be selected with a field projection
+var ControlFields = []string{
+	"id",
+	"title",
+	"parameters",
+	"statements",
+	"guidance",
+	"assessmentObjectives",
+	"fullText",
+	"evidenceGuidance",
+	"status",
+	"incorporatedInto",
+	"movedTo",
+	"relatedControls",
+	"tailoring",
+}
+
+// ParseFields parses a comma-separated list of control fields, validating each against ControlFields.
+// An empty list selects every field.
+func ParseFields(fields string) ([]string, error) {
+	var selected []string
+	for _, field := range strings.Split(fields, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		known := false
+		for _, candidate := range ControlFields {
+			if strings.EqualFold(candidate, field) {
+				selected = append(selected, candidate)
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, NewError(ErrInvalidArgument, "unknown field %q (valid fields: %s)", field, strings.Join(ControlFields, ", ")).
+				WithSuggestions(ClosestMatches(field, ControlFields)...)
+		}
+	}
+	return selected, nil
+}
+
+// ProjectControl returns a representation of the control containing only the selected fields.
+// The control ID is always included so that projected results remain addressable, and so are the
+// status of a withdrawn control and the tailoring of a control an overlay marked not applicable, so
+// that neither is mistaken for an active one.
+func ProjectControl(control Control, fields []string) (map[string]any, error) {
+	data, err := json.Marshal(control)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal control: %v", err)
+	}
+
+	var full map[string]any
+	if err := json.Unmarshal(data, &full); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal control: %v", err)
+	}
+	if len(fields) == 0 {
+		return full, nil
+	}
+
+	projected := map[string]any{"id": full["id"]}
+	if control.IsWithdrawn() {
+		projected["status"] = full["status"]
+	}
+	if control.NotApplicable() {
+		projected["tailoring"] = full["tailoring"]
+	}
+	for _, field := range fields {
+		if value, ok := full[field]; ok {
+			projected[field] = value
+		}
+	}
+	return projected, nil
+}
+
+// ProjectControls applies a field projection to each control
+func ProjectControls(controls []Control, fields []string) ([]map[string]any, error) {
+	projected := make([]map[string]any, 0, len(controls))
+	for _, control := range controls {
+		item, err := ProjectControl(control, fields)
+		if err != nil {
+			return nil, err
+		}
+		projected = append(projected, item)
+	}
+	return projected, nil
+}
@@ -0,0 +1,665 @@
+package fedramp
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Format is an output format for compliance data
+type Format string
+
+const (
+	FormatJSON     Format = "json"
+	FormatMarkdown Format = "markdown"
+	FormatText     Format = "text"
+)
+
+// Formats lists the names of the supported output formats
+var Formats = []string{string(FormatJSON), string(FormatMarkdown), string(FormatText)}
+
+// ParseFormat parses the name of an output format. An empty name selects JSON.
+func ParseFormat(s string) (Format, error) {
+	switch Format(strings.ToLower(strings.TrimSpace(s))) {
+	case "", FormatJSON:
+		return FormatJSON, nil
+	case FormatMarkdown, "md":
+		return FormatMarkdown, nil
+	case FormatText, "txt":
+		return FormatText, nil
+	}
+	return "", NewError(ErrInvalidArgument, "unknown format %q (valid formats: %s)", s, strings.Join(Formats, ", ")).
+		WithSuggestions(ClosestMatches(s, Formats)...)
+}
+
+// parameterPattern matches a parameter insertion in OSCAL prose, e.g. "{{ insert: param, ac-02_odp.01 }}"
+var parameterPattern = regexp.MustCompile(`\{\{\s*insert:\s*param,\s*([^\s}]+)\s*\}\}`)
+
+// ResolveParameters replaces the parameter insertions in prose with the parameter's values, or
+// with an "[Assignment: label]" placeholder for parameters that have no value
+func ResolveParameters(prose string, parameters []ControlParameter) string {
+	return parameterPattern.ReplaceAllStringFunc(prose, func(insertion string) string {
+		id := parameterPattern.FindStringSubmatch(insertion)[1]
+		for _, param := range parameters {
+			if param.ID != id {
+				continue
+			}
+			if len(param.Values) > 0 {
+				return strings.Join(param.Values, ", ")
+			}
+			if param.Label != "" {
+				return "[Assignment: " + param.Label + "]"
+			}
+		}
+		return "[Assignment: " + id + "]"
+	})
+}
+
+// blankLinePattern matches the blank lines that separate paragraphs
+var blankLinePattern = regexp.MustCompile(`\n\s*\n`)
+
+// Document builds a Markdown or plain text rendering of compliance data. Documents for the JSON
+// format are rendered as Markdown.
+type Document struct {
+	format Format
+	text   strings.Builder
+}
+
+// NewDocument creates an empty document in a format
+func NewDocument(format Format) *Document {
+	if format != FormatText {
+		format = FormatMarkdown
+	}
+	return &Document{format: format}
+}
+
+// Heading adds a heading, where level 1 is the document title
+func (d *Document) Heading(level int, text string) {
+	if d.format == FormatMarkdown {
+		fmt.Fprintf(&d.text, "%s %s\n\n", strings.Repeat("#", level), text)
+		return
+	}
+	switch level {
+	case 1:
+		fmt.Fprintf(&d.text, "%s\n%s\n\n", text, strings.Repeat("=", len([]rune(text))))
+	case 2:
+		fmt.Fprintf(&d.text, "%s\n%s\n\n", text, strings.Repeat("-", len([]rune(text))))
+	default:
+		fmt.Fprintf(&d.text, "%s:\n\n", text)
+	}
+}
+
+// Paragraph adds text as one or more paragraphs. Blank lines separate paragraphs and other line
+// breaks within the text are kept.
+func (d *Document) Paragraph(text string) {
+	for _, paragraph := range blankLinePattern.Split(strings.TrimSpace(text), -1) {
+		var lines []string
+		for _, line := range strings.Split(paragraph, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				lines = append(lines, line)
+			}
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		separator := "\n"
+		if d.format == FormatMarkdown {
+			separator = "  \n"
+		}
+		d.text.WriteString(strings.Join(lines, separator))
+		d.text.WriteString("\n\n")
+	}
+}
+
+// Field adds a labelled value
+func (d *Document) Field(name, value string) {
+	if d.format == FormatMarkdown {
+		fmt.Fprintf(&d.text, "**%s:** %s\n\n", name, value)
+		return
+	}
+	fmt.Fprintf(&d.text, "%s: %s\n\n", name, value)
+}
+
+// Item adds a list item at a nesting depth, with an optional label such as "a." or "AC-2".
+// Call EndList after the last item of a list.
+func (d *Document) Item(depth int, label, text string) {
+	text = strings.Join(strings.Fields(text), " ")
+	var item string
+	switch {
+	case d.format == FormatMarkdown && label != "":
+		item = fmt.Sprintf("- **%s** %s", label, text)
+	case d.format == FormatMarkdown:
+		item = "- " + text
+	case label != "":
+		item = label + " " + text
+	default:
+		item = "* " + text
+	}
+	d.text.WriteString(strings.Repeat("  ", depth) + strings.TrimSpace(item) + "\n")
+}
+
+// EndList ends a list started with Item
+func (d *Document) EndList() {
+	d.text.WriteString("\n")
+}
+
+// PageSummary describes which part of a paged result set the document contains
+func (d *Document) PageSummary(offset, count, total int, nextCursor string) {
+	switch {
+	case total == 0:
+		d.Paragraph("No results.")
+	case nextCursor == "":
+		d.Paragraph(fmt.Sprintf("Showing %d–%d of %d.", offset+1, offset+count, total))
+	case d.format == FormatMarkdown:
+		d.Paragraph(fmt.Sprintf("Showing %d–%d of %d. Pass cursor `%s` for more.", offset+1, offset+count, total, nextCursor))
+	default:
+		d.Paragraph(fmt.Sprintf("Showing %d–%d of %d. Pass cursor %s for more.", offset+1, offset+count, total, nextCursor))
+	}
+}
+
+// String returns the rendered document
+func (d *Document) String() string {
+	return strings.TrimRight(d.text.String(), "\n") + "\n"
+}
+
+// Control adds a control under a heading of the given level. Only the selected fields are
+// rendered, or every field if none are selected.
+func (d *Document) Control(control Control, level int, fields []string) {
+	selected := func(field string) bool {
+		return len(fields) == 0 || slices.Contains(fields, field)
+	}
+
+	d.Heading(level, strings.TrimSpace(ControlLabel(control.ID)+" "+control.Title))
+	if notice := control.WithdrawnNotice(); notice != "" {
+		d.Paragraph(notice)
+	}
+	if notice := control.TailoringNotice(); notice != "" && (selected("tailoring") || control.NotApplicable()) {
+		d.Paragraph(notice)
+	}
+
+	if (selected("statements") || selected("fullText")) && (len(control.Statements) > 0 || control.FullText != "") {
+		d.Heading(level+1, "Statement")
+		d.statements(control)
+	}
+
+	if selected("parameters") && len(control.Parameters) > 0 {
+		d.Heading(level+1, "Parameters")
+		for _, param := range control.Parameters {
+			d.Item(0, param.ID, parameterDescription(param))
+		}
+		d.EndList()
+	}
+
+	if selected("guidance") && control.Guidance != "" {
+		d.Heading(level+1, "Guidance")
+		d.Paragraph(control.Guidance)
+	}
+
+	if selected("assessmentObjectives") && len(control.AssessmentObjectives) > 0 {
+		d.Heading(level+1, "Assessment Objectives")
+		d.objectives(control)
+	}
+
+	if selected("evidenceGuidance") && len(control.EvidenceGuidance.Determinations) > 0 {
+		d.Heading(level+1, "Evidence Guidance")
+		d.EvidenceGuidance(control.EvidenceGuidance)
+	}
+}
+
+// Controls adds a list of controls. Controls projected to just their ID and title are listed
+// one per line; otherwise each control is rendered in full under a heading of the given level.
+func (d *Document) Controls(controls []Control, level int, fields []string) {
+	summary := len(fields) > 0 && !slices.ContainsFunc(fields, func(field string) bool {
+		return field != "id" && field != "title"
+	})
+	if summary {
+		for _, control := range controls {
+			title := control.Title
+			if control.IsWithdrawn() {
+				title += " (withdrawn)"
+			}
+			if control.NotApplicable() {
+				title += " (not applicable)"
+			}
+			d.Item(0, ControlLabel(control.ID), title)
+		}
+		d.EndList()
+		return
+	}
+	for _, control := range controls {
+		d.Control(control, level, fields)
+	}
+}
+
+// statements adds the statement of a control as a labelled outline
+func (d *Document) statements(control Control) {
+	if len(control.Statements) == 0 {
+		d.Paragraph(ResolveParameters(control.FullText, control.Parameters))
+		return
+	}
+
+	for _, statement := range control.Statements {
+		d.Paragraph(ResolveParameters(statement.Prose, control.Parameters))
+		d.statementParts(statement.Parts, control.Parameters, 0)
+	}
+	d.EndList()
+}
+
+// statementParts adds statement parts and their nested parts as a nested list. Parts with neither
+// a label nor prose, such as the heading of FedRAMP's requirements, add no item of their own.
+func (d *Document) statementParts(parts []ControlStatement, parameters []ControlParameter, depth int) {
+	for _, part := range parts {
+		if part.Label == "" && strings.TrimSpace(part.Prose) == "" {
+			d.statementParts(part.Parts, parameters, depth)
+			continue
+		}
+		d.Item(depth, part.Label, ResolveParameters(part.Prose, parameters))
+		d.statementParts(part.Parts, parameters, depth+1)
+	}
+}
+
+// objectives adds the assessment objectives of a control as a labelled outline, with the methods
+// used to assess each objective
+func (d *Document) objectives(control Control) {
+	for _, objective := range control.AssessmentObjectives {
+		d.Paragraph(ResolveParameters(objective.Prose, control.Parameters))
+		if methods := objectiveMethods(objective); len(methods) > 0 {
+			d.Field("Methods", strings.Join(methods, ", "))
+		}
+		d.objectiveParts(objective.Parts, control.Parameters, 0)
+	}
+	d.EndList()
+}
+
+// objectiveParts adds assessment objectives and their nested objectives as a nested list
+func (d *Document) objectiveParts(parts []AssessmentObjective, parameters []ControlParameter, depth int) {
+	for _, part := range parts {
+		text := ResolveParameters(part.Prose, parameters)
+		if methods := objectiveMethods(part); len(methods) > 0 {
+			text += " (" + strings.Join(methods, ", ") + ")"
+		}
+		d.Item(depth, part.Label, text)
+		d.objectiveParts(part.Parts, parameters, depth+1)
+	}
+}
+
+// EvidenceGuidance adds the evidence expected for each determination statement of a control
+func (d *Document) EvidenceGuidance(guidance EvidenceGuidance) {
+	if len(guidance.Methods) > 0 {
+		d.Field("Methods", strings.Join(guidance.Methods, ", "))
+	}
+	for _, determination := range guidance.Determinations {
+		d.Item(0, determination.Label, determination.Statement)
+		d.Item(1, "Methods:", strings.Join(determination.Methods, ", "))
+		d.Item(1, "Artifacts:", strings.Join(determination.ArtifactTypes, "; "))
+		if len(determination.Objects) > 0 {
+			d.Item(1, "Objects:", strings.Join(determination.Objects, "; "))
+		}
+	}
+	d.EndList()
+}
+
+// SSPSummary adds a summary of a system security plan: the system, its baseline and how many
+// controls have each implementation status
+func (d *Document) SSPSummary(ssp OSCALSystemSecurityPlan) {
+	plan := ssp.SystemSecurityPlan
+	d.Heading(1, plan.Metadata.Title)
+	d.Field("System", plan.SystemCharacteristics.SystemName)
+	d.Field("Profile", plan.ImportProfile.Href)
+	d.Field("Last modified", plan.Metadata.LastModified)
+	d.Field("Controls", strconv.Itoa(len(plan.ControlImplementation.ImplementedRequirements)))
+
+	counts := make(map[string]int)
+	for _, requirement := range plan.ControlImplementation.ImplementedRequirements {
+		counts[requirement.ImplementationState()]++
+	}
+	d.Heading(2, "Implementation Status")
+	for _, status := range append(slices.Clone(ImplementationStatuses), "") {
+		if counts[status] == 0 {
+			continue
+		}
+		label := status
+		if label == "" {
+			label = "not documented"
+		}
+		d.Item(0, label, strconv.Itoa(counts[status]))
+	}
+	d.EndList()
+}
+
+// SSPImport adds the report of an SSP import: the controls imported and the controls missing from
+// the plan or the program
+func (d *Document) SSPImport(result SSPImport) {
+	title := "SSP Import for " + result.System
+	if result.DryRun {
+		title += " (dry run)"
+	}
+	d.Heading(1, title)
+	d.Field("Program", result.Program)
+	d.Field("Imported", strconv.Itoa(len(result.Controls)))
+
+	if len(result.Controls) > 0 {
+		d.Heading(2, "Imported Controls")
+		for _, control := range result.Controls {
+			d.Item(0, ControlLabel(control.ID), fmt.Sprintf("%s, %d statement narratives, %d parameter values", control.Status, control.Statements, control.Parameters))
+		}
+		d.EndList()
+	}
+	if len(result.MissingFromSSP) > 0 {
+		d.Heading(2, "In the Baseline but Missing from the SSP")
+		for _, control := range result.MissingFromSSP {
+			d.Item(0, ControlLabel(control.ID), control.Title)
+		}
+		d.EndList()
+	}
+	if len(result.NotInBaseline) > 0 {
+		d.Heading(2, "In the SSP but Not in the Baseline")
+		for _, controlID := range result.NotInBaseline {
+			d.Item(0, "", ControlLabel(controlID))
+		}
+		d.EndList()
+	}
+	if len(result.Undocumented) > 0 {
+		d.Heading(2, "Listed Without an Implementation")
+		for _, controlID := range result.Undocumented {
+			d.Item(0, "", ControlLabel(controlID))
+		}
+		d.EndList()
+	}
+	if len(result.Warnings) > 0 {
+		d.Heading(2, "Warnings")
+		for _, warning := range result.Warnings {
+			d.Item(0, "", warning)
+		}
+		d.EndList()
+	}
+}
+
+// CRMImport adds the report of a CRM import: the responsibilities imported for each control
+func (d *Document) CRMImport(result CRMImport) {
+	title := "CRM Import for " + result.System
+	if result.DryRun {
+		title += " (dry run)"
+	}
+	d.Heading(1, title)
+	d.Field("Program", result.Program)
+	d.Field("Leveraged authorization", result.LeveragedAuthorization)
+	d.Field("Imported", strconv.Itoa(len(result.Controls)))
+
+	if len(result.Controls) > 0 {
+		d.Heading(2, "Imported Controls")
+		for _, control := range result.Controls {
+			responsibility := "by statement"
+			if control.Responsibility != nil {
+				responsibility = string(*control.Responsibility)
+			}
+			d.Item(0, ControlLabel(control.ID), fmt.Sprintf("%s, %d statement responsibilities", responsibility, control.Statements))
+		}
+		d.EndList()
+	}
+	if len(result.NotInBaseline) > 0 {
+		d.Heading(2, "In the CRM but Not in the Baseline")
+		for _, controlID := range result.NotInBaseline {
+			d.Item(0, "", ControlLabel(controlID))
+		}
+		d.EndList()
+	}
+	if len(result.Warnings) > 0 {
+		d.Heading(2, "Warnings")
+		for _, warning := range result.Warnings {
+			d.Item(0, "", warning)
+		}
+		d.EndList()
+	}
+}
+
+// Component adds a component with the controls it implements and the statements it satisfies
+func (d *Document) Component(component Component) {
+	d.Heading(1, component.Name)
+	d.Field("Type", component.Type)
+	if component.Description != "" {
+		d.Paragraph(component.Description)
+	}
+	for i, control := range component.Controls {
+		if i == 0 || control.Program != component.Controls[i-1].Program {
+			if i > 0 {
+				d.EndList()
+			}
+			d.Heading(2, "Controls in "+control.Program)
+		}
+		d.componentControl(ControlLabel(control.ControlID), control)
+	}
+	if len(component.Controls) > 0 {
+		d.EndList()
+	}
+}
+
+// ComponentMappings adds the components that implement a control, with how each implements it
+func (d *Document) ComponentMappings(controlID string, mappings []ComponentMapping) {
+	d.Heading(1, "Components Implementing "+ControlLabel(controlID))
+	if len(mappings) == 0 {
+		d.Paragraph("No components are mapped to this control.")
+		return
+	}
+	for _, mapping := range mappings {
+		d.componentControl(mapping.Component+" ("+mapping.Type+")", mapping.ComponentControl)
+	}
+	d.EndList()
+}
+
+// componentControl adds a list item for a component's mapping to a control, with its narrative
+// and the statements it satisfies
+func (d *Document) componentControl(label string, mapping ComponentControl) {
+	d.Item(0, label, mapping.Narrative)
+	for _, statementID := range slices.Sorted(maps.Keys(mapping.Statements)) {
+		d.Item(1, statementID, mapping.Statements[statementID])
+	}
+}
+
+// ComponentImport adds the report of a component definition import
+func (d *Document) ComponentImport(result ComponentImport) {
+	title := "Component Definition Import"
+	if result.DryRun {
+		title += " (dry run)"
+	}
+	d.Heading(1, title)
+	d.Field("Imported", strconv.Itoa(len(result.Components)))
+
+	if len(result.Components) > 0 {
+		d.Heading(2, "Imported Components")
+		for _, component := range result.Components {
+			d.Item(0, component.Name, fmt.Sprintf("%s, %d controls", component.Type, component.Controls))
+		}
+		d.EndList()
+	}
+	if len(result.NotInBaseline) > 0 {
+		d.Heading(2, "Mapped Controls Not in the Program")
+		for _, controlID := range result.NotInBaseline {
+			d.Item(0, "", ControlLabel(controlID))
+		}
+		d.EndList()
+	}
+	if len(result.Warnings) > 0 {
+		d.Heading(2, "Warnings")
+		for _, warning := range result.Warnings {
+			d.Item(0, "", warning)
+		}
+		d.EndList()
+	}
+}
+
+// ComponentDefinitionSummary adds a summary of an OSCAL component definition: its components and
+// the number of controls each implements
+func (d *Document) ComponentDefinitionSummary(definition OSCALComponentDefinition) {
+	content := definition.ComponentDefinition
+	d.Heading(1, content.Metadata.Title)
+	d.Field("OSCAL version", content.Metadata.OSCALVersion)
+	for _, component := range content.Components {
+		var counts []string
+		for _, implementation := range component.ControlImplementations {
+			counts = append(counts, fmt.Sprintf("%d controls of %s", len(implementation.ImplementedRequirements), implementation.Source))
+		}
+		if len(counts) == 0 {
+			counts = append(counts, "no controls")
+		}
+		d.Item(0, component.Title, component.Type+", "+strings.Join(counts, "; "))
+	}
+	d.EndList()
+}
+
+// ResponsibilityDescription describes a responsibility by its role, leveraged authorization and
+// description, e.g. "inherited from AWS GovCloud: Physical access is managed by AWS."
+func ResponsibilityDescription(responsibility Responsibility) string {
+	description := string(responsibility.Role)
+	if responsibility.LeveragedAuthorization != "" {
+		if responsibility.Role == ResponsibilityShared {
+			description += " with "
+		} else {
+			description += " from "
+		}
+		description += responsibility.LeveragedAuthorization
+	}
+	if responsibility.Description != "" {
+		description += ": " + responsibility.Description
+	}
+	return description
+}
+
+// UnassignedParameters adds a list of parameters that have no value, with their choices and
+// constraints
+func (d *Document) UnassignedParameters(params []UnassignedParameter) {
+	for _, param := range params {
+		d.Item(0, ControlLabel(param.ControlID)+" "+param.ID, parameterDescription(param.ControlParameter))
+	}
+	d.EndList()
+}
+
+// GapAnalysis adds a gap analysis: readiness and status counts overall and by family, then the
+// ranked gaps. At most limit gaps are listed if limit is positive.
+func (d *Document) GapAnalysis(analysis GapAnalysis, limit int) {
+	d.Heading(1, "Gap Analysis for "+analysis.System)
+	d.Field("Program", analysis.Program)
+	d.Field("Readiness", fmt.Sprintf("%.1f%% (%d of %d controls addressed)", analysis.Readiness, analysis.Addressed, analysis.Total))
+	for _, status := range analysis.Statuses {
+		if status.Count > 0 {
+			d.Item(0, status.Status, fmt.Sprintf("%d (%.1f%%)", status.Count, status.Percent))
+		}
+	}
+	d.EndList()
+
+	d.Heading(2, "By Family")
+	for _, family := range analysis.Families {
+		var counts []string
+		for _, status := range family.Statuses {
+			if status.Count > 0 {
+				counts = append(counts, fmt.Sprintf("%d %s", status.Count, status.Status))
+			}
+		}
+		d.Item(0, strings.ToUpper(family.ID), fmt.Sprintf("%s: %.1f%% ready (%d of %d) — %s",
+			family.Title, family.Readiness, family.Addressed, family.Total, strings.Join(counts, ", ")))
+	}
+	d.EndList()
+
+	gaps := analysis.Gaps
+	if limit > 0 && len(gaps) > limit {
+		gaps = gaps[:limit]
+	}
+	d.Heading(2, fmt.Sprintf("Gaps by Related-Control Fan-Out (%d of %d)", len(gaps), len(analysis.Gaps)))
+	for _, gap := range gaps {
+		d.Item(0, ControlLabel(gap.ID), fmt.Sprintf("%s — %s, %d related controls", gap.Title, gap.Status, gap.FanOut))
+		if len(gap.UnaddressedStatements) > 0 {
+			d.Item(1, "Unaddressed statements:", strings.Join(gap.UnaddressedStatements, ", "))
+		}
+	}
+	d.EndList()
+}
+
+// NarrativeLint adds the findings of a narrative lint, for the narrative as a whole and then part
+// by part
+func (d *Document) NarrativeLint(lint NarrativeLint) {
+	d.Heading(1, ControlLabel(lint.ControlID)+" Narrative Lint")
+	if lint.System != "" {
+		d.Field("System", lint.System)
+	}
+	d.Field("Program", lint.Program)
+	d.Field("Parts addressed", fmt.Sprintf("%d of %d", lint.Addressed, lint.Total))
+	d.Field("Findings", strconv.Itoa(lint.FindingCount()))
+	if len(lint.Findings) > 0 {
+		for _, finding := range lint.Findings {
+			d.Item(0, string(finding.Kind), finding.Message)
+		}
+		d.EndList()
+	}
+
+	d.Heading(2, "Parts")
+	for _, part := range lint.Parts {
+		d.Item(0, cmp.Or(part.Label, part.StatementID), string(part.Coverage))
+		for _, finding := range part.Findings {
+			d.Item(1, string(finding.Kind), finding.Message)
+		}
+	}
+	d.EndList()
+}
+
+// parameterDescription describes a parameter by its label and either its values or its guidelines
+func parameterDescription(param ControlParameter) string {
+	description := param.Label
+	detail := strings.Join(param.Guidelines, " ")
+	switch {
+	case len(param.Values) > 0:
+		detail = "Value: " + strings.Join(param.Values, ", ")
+	case param.Select != nil:
+		how := "Select one"
+		if param.Select.HowMany == "one-or-more" {
+			how = "Select one or more"
+		}
+		detail = how + ": " + ResolveParameters(strings.Join(param.Select.Choices, "; "), nil)
+	}
+	if len(param.Constraints) > 0 {
+		detail = strings.TrimSpace(detail + " (Constraint: " + strings.Join(param.Constraints, "; ") + ")")
+	}
+	switch {
+	case description == "":
+		return detail
+	case detail == "":
+		return description
+	default:
+		return description + " — " + detail
+	}
+}
+
+// Overlay adds an overlay: the program it tailors, the controls it adds and marks not applicable
+// and the parameter values it sets
+func (d *Document) Overlay(overlay Overlay) {
+	d.Heading(1, overlay.Name)
+	d.Field("Base", overlay.Base)
+	if overlay.Description != "" {
+		d.Paragraph(overlay.Description)
+	}
+
+	if len(overlay.Add) > 0 {
+		d.Heading(2, "Added Controls")
+		for _, addition := range overlay.Add {
+			d.Item(0, ControlLabel(addition.ControlID), "from "+addition.From)
+		}
+		d.EndList()
+	}
+	if len(overlay.NotApplicable) > 0 {
+		d.Heading(2, "Not Applicable")
+		for _, exclusion := range overlay.NotApplicable {
+			d.Item(0, ControlLabel(exclusion.ControlID), exclusion.Justification)
+		}
+		d.EndList()
+	}
+	if len(overlay.Parameters) > 0 {
+		d.Heading(2, "Parameters")
+		for _, parameter := range overlay.Parameters {
+			d.Item(0, parameter.ParameterID, strings.Join(parameter.Values, "; "))
+		}
+		d.EndList()
+	}
+}
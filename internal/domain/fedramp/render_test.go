@@ -0,0 +1,178 @@
+package fedramp
+
+import "testing"
+
+// renderTestControl returns a control with a statement, parameters and guidance
+func renderTestControl() Control {
+	return Control{
+		ID:    "ac-2",
+		Title: "Account Management",
+		Statements: []ControlStatement{{ID: "ac-2_smt", Name: "statement", Parts: []ControlStatement{
+			{ID: "ac-2_smt.a", Name: "item", Label: "a.", Prose: "Define the types of accounts allowed;"},
+			{ID: "ac-2_smt.b", Name: "item", Label: "b.", Prose: "Review accounts {{ insert: param, ac-02_odp.01 }}.", Parts: []ControlStatement{
+				{ID: "ac-2_smt.b.1", Name: "item", Label: "1.", Prose: "Disable unused accounts."},
+			}},
+		}}},
+		Parameters: []ControlParameter{
+			{ID: "ac-02_odp.01", Label: "frequency", Constraints: []string{"at least monthly"}},
+			{ID: "ac-02_odp.02", Select: &ParameterSelection{HowMany: "one-or-more", Choices: []string{"disable", "notify"}}},
+		},
+		Guidance: "Account types include individual and shared.\n\nReview them regularly.",
+	}
+}
+
+func TestDocumentControl(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		fields []string
+		want   string
+	}{
+		{
+			name:   "markdown",
+			format: FormatMarkdown,
+			want: `## AC-2 Account Management
+
+### Statement
+
+- **a.** Define the types of accounts allowed;
+- **b.** Review accounts [Assignment: frequency].
+  - **1.** Disable unused accounts.
+
+### Parameters
+
+- **ac-02_odp.01** frequency — (Constraint: at least monthly)
+- **ac-02_odp.02** Select one or more: disable; notify
+
+### Guidance
+
+Account types include individual and shared.
+
+Review them regularly.
+`,
+		},
+		{
+			name:   "text",
+			format: FormatText,
+			want: `AC-2 Account Management
+-----------------------
+
+Statement:
+
+a. Define the types of accounts allowed;
+b. Review accounts [Assignment: frequency].
+  1. Disable unused accounts.
+
+Parameters:
+
+ac-02_odp.01 frequency — (Constraint: at least monthly)
+ac-02_odp.02 Select one or more: disable; notify
+
+Guidance:
+
+Account types include individual and shared.
+
+Review them regularly.
+`,
+		},
+		{
+			name:   "JSON as markdown, selected fields",
+			format: FormatJSON,
+			fields: []string{"id", "guidance"},
+			want: `## AC-2 Account Management
+
+### Guidance
+
+Account types include individual and shared.
+
+Review them regularly.
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewDocument(tt.format)
+			d.Control(renderTestControl(), 2, tt.fields)
+			if got := d.String(); got != tt.want {
+				t.Errorf("document =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDocumentControlsPage(t *testing.T) {
+	controls := []Control{
+		{ID: "ac-2", Title: "Account Management"},
+		{ID: "ac-2.1", Title: "Automated System Account Management"},
+		{ID: "ac-2.10", Title: "Shared and Group Account Credential Change", Status: "withdrawn"},
+	}
+
+	tests := []struct {
+		name       string
+		format     Format
+		total      int
+		nextCursor string
+		want       string
+	}{
+		{
+			name:       "markdown",
+			format:     FormatMarkdown,
+			total:      5,
+			nextCursor: "Mw",
+			want: `# Controls
+
+- **AC-2** Account Management
+- **AC-2(1)** Automated System Account Management
+- **AC-2(10)** Shared and Group Account Credential Change (withdrawn)
+
+Showing 1–3 of 5. Pass cursor ` + "`Mw`" + ` for more.
+`,
+		},
+		{
+			name:       "text",
+			format:     FormatText,
+			total:      5,
+			nextCursor: "Mw",
+			want: `Controls
+========
+
+AC-2 Account Management
+AC-2(1) Automated System Account Management
+AC-2(10) Shared and Group Account Credential Change (withdrawn)
+
+Showing 1–3 of 5. Pass cursor Mw for more.
+`,
+		},
+		{
+			name:   "last page",
+			format: FormatText,
+			total:  3,
+			want: `Controls
+========
+
+AC-2 Account Management
+AC-2(1) Automated System Account Management
+AC-2(10) Shared and Group Account Credential Change (withdrawn)
+
+Showing 1–3 of 3.
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewDocument(tt.format)
+			d.Heading(1, "Controls")
+			d.Controls(controls, 2, []string{"id", "title"})
+			d.PageSummary(0, len(controls), tt.total, tt.nextCursor)
+			if got := d.String(); got != tt.want {
+				t.Errorf("document =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+
+	d := NewDocument(FormatMarkdown)
+	d.PageSummary(0, 0, 0, "")
+	if got := d.String(); got != "No results.\n" {
+		t.Errorf("empty page = %q, want %q", got, "No results.\n")
+	}
+}
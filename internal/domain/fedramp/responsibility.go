@@ -0,0 +1,274 @@
+package fedramp
+
+import (
+	"encoding/csv"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ResponsibilityRole is who is responsible for implementing a control or statement
+type ResponsibilityRole string
+
+const (
+	ResponsibilityProvider  ResponsibilityRole = "provider"  // The system's own provider implements it
+	ResponsibilityCustomer  ResponsibilityRole = "customer"  // The system's customers implement it
+	ResponsibilityShared    ResponsibilityRole = "shared"    // The provider and a customer or leveraged system share it
+	ResponsibilityInherited ResponsibilityRole = "inherited" // It is inherited from a leveraged authorization
+)
+
+// ResponsibilityRoles lists the names of the responsibility roles
+var ResponsibilityRoles = []string{
+	string(ResponsibilityProvider),
+	string(ResponsibilityCustomer),
+	string(ResponsibilityShared),
+	string(ResponsibilityInherited),
+}
+
+// ParseResponsibilityRole parses a responsibility role in any case
+func ParseResponsibilityRole(s string) (ResponsibilityRole, error) {
+	normalized := strings.ToLower(strings.TrimSpace(s))
+	if slices.Contains(ResponsibilityRoles, normalized) {
+		return ResponsibilityRole(normalized), nil
+	}
+	return "", NewError(ErrInvalidArgument, "unknown responsibility %q (valid responsibilities: %s)", s, strings.Join(ResponsibilityRoles, ", ")).
+		WithSuggestions(ClosestMatches(s, ResponsibilityRoles)...)
+}
+
+// Responsibility records who implements a control or statement
+type Responsibility struct {
+	Role                   ResponsibilityRole `json:"role"`
+	LeveragedAuthorization string             `json:"leveragedAuthorization,omitempty"` // The authorization it is inherited from or shared with, e.g. "AWS GovCloud (US) High P-ATO"
+	Description            string             `json:"description,omitempty"`            // What each party does, e.g. the customer's responsibilities
+}
+
+// Validate checks that an inherited responsibility names the authorization it is inherited from
+func (r Responsibility) Validate() error {
+	if r.Role == ResponsibilityInherited && strings.TrimSpace(r.LeveragedAuthorization) == "" {
+		return NewError(ErrInvalidArgument, "an inherited responsibility must name the leveraged authorization it is inherited from")
+	}
+	return nil
+}
+
+// StatementResponsibility returns the responsibility for a statement, falling back to the
+// responsibility for the control as a whole
+func (r ImplementationRecord) StatementResponsibility(statementID string) *Responsibility {
+	if responsibility, ok := r.StatementResponsibilities[statementID]; ok {
+		return &responsibility
+	}
+	return r.Responsibility
+}
+
+// Inherited reports whether a control is inherited as a whole: either the control is inherited, or
+// every one of its statements is
+func (r ImplementationRecord) Inherited(control Control) bool {
+	statements := control.ImplementationStatements()
+	if len(statements) == 0 {
+		return r.Responsibility != nil && r.Responsibility.Role == ResponsibilityInherited
+	}
+	for _, statement := range statements {
+		responsibility := r.StatementResponsibility(statement.ID)
+		if responsibility == nil || responsibility.Role != ResponsibilityInherited {
+			return false
+		}
+	}
+	return true
+}
+
+// CRM column headings of the FedRAMP customer responsibility matrix (CRM) workbook
+const (
+	crmControlID      = "Control ID"
+	crmInheritable    = "Can Be Inherited from CSP"
+	crmResponsibility = "Specific Inheritance and Customer Agency/CSP Responsibilities"
+)
+
+// crmRowPattern matches the control ID column of a CRM row for a control, e.g. "AC-2" or "AC-02(1)",
+// optionally followed by a statement, e.g. "AC-2 (a)" or "AC-2 a."
+var crmRowPattern = regexp.MustCompile(`(?i)^\s*([a-z]{2}-\d+(?:\s*\(\d+\))?)(?:\s*\(?([a-z])\)?\.?)?\s*$`)
+
+// CRMImport is what a leveraged system's CRM says about the controls of a program
+type CRMImport struct {
+	System                 string                          `json:"system"`
+	Program                string                          `json:"program"`
+	LeveragedAuthorization string                          `json:"leveragedAuthorization"`
+	DryRun                 bool                            `json:"dryRun,omitempty"` // Whether the records were left unchanged
+	Controls               []CRMControl                    `json:"controls"`
+	NotInBaseline          []string                        `json:"notInBaseline,omitempty"` // Rows for controls that are not in the program
+	Warnings               []string                        `json:"warnings,omitempty"`
+	Updates                map[string]ImplementationUpdate `json:"-"` // Updates to the implementation record of each control
+}
+
+// CRMControl summarizes what was imported for a control
+type CRMControl struct {
+	ID             string              `json:"id"`
+	Responsibility *ResponsibilityRole `json:"responsibility,omitempty"` // Responsibility for the control as a whole, if given
+	Statements     int                 `json:"statements"`               // Number of statements with their own responsibility
+}
+
+// ReadCRM reads a leveraged system's customer responsibility matrix in the FedRAMP CRM workbook
+// layout, exported as CSV. Rows the leveraged system marks as inheritable ("Yes") are inherited
+// from its authorization, partially inheritable rows ("Partial") are shared with it, and the
+// others ("No") are the provider's own responsibility. Rows before the heading row, such as the
+// workbook's title, and rows without a value in the inheritance column are skipped.
+func ReadCRM(program Program, r io.Reader, system, leveragedAuthorization string) (CRMImport, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return CRMImport{}, WrapError(ErrInvalidArgument, err, "failed to parse the CRM as CSV")
+	}
+
+	// Find the heading row and the columns
+	heading := slices.IndexFunc(rows, func(row []string) bool {
+		return slices.ContainsFunc(row, func(cell string) bool { return strings.EqualFold(strings.TrimSpace(cell), crmControlID) })
+	})
+	if heading < 0 {
+		return CRMImport{}, NewError(ErrInvalidArgument, "the CRM has no %q column", crmControlID)
+	}
+	column := func(prefix string) int {
+		return slices.IndexFunc(rows[heading], func(cell string) bool {
+			return strings.HasPrefix(strings.ToLower(strings.TrimSpace(cell)), strings.ToLower(prefix))
+		})
+	}
+	idColumn, inheritableColumn, responsibilityColumn := column(crmControlID), column("Can Be Inherited"), column("Specific Inheritance")
+	if inheritableColumn < 0 {
+		return CRMImport{}, NewError(ErrInvalidArgument, "the CRM has no %q column", crmInheritable)
+	}
+	cell := func(row []string, index int) string {
+		if index < 0 || index >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[index])
+	}
+
+	result := CRMImport{
+		System:                 system,
+		Program:                program.Name,
+		LeveragedAuthorization: leveragedAuthorization,
+		Controls:               []CRMControl{},
+		Updates:                make(map[string]ImplementationUpdate),
+	}
+	for i, row := range rows[heading+1:] {
+		id := cell(row, idColumn)
+		if id == "" {
+			continue
+		}
+		line := heading + i + 2
+		match := crmRowPattern.FindStringSubmatch(id)
+		if match == nil {
+			result.Warnings = append(result.Warnings, "row "+strconv.Itoa(line)+": "+id+" is not a control or statement ID")
+			continue
+		}
+		control, ok := program.FindControl(strings.ReplaceAll(match[1], " ", ""))
+		if !ok {
+			if label := ControlLabel(match[1]); !slices.Contains(result.NotInBaseline, label) {
+				result.NotInBaseline = append(result.NotInBaseline, label)
+			}
+			continue
+		}
+
+		responsibility := Responsibility{Description: cell(row, responsibilityColumn), LeveragedAuthorization: leveragedAuthorization}
+		switch strings.ToLower(cell(row, inheritableColumn)) {
+		case "yes", "y":
+			responsibility.Role = ResponsibilityInherited
+		case "partial", "partially", "p":
+			responsibility.Role = ResponsibilityShared
+		case "no", "n":
+			responsibility.Role = ResponsibilityProvider
+			responsibility.LeveragedAuthorization = ""
+		case "":
+			continue // The leveraged system says nothing about the control
+		default:
+			result.Warnings = append(result.Warnings, "row "+strconv.Itoa(line)+": "+id+" has no Yes, No or Partial in "+crmInheritable)
+			continue
+		}
+
+		update := result.Updates[control.ID]
+		if match[2] == "" {
+			update.Responsibility = &responsibility
+		} else {
+			statement, ok := control.FindImplementationStatement(strings.ToLower(match[2]))
+			if !ok {
+				result.Warnings = append(result.Warnings, "row "+strconv.Itoa(line)+": "+id+" is not a statement of "+ControlLabel(control.ID))
+				continue
+			}
+			if update.StatementResponsibilities == nil {
+				update.StatementResponsibilities = make(map[string]Responsibility)
+			}
+			update.StatementResponsibilities[statement.ID] = responsibility
+		}
+		result.Updates[control.ID] = update
+	}
+
+	for _, family := range program.Families {
+		for _, control := range family.Controls {
+			update, ok := result.Updates[control.ID]
+			if !ok {
+				continue
+			}
+			imported := CRMControl{ID: control.ID, Statements: len(update.StatementResponsibilities)}
+			if update.Responsibility != nil {
+				imported.Responsibility = &update.Responsibility.Role
+			}
+			result.Controls = append(result.Controls, imported)
+		}
+	}
+	slices.SortFunc(result.NotInBaseline, CompareControlIDs)
+	return result, nil
+}
+
+// WriteCRM writes the customer responsibility matrix of a system in the FedRAMP CRM workbook
+// layout, with a row for every active control of the program and for each statement with its own
+// responsibility. Controls the system implements itself or inherits can be inherited by its
+// customers ("Yes"), shared controls partially ("Partial") and customer controls not ("No").
+// Controls without a recorded responsibility are left blank.
+func WriteCRM(w io.Writer, program Program, records []ImplementationRecord) error {
+	recordsByControl := make(map[string]ImplementationRecord, len(records))
+	for _, record := range records {
+		recordsByControl[record.ControlID] = record
+	}
+
+	writer := csv.NewWriter(w)
+	writer.Write([]string{crmControlID, crmInheritable, crmResponsibility})
+	row := func(id string, responsibility *Responsibility) {
+		if responsibility == nil {
+			writer.Write([]string{id, "", ""})
+			return
+		}
+		inheritable := "Yes"
+		switch responsibility.Role {
+		case ResponsibilityShared:
+			inheritable = "Partial"
+		case ResponsibilityCustomer:
+			inheritable = "No"
+		}
+		writer.Write([]string{id, inheritable, responsibility.Description})
+	}
+	for _, family := range program.Families {
+		for _, control := range ActiveControls(family.Controls) {
+			record := recordsByControl[control.ID]
+			row(ControlLabel(control.ID), record.Responsibility)
+			for _, statement := range control.ImplementationStatements() {
+				if responsibility, ok := record.StatementResponsibilities[statement.ID]; ok {
+					row(ControlLabel(control.ID)+" ("+statementLetter(statement.ID)+")", &responsibility)
+				}
+			}
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return WrapError(ErrInternal, err, "failed to write the CRM as CSV")
+	}
+	return nil
+}
+
+// statementLetter returns the letter of a statement from its ID, e.g. "a" for "ac-2_smt.a"
+func statementLetter(statementID string) string {
+	_, letter, found := strings.Cut(statementID, "_smt.")
+	if !found {
+		return statementID
+	}
+	return strings.TrimSuffix(letter, ".")
+}
@@ -0,0 +1,144 @@
+package fedramp
+
+import (
+	"bytes"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// crmTestProgram returns a program with a control with statements, an enhancement and a control
+// without statements
+func crmTestProgram() Program {
+	return Program{Name: "FedRAMP Moderate", Families: []ControlFamily{{ID: "ac", Controls: []Control{
+		{ID: "ac-2", Statements: []ControlStatement{{ID: "ac-2_smt", Name: "statement", Parts: []ControlStatement{
+			{ID: "ac-2_smt.a", Name: "item", Label: "a."},
+			{ID: "ac-2_smt.b", Name: "item", Label: "b."},
+		}}}},
+		{ID: "ac-2.1", Statements: []ControlStatement{{ID: "ac-2.1_smt", Name: "statement"}}},
+		{ID: "ac-3", Statements: []ControlStatement{{ID: "ac-3_smt", Name: "statement"}}},
+	}}}}
+}
+
+func TestReadCRM(t *testing.T) {
+	crm := strings.Join([]string{
+		"Customer Responsibility Matrix,,",
+		"Leveraged System,,",
+		",,",
+		"Control ID,Can Be Inherited from CSP,Specific Inheritance and Customer Agency/CSP Responsibilities",
+		"AC-2,Partial,Customers manage their own accounts.",
+		"AC-2 (a),Yes,The CSP defines account types.",
+		"AC-2 b.,No,",
+		"AC-2(1),yes,Automated account management is inherited.",
+		"AC-3,maybe,",
+		"AC-3,,",
+		"AC-2 (z),Yes,",
+		"AC-99(2),Yes,",
+		"not a control,Yes,",
+	}, "\n")
+
+	result, err := ReadCRM(crmTestProgram(), strings.NewReader(crm), "Acme Cloud", "Leveraged P-ATO")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	for _, control := range result.Controls {
+		ids = append(ids, control.ID)
+	}
+	if want := []string{"ac-2", "ac-2.1"}; !slices.Equal(ids, want) {
+		t.Errorf("imported controls = %v, want %v", ids, want)
+	}
+	if want := []string{"AC-99(2)"}; !slices.Equal(result.NotInBaseline, want) {
+		t.Errorf("not in baseline = %v, want %v", result.NotInBaseline, want)
+	}
+	if len(result.Warnings) != 3 {
+		t.Errorf("warnings = %q, want one each for maybe, AC-2 (z) and the row that is not a control", result.Warnings)
+	}
+
+	update := result.Updates["ac-2"]
+	if update.Responsibility == nil || *update.Responsibility != (Responsibility{Role: ResponsibilityShared, LeveragedAuthorization: "Leveraged P-ATO", Description: "Customers manage their own accounts."}) {
+		t.Errorf("ac-2 responsibility = %+v, want shared with the leveraged authorization", update.Responsibility)
+	}
+	if got := update.StatementResponsibilities["ac-2_smt.a"]; got != (Responsibility{Role: ResponsibilityInherited, LeveragedAuthorization: "Leveraged P-ATO", Description: "The CSP defines account types."}) {
+		t.Errorf("ac-2_smt.a responsibility = %+v, want inherited", got)
+	}
+	if got := update.StatementResponsibilities["ac-2_smt.b"]; got != (Responsibility{Role: ResponsibilityProvider}) {
+		t.Errorf("ac-2_smt.b responsibility = %+v, want the provider's without an authorization", got)
+	}
+	if got := result.Updates["ac-2.1"].Responsibility; got == nil || got.Role != ResponsibilityInherited {
+		t.Errorf("ac-2.1 responsibility = %+v, want inherited", got)
+	}
+}
+
+func TestReadCRMInvalid(t *testing.T) {
+	tests := map[string]string{
+		"no control ID column":  "Control,Can Be Inherited from CSP\nAC-2,Yes\n",
+		"no inheritance column": "Control ID,Responsibility\nAC-2,Yes\n",
+		"not CSV":               "Control ID,\"Can Be Inherited\nAC-2,Yes\n",
+	}
+	for name, crm := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ReadCRM(crmTestProgram(), strings.NewReader(crm), "Acme Cloud", "Leveraged P-ATO"); KindOf(err) != ErrInvalidArgument {
+				t.Errorf("ReadCRM() error = %v, want an invalid argument", err)
+			}
+		})
+	}
+}
+
+func TestWriteCRMReadCRM(t *testing.T) {
+	program := crmTestProgram()
+	records := []ImplementationRecord{
+		{
+			ImplementationKey: ImplementationKey{System: "Platform", Program: program.Name, ControlID: "ac-2"},
+			Responsibility:    &Responsibility{Role: ResponsibilityShared, Description: "Customers manage their own accounts."},
+			StatementResponsibilities: map[string]Responsibility{
+				"ac-2_smt.a": {Role: ResponsibilityProvider, Description: "The platform defines account types."},
+				"ac-2_smt.b": {Role: ResponsibilityCustomer, Description: "Customers assign account managers."},
+			},
+		},
+		{
+			ImplementationKey: ImplementationKey{System: "Platform", Program: program.Name, ControlID: "ac-2.1"},
+			Responsibility:    &Responsibility{Role: ResponsibilityInherited, LeveragedAuthorization: "IaaS P-ATO"},
+		},
+	}
+
+	var crm bytes.Buffer
+	if err := WriteCRM(&crm, program, records); err != nil {
+		t.Fatal(err)
+	}
+	result, err := ReadCRM(program, &crm, "Customer System", "Platform P-ATO")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Warnings) != 0 || len(result.NotInBaseline) != 0 {
+		t.Errorf("warnings = %q, not in baseline = %q, want none", result.Warnings, result.NotInBaseline)
+	}
+
+	// What the platform provides, or inherits itself, its customers inherit; what is shared stays
+	// shared; and what the platform leaves to its customers is theirs
+	tests := []struct {
+		controlID, statementID string
+		want                   Responsibility
+	}{
+		{"ac-2", "", Responsibility{Role: ResponsibilityShared, LeveragedAuthorization: "Platform P-ATO", Description: "Customers manage their own accounts."}},
+		{"ac-2", "ac-2_smt.a", Responsibility{Role: ResponsibilityInherited, LeveragedAuthorization: "Platform P-ATO", Description: "The platform defines account types."}},
+		{"ac-2", "ac-2_smt.b", Responsibility{Role: ResponsibilityProvider, Description: "Customers assign account managers."}},
+		{"ac-2.1", "", Responsibility{Role: ResponsibilityInherited, LeveragedAuthorization: "Platform P-ATO"}},
+	}
+	for _, tt := range tests {
+		update := result.Updates[tt.controlID]
+		got := update.Responsibility
+		if tt.statementID != "" {
+			if responsibility, ok := update.StatementResponsibilities[tt.statementID]; ok {
+				got = &responsibility
+			}
+		}
+		if got == nil || *got != tt.want {
+			t.Errorf("%s %s responsibility = %+v, want %+v", tt.controlID, tt.statementID, got, tt.want)
+		}
+	}
+	if _, ok := result.Updates["ac-3"]; ok {
+		t.Error("ac-3, which has no responsibility, was imported")
+	}
+}
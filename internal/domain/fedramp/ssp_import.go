@@ -0,0 +1,315 @@
+package fedramp
+
+import (
+	"cmp"
+	"encoding/json"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// SSPImport is what an OSCAL system security plan says about how a system implements the controls
+// of a program
+type SSPImport struct {
+	System         string                          `json:"system"`
+	Program        string                          `json:"program"`
+	DryRun         bool                            `json:"dryRun,omitempty"`         // Whether the records were left unchanged
+	Controls       []ImportedControl               `json:"controls"`                 // Controls the plan documents
+	Undocumented   []string                        `json:"undocumented,omitempty"`   // Controls the plan lists without a status or narrative
+	MissingFromSSP []ControlSummary                `json:"missingFromSsp,omitempty"` // Controls of the program the plan does not list
+	NotInBaseline  []string                        `json:"notInBaseline,omitempty"`  // Controls the plan lists that are not in the program
+	Warnings       []string                        `json:"warnings,omitempty"`       // Parts of the plan that could not be imported
+	Updates        map[string]ImplementationUpdate `json:"-"`                        // Updates to the implementation record of each documented control
+}
+
+// ImportedControl summarizes what was imported for a control
+type ImportedControl struct {
+	ID         string               `json:"id"`
+	Status     ImplementationStatus `json:"status"`
+	Statements int                  `json:"statements"` // Number of statement narratives
+	Parameters int                  `json:"parameters"` // Number of parameter values
+}
+
+// ParseOSCALSSP parses an OSCAL system security plan in JSON
+func ParseOSCALSSP(data []byte) (OSCALSystemSecurityPlan, error) {
+	var ssp OSCALSystemSecurityPlan
+	if err := json.Unmarshal(data, &ssp); err != nil {
+		return OSCALSystemSecurityPlan{}, WrapError(ErrInvalidArgument, err, "failed to parse the OSCAL system security plan")
+	}
+	if ssp.SystemSecurityPlan.UUID == "" && len(ssp.SystemSecurityPlan.ControlImplementation.ImplementedRequirements) == 0 {
+		return OSCALSystemSecurityPlan{}, NewError(ErrInvalidArgument, "the document is not an OSCAL system security plan (no system-security-plan object)")
+	}
+	return ssp, nil
+}
+
+// ReadSSP reads the implementation of the program's controls from an OSCAL system security plan.
+// Statuses, narratives, parameter values, responsible roles and components are read from each
+// implemented requirement, and parameter values that are not valid for their parameter are
+// reported as warnings. Narratives written by BuildSSP for undocumented controls are ignored,
+// so that a generated plan can be imported again.
+func ReadSSP(program Program, ssp OSCALSystemSecurityPlan, system string) SSPImport {
+	plan := ssp.SystemSecurityPlan
+	if system == "" {
+		system = strings.TrimSpace(plan.SystemCharacteristics.SystemName)
+	}
+	result := SSPImport{
+		System:  system,
+		Program: program.Name,
+		Updates: make(map[string]ImplementationUpdate),
+	}
+
+	roleTitles := make(map[string]string, len(plan.Metadata.Roles))
+	for _, role := range plan.Metadata.Roles {
+		roleTitles[role.ID] = role.Title
+	}
+	componentTitles := make(map[string]string, len(plan.SystemImplementation.Components))
+	leveragedTitles := make(map[string]string)
+	for _, component := range plan.SystemImplementation.Components {
+		switch {
+		case slices.Contains(component.Props, OSCALProperty{Name: "implementation-point", Value: "external"}):
+			leveragedTitles[component.UUID] = component.Title
+		case component.Type != "this-system":
+			componentTitles[component.UUID] = component.Title
+		}
+	}
+
+	listed := make(map[string]bool)
+	for _, requirement := range plan.ControlImplementation.ImplementedRequirements {
+		control, ok := program.FindControl(requirement.ControlID)
+		if !ok {
+			result.NotInBaseline = append(result.NotInBaseline, ControlLabel(requirement.ControlID))
+			continue
+		}
+		listed[control.ID] = true
+
+		update, warnings := readRequirement(control, requirement, roleTitles, componentTitles, leveragedTitles)
+		result.Warnings = append(result.Warnings, warnings...)
+		if update.Status == "" && update.Narrative == nil && len(update.Statements) == 0 && update.Responsibility == nil && len(update.StatementResponsibilities) == 0 {
+			result.Undocumented = append(result.Undocumented, control.ID)
+			continue
+		}
+		if update.Status == "" {
+			update.Status = StatusPlanned
+		}
+		result.Updates[control.ID] = update
+		result.Controls = append(result.Controls, ImportedControl{
+			ID:         control.ID,
+			Status:     update.Status,
+			Statements: len(update.Statements),
+			Parameters: len(update.Parameters),
+		})
+	}
+
+	for _, family := range program.Families {
+		for _, control := range ActiveControls(family.Controls) {
+			if !listed[control.ID] {
+				result.MissingFromSSP = append(result.MissingFromSSP, ControlSummary{ID: control.ID, Title: control.Title})
+			}
+		}
+	}
+	slices.SortFunc(result.NotInBaseline, CompareControlIDs)
+	return result
+}
+
+// readRequirement reads the update to a control's implementation record from an implemented
+// requirement, with warnings for the parts that do not match the control
+func readRequirement(control Control, requirement OSCALImplementedRequirement, roleTitles, componentTitles, leveragedTitles map[string]string) (ImplementationUpdate, []string) {
+	var update ImplementationUpdate
+	var warnings []string
+	var states []string
+	var components []string
+
+	// describe combines the descriptions of the components implementing a statement or control,
+	// prefixing each with its component's title when the components describe it differently
+	describe := func(byComponents []OSCALByComponent) string {
+		var descriptions, labelled []string
+		for _, byComponent := range byComponents {
+			if byComponent.ImplementationStatus != nil && byComponent.ImplementationStatus.State != "" {
+				states = append(states, byComponent.ImplementationStatus.State)
+			}
+			if _, leveraged := leveragedTitles[byComponent.ComponentUUID]; leveraged {
+				continue // Read as a responsibility
+			}
+			title, named := componentTitles[byComponent.ComponentUUID]
+			if named && !slices.Contains(components, title) {
+				components = append(components, title)
+			}
+			description := strings.TrimSpace(byComponent.Description)
+			if description == "" || description == SSPPlaceholderNarrative || slices.Contains(descriptions, description) {
+				continue
+			}
+			descriptions = append(descriptions, description)
+			if named {
+				description = title + ": " + description
+			}
+			labelled = append(labelled, description)
+		}
+		if len(descriptions) > 1 {
+			return strings.Join(labelled, "\n\n")
+		}
+		return strings.Join(descriptions, "")
+	}
+
+	// responsibility reads the responsibility for a statement or control from its control
+	// origination properties and the components of leveraged authorizations that describe it.
+	// Without origination properties, a leveraged component makes it inherited, unless the
+	// responsibility was read for the control as a whole.
+	responsibility := func(props []OSCALProperty, byComponents []OSCALByComponent, inferred bool) *Responsibility {
+		var result Responsibility
+		var origins []string
+		for _, prop := range props {
+			if prop.Name == "control-origination" {
+				origins = append(origins, prop.Value)
+				result.Description = cmp.Or(result.Description, strings.TrimSpace(prop.Remarks))
+			}
+		}
+		for _, byComponent := range byComponents {
+			title, ok := leveragedTitles[byComponent.ComponentUUID]
+			if !ok {
+				continue
+			}
+			result.LeveragedAuthorization = title
+			description := strings.TrimSpace(byComponent.Description)
+			for _, prefix := range []string{"Inherited from " + title + ".", "Shared with " + title + "."} {
+				description = strings.TrimSpace(strings.TrimPrefix(description, prefix))
+			}
+			result.Description = cmp.Or(result.Description, description)
+			if len(origins) == 0 && inferred {
+				origins = append(origins, "inherited")
+			}
+		}
+		result.Role = originationRole(origins)
+		if result.Role == "" {
+			return nil
+		}
+		if result.Role == ResponsibilityProvider || result.Role == ResponsibilityCustomer {
+			result.LeveragedAuthorization = ""
+		}
+		if err := result.Validate(); err != nil {
+			warnings = append(warnings, ControlLabel(control.ID)+": "+err.Error())
+			return nil
+		}
+		return &result
+	}
+
+	for _, prop := range requirement.Props {
+		if prop.Name == "implementation-status" {
+			states = append(states, prop.Value)
+		}
+	}
+	if narrative := describe(requirement.ByComponents); narrative != "" {
+		update.Narrative = &narrative
+	}
+	// The leveraged system of a control with statements is named by the statements' components
+	byComponents := slices.Clone(requirement.ByComponents)
+	for _, implemented := range requirement.Statements {
+		byComponents = append(byComponents, implemented.ByComponents...)
+	}
+	update.Responsibility = responsibility(requirement.Props, byComponents, len(requirement.Statements) == 0)
+
+	// Read the statement narratives. Without a narrative for the control as a whole, the narrative
+	// most statements share is used for it, and only the other statements keep their own.
+	statements := make(map[string]string)
+	counts := make(map[string]int)
+	for _, implemented := range requirement.Statements {
+		narrative := describe(implemented.ByComponents)
+		statementResponsibility := responsibility(implemented.Props, implemented.ByComponents, update.Responsibility == nil)
+		if narrative == "" && statementResponsibility == nil {
+			continue
+		}
+		statement, ok := control.FindImplementationStatement(implemented.StatementID)
+		if !ok {
+			warnings = append(warnings, "statement "+implemented.StatementID+" is not a statement of "+ControlLabel(control.ID))
+			continue
+		}
+		if statementResponsibility != nil {
+			if update.StatementResponsibilities == nil {
+				update.StatementResponsibilities = make(map[string]Responsibility)
+			}
+			update.StatementResponsibilities[statement.ID] = *statementResponsibility
+		}
+		if narrative != "" {
+			statements[statement.ID] = narrative
+			counts[narrative]++
+		}
+	}
+	if update.Narrative == nil {
+		var shared string
+		for _, narrative := range slices.Sorted(maps.Keys(counts)) {
+			if counts[narrative] > 1 && counts[narrative] > counts[shared] {
+				shared = narrative
+			}
+		}
+		if shared != "" {
+			update.Narrative = &shared
+			maps.DeleteFunc(statements, func(_, narrative string) bool { return narrative == shared })
+		}
+	}
+	if len(statements) > 0 {
+		update.Statements = statements
+	}
+
+	// Read the parameter values, responsible roles and overall status
+	for _, setParameter := range requirement.SetParameters {
+		param, ok := control.FindParameter(setParameter.ParamID)
+		if !ok {
+			warnings = append(warnings, "parameter "+setParameter.ParamID+" is not a parameter of "+ControlLabel(control.ID))
+			continue
+		}
+		if slices.Equal(setParameter.Values, param.Values) {
+			continue // The program's own value
+		}
+		values, err := ValidateParameterValues(param, setParameter.Values)
+		if err != nil {
+			warnings = append(warnings, err.Error())
+			continue
+		}
+		if update.Parameters == nil {
+			update.Parameters = make(map[string][]string)
+		}
+		update.Parameters[param.ID] = values
+	}
+	for _, role := range requirement.ResponsibleRoles {
+		title := roleTitles[role.RoleID]
+		if title == "" {
+			title = role.RoleID
+		}
+		update.ResponsibleRoles = append(update.ResponsibleRoles, title)
+	}
+	update.Components = components
+
+	status, err := combineStates(states)
+	if err != nil {
+		warnings = append(warnings, ControlLabel(control.ID)+": "+err.Error())
+	}
+	update.Status = status
+	return update, warnings
+}
+
+// combineStates returns the implementation status of a control from the states of its parts. Parts
+// in different states make the control partially implemented if any part has been implemented,
+// and planned otherwise.
+func combineStates(states []string) (ImplementationStatus, error) {
+	var combined []ImplementationStatus
+	for _, state := range states {
+		status, err := ParseImplementationStatus(state)
+		if err != nil {
+			return "", err
+		}
+		if !slices.Contains(combined, status) {
+			combined = append(combined, status)
+		}
+	}
+	switch {
+	case len(combined) == 0:
+		return "", nil
+	case len(combined) == 1:
+		return combined[0], nil
+	case slices.ContainsFunc(combined, func(status ImplementationStatus) bool {
+		return status == StatusImplemented || status == StatusPartial || status == StatusAlternative
+	}):
+		return StatusPartial, nil
+	default:
+		return StatusPlanned, nil
+	}
+}
@@ -0,0 +1,179 @@
+package fedramp
+
+import (
+	"encoding/json"
+	"maps"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+// sspTestProgram returns a program with a control with statements and parameters, an enhancement
+// with a single statement and a control without any
+func sspTestProgram() Program {
+	return Program{Name: "FedRAMP Moderate", Families: []ControlFamily{{ID: "ac", Controls: []Control{
+		{
+			ID: "ac-2",
+			Statements: []ControlStatement{{ID: "ac-2_smt", Name: "statement", Parts: []ControlStatement{
+				{ID: "ac-2_smt.a", Name: "item", Label: "a."},
+				{ID: "ac-2_smt.b", Name: "item", Label: "b."},
+				{ID: "ac-2_smt.c", Name: "item", Label: "c."},
+			}}},
+			Parameters: []ControlParameter{
+				{ID: "ac-02_odp.01", Values: []string{"annually"}},
+				{ID: "ac-02_odp.02"},
+				{ID: "ac-02_odp.03", Select: &ParameterSelection{Choices: []string{"disable", "notify"}}},
+			},
+		},
+		{ID: "ac-2.1", Statements: []ControlStatement{{ID: "ac-2.1_smt", Name: "statement"}}},
+		{ID: "ac-3", Statements: []ControlStatement{{ID: "ac-3_smt", Name: "statement"}}},
+	}}}}
+}
+
+func TestBuildSSPReadSSP(t *testing.T) {
+	program := sspTestProgram()
+	key := func(controlID string) ImplementationKey {
+		return ImplementationKey{System: "Acme Cloud", Program: program.Name, ControlID: controlID}
+	}
+	records := []ImplementationRecord{
+		{
+			ImplementationKey: key("ac-2"),
+			Status:            StatusImplemented,
+			ResponsibleRoles:  []string{"System Owner"},
+			Narrative:         "Accounts are managed in the directory.",
+			Statements:        map[string]string{"ac-2_smt.b": "Each team names its account managers."},
+			Parameters:        map[string][]string{"ac-02_odp.02": {"30 days"}},
+			Components:        []string{"Directory"},
+			Responsibility:    &Responsibility{Role: ResponsibilityShared, LeveragedAuthorization: "IaaS P-ATO", Description: "The IaaS manages console accounts."},
+			StatementResponsibilities: map[string]Responsibility{
+				"ac-2_smt.c": {Role: ResponsibilityProvider, Description: "Only the provider reviews accounts."},
+			},
+		},
+		{
+			ImplementationKey: key("ac-2.1"),
+			Status:            StatusPlanned,
+			Responsibility:    &Responsibility{Role: ResponsibilityInherited, LeveragedAuthorization: "IaaS P-ATO"},
+		},
+	}
+
+	// Import the plan as written, so that it is read the way a plan from another tool would be
+	data, err := json.Marshal(BuildSSP(program, records, SSPOptions{System: "Acme Cloud"}, time.Now()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ssp, err := ParseOSCALSSP(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := ReadSSP(program, ssp, "")
+
+	if result.System != "Acme Cloud" {
+		t.Errorf("system = %q, want the system name of the plan", result.System)
+	}
+	if len(result.Warnings) != 0 || len(result.NotInBaseline) != 0 || len(result.MissingFromSSP) != 0 {
+		t.Errorf("warnings = %q, not in baseline = %q, missing = %+v, want none", result.Warnings, result.NotInBaseline, result.MissingFromSSP)
+	}
+	if want := []string{"ac-3"}; !slices.Equal(result.Undocumented, want) {
+		t.Errorf("undocumented = %v, want %v", result.Undocumented, want)
+	}
+	if want := []string{"ac-2", "ac-2.1"}; !slices.Equal(slices.Sorted(maps.Keys(result.Updates)), want) {
+		t.Fatalf("updated controls = %v, want %v", slices.Sorted(maps.Keys(result.Updates)), want)
+	}
+
+	// The narrative most statements share is read back as the control's, and only the statement
+	// that differs keeps its own. The program's own parameter values are not recorded.
+	narrative := "Accounts are managed in the directory."
+	want := ImplementationUpdate{
+		Status:           StatusImplemented,
+		ResponsibleRoles: []string{"System Owner"},
+		Narrative:        &narrative,
+		Statements:       map[string]string{"ac-2_smt.b": "Each team names its account managers."},
+		Parameters:       map[string][]string{"ac-02_odp.02": {"30 days"}},
+		Components:       []string{"Directory"},
+		Responsibility:   &Responsibility{Role: ResponsibilityShared, LeveragedAuthorization: "IaaS P-ATO", Description: "The IaaS manages console accounts."},
+		StatementResponsibilities: map[string]Responsibility{
+			"ac-2_smt.c": {Role: ResponsibilityProvider, Description: "Only the provider reviews accounts."},
+		},
+	}
+	if got := result.Updates["ac-2"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("ac-2 update = %+v, want %+v", got, want)
+	}
+
+	// An inherited control without a narrative of its own is described by the leveraged system
+	want = ImplementationUpdate{
+		Status:         StatusPlanned,
+		Responsibility: &Responsibility{Role: ResponsibilityInherited, LeveragedAuthorization: "IaaS P-ATO"},
+	}
+	if got := result.Updates["ac-2.1"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("ac-2.1 update = %+v, want %+v", got, want)
+	}
+}
+
+func TestReadSSP(t *testing.T) {
+	requirement := func(controlID string, byComponents ...OSCALByComponent) OSCALImplementedRequirement {
+		return OSCALImplementedRequirement{ControlID: controlID, ByComponents: byComponents}
+	}
+	implemented := func(state, description string) OSCALByComponent {
+		return OSCALByComponent{ComponentUUID: "this-system", Description: description, ImplementationStatus: &OSCALStatus{State: state}}
+	}
+
+	var ssp OSCALSystemSecurityPlan
+	plan := &ssp.SystemSecurityPlan
+	plan.SystemCharacteristics.SystemName = "Acme Cloud"
+	plan.SystemImplementation.Components = []OSCALComponent{{UUID: "this-system", Type: "this-system", Title: "This System"}}
+	plan.ControlImplementation.ImplementedRequirements = []OSCALImplementedRequirement{
+		// Statements in different states make the control partially implemented
+		{
+			ControlID: "ac-2",
+			Statements: []OSCALImplementedStatement{
+				{StatementID: "ac-2_smt.a", ByComponents: []OSCALByComponent{implemented("implemented", "Accounts are typed.")}},
+				{StatementID: "ac-2_smt.b", ByComponents: []OSCALByComponent{implemented("planned", "Managers will be named.")}},
+				{StatementID: "ac-2_smt.z", ByComponents: []OSCALByComponent{implemented("planned", "Not a statement.")}},
+			},
+			SetParameters: []OSCALSetParameter{
+				{ParamID: "ac-2_prm_2", Values: []string{"30 days"}},
+				{ParamID: "ac-02_odp.01", Values: []string{"annually"}},
+				{ParamID: "ac-02_odp.09", Values: []string{"weekly"}},
+				{ParamID: "ac-02_odp.03", Values: []string{"delete"}},
+			},
+		},
+		// A narrative without a status is planned
+		requirement("ac-2.1", OSCALByComponent{ComponentUUID: "this-system", Description: "Accounts will be provisioned automatically."}),
+		requirement("ac-3", OSCALByComponent{ComponentUUID: "this-system", Description: SSPPlaceholderNarrative}),
+		// Real plans list enhancements the way the catalog identifies them
+		requirement("ac-2.3", implemented("implemented", "Inactive accounts are disabled.")),
+		requirement("au-2", implemented("implemented", "Events are logged.")),
+	}
+
+	result := ReadSSP(sspTestProgram(), ssp, "")
+
+	if result.System != "Acme Cloud" {
+		t.Errorf("system = %q, want the system name of the plan", result.System)
+	}
+	if want := []string{"AC-2(3)", "AU-2"}; !slices.Equal(result.NotInBaseline, want) {
+		t.Errorf("not in baseline = %v, want %v", result.NotInBaseline, want)
+	}
+	if want := []string{"ac-3"}; !slices.Equal(result.Undocumented, want) {
+		t.Errorf("undocumented = %v, want %v", result.Undocumented, want)
+	}
+	if len(result.Warnings) != 3 {
+		t.Errorf("warnings = %q, want one each for statement ac-2_smt.z, parameter ac-02_odp.09 and the value of ac-02_odp.03", result.Warnings)
+	}
+
+	want := []ImportedControl{
+		{ID: "ac-2", Status: StatusPartial, Statements: 2, Parameters: 1},
+		{ID: "ac-2.1", Status: StatusPlanned},
+	}
+	if !slices.Equal(result.Controls, want) {
+		t.Errorf("controls = %+v, want %+v", result.Controls, want)
+	}
+	// A parameter set by its earlier ID is recorded by its ODP ID, and one set to the program's
+	// value is not recorded
+	if got, want := result.Updates["ac-2"].Parameters, map[string][]string{"ac-02_odp.02": {"30 days"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ac-2 parameters = %v, want %v", got, want)
+	}
+	if narrative := result.Updates["ac-2.1"].Narrative; narrative == nil || *narrative != "Accounts will be provisioned automatically." {
+		t.Errorf("ac-2.1 narrative = %v, want the narrative of the plan", narrative)
+	}
+}
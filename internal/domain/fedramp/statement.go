@@ -0,0 +1,70 @@
+package fedramp
+
+import "strings"
+
+// StatementText renders a statement tree as labelled text indented by level, the way the control
+// reads in NIST SP 800-53, e.g.
+//
+//	a. Define and document the types of accounts allowed ...;
+//	d. Specify:
+//	  1. Authorized users of the system;
+func StatementText(statements []ControlStatement) string {
+	var text strings.Builder
+	for _, statement := range statements {
+		if prose := strings.TrimSpace(statement.Prose); prose != "" {
+			text.WriteString(prose)
+			text.WriteString("\n")
+		}
+		writeStatementParts(&text, statement.Parts, 0)
+	}
+	return text.String()
+}
+
+// writeStatementParts writes statement parts and their nested parts as labelled, indented lines
+func writeStatementParts(text *strings.Builder, parts []ControlStatement, depth int) {
+	for _, part := range parts {
+		line := strings.TrimSpace(strings.Join(strings.Fields(part.Label+" "+part.Prose), " "))
+		if line != "" {
+			text.WriteString(strings.Repeat("  ", depth))
+			text.WriteString(line)
+			text.WriteString("\n")
+		}
+		writeStatementParts(text, part.Parts, depth+1)
+	}
+}
+
+// ImplementationStatements returns the statements of a control that an SSP describes one by one:
+// the lettered items of the control statement, e.g. ac-2_smt.a, or the statement itself for a
+// control without items
+func (c Control) ImplementationStatements() []ControlStatement {
+	var statements []ControlStatement
+	for _, statement := range c.Statements {
+		for _, part := range statement.Parts {
+			if part.Name == "item" && strings.Contains(part.ID, "_smt") {
+				statements = append(statements, part)
+			}
+		}
+	}
+	if len(statements) == 0 {
+		for _, statement := range c.Statements {
+			if statement.ID != "" {
+				statements = append(statements, statement)
+			}
+		}
+	}
+	return statements
+}
+
+// FindImplementationStatement returns the implementation statement of a control with an ID such as
+// "ac-2_smt.a", or the part of the ID after "_smt.", e.g. "a" or "a."
+func (c Control) FindImplementationStatement(ref string) (ControlStatement, bool) {
+	ref = strings.ToLower(strings.TrimSpace(ref))
+	for _, statement := range c.ImplementationStatements() {
+		id := strings.ToLower(statement.ID)
+		_, suffix, _ := strings.Cut(id, "_smt.")
+		if ref == id || (suffix != "" && strings.TrimSuffix(ref, ".") == suffix) {
+			return statement, true
+		}
+	}
+	return ControlStatement{}, false
+}
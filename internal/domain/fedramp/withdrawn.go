@@ -0,0 +1,91 @@
+package fedramp
+
+import (
+	"strings"
+)
+
+// StatusWithdrawn is the status of a control that is no longer part of the catalog
+const StatusWithdrawn = "withdrawn"
+
+// QueryOptions controls which controls are returned by listings and searches
+type QueryOptions struct {
+	IncludeWithdrawn bool // Whether withdrawn controls are returned
+}
+
+// QueryOption configures QueryOptions
+type QueryOption func(*QueryOptions)
+
+// IncludeWithdrawn returns withdrawn controls from listings and searches, which hide them by default
+func IncludeWithdrawn(include bool) QueryOption {
+	return func(o *QueryOptions) {
+		o.IncludeWithdrawn = include
+	}
+}
+
+// NewQueryOptions applies query options to the defaults
+func NewQueryOptions(options ...QueryOption) QueryOptions {
+	var o QueryOptions
+	for _, option := range options {
+		option(&o)
+	}
+	return o
+}
+
+// IsWithdrawn reports whether the control has been withdrawn from the catalog
+func (c Control) IsWithdrawn() bool {
+	return strings.EqualFold(c.Status, StatusWithdrawn)
+}
+
+// WithdrawnNotice explains where the requirements of a withdrawn control went, or returns an
+// empty string for a control that has not been withdrawn
+func (c Control) WithdrawnNotice() string {
+	if !c.IsWithdrawn() {
+		return ""
+	}
+	label := ControlLabel(c.ID)
+	switch {
+	case len(c.IncorporatedInto) > 0:
+		return label + " has been withdrawn. Its requirements were incorporated into " + joinControlLabels(c.IncorporatedInto) + "."
+	case c.MovedTo != "":
+		return label + " has been withdrawn. Its requirements moved to " + ControlLabel(c.MovedTo) + "."
+	default:
+		return label + " has been withdrawn and its requirements were not moved to another control."
+	}
+}
+
+// ActiveControls returns the controls that have not been withdrawn
+func ActiveControls(controls []Control) []Control {
+	active := make([]Control, 0, len(controls))
+	for _, control := range controls {
+		if !control.IsWithdrawn() {
+			active = append(active, control)
+		}
+	}
+	return active
+}
+
+// ActiveFamily returns a control family without its withdrawn controls
+func ActiveFamily(family ControlFamily) ControlFamily {
+	family.Controls = ActiveControls(family.Controls)
+	return family
+}
+
+// ControlIDFromHref returns the control ID referenced by an OSCAL link, e.g. "ac-2" for "#ac-2".
+// Links to a part of a control, such as "#ac-2_smt.k", reference the control.
+func ControlIDFromHref(href string) string {
+	id := strings.TrimPrefix(strings.TrimSpace(href), "#")
+	id, _, _ = strings.Cut(id, "_")
+	return id
+}
+
+// joinControlLabels lists control IDs by their display labels, e.g. "AC-2, AC-3 and AC-6"
+func joinControlLabels(ids []string) string {
+	labels := make([]string, 0, len(ids))
+	for _, id := range ids {
+		labels = append(labels, ControlLabel(id))
+	}
+	if len(labels) == 1 {
+		return labels[0]
+	}
+	return strings.Join(labels[:len(labels)-1], ", ") + " and " + labels[len(labels)-1]
+}
@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/auth"
+)
+
+// Authenticator defines methods for authenticating callers of the networked MCP server
+type Authenticator interface {
+	// Authenticate validates a bearer token and returns the identity it was issued to
+	Authenticate(ctx context.Context, token string) (auth.Identity, error)
+}
@@ -0,0 +1,20 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
+)
+
+// ComponentRepository defines methods for storing the components that implement controls. Calls
+// return an error instead of reading or writing components once their context is done.
+type ComponentRepository interface {
+	// GetComponent returns the component with a name in any case, or an ErrNotFound error
+	GetComponent(ctx context.Context, name string) (fedramp.Component, error)
+
+	// SaveComponent creates or replaces the component with the component's name
+	SaveComponent(ctx context.Context, component fedramp.Component) error
+
+	// ListComponents returns every component, ordered by name
+	ListComponents(ctx context.Context) ([]fedramp.Component, error)
+}
@@ -0,0 +1,26 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
+)
+
+// ImplementationRepository defines methods for storing how systems implement controls. Calls
+// return an error instead of reading or writing records once their context is done.
+type ImplementationRepository interface {
+	// GetImplementation returns the implementation record for a key, or an ErrNotFound error
+	GetImplementation(ctx context.Context, key fedramp.ImplementationKey) (fedramp.ImplementationRecord, error)
+
+	// UpdateImplementation reads, updates and saves the implementation record for a key as one
+	// operation, so that concurrent updates are not lost. See UpdateImplementations.
+	UpdateImplementation(ctx context.Context, key fedramp.ImplementationKey, update func(record *fedramp.ImplementationRecord) error) (fedramp.ImplementationRecord, error)
+
+	// UpdateImplementations reads, updates and saves the implementation records for distinct keys
+	// as one operation. A key without a record gets a record with only the key set, which is saved
+	// only if update sets its CreatedAt. If update returns an error, no record is saved.
+	UpdateImplementations(ctx context.Context, keys []fedramp.ImplementationKey, update func(records []*fedramp.ImplementationRecord) error) ([]fedramp.ImplementationRecord, error)
+
+	// ListImplementations returns the implementation records selected by a filter, ordered by key
+	ListImplementations(ctx context.Context, filter fedramp.ImplementationFilter) ([]fedramp.ImplementationRecord, error)
+}
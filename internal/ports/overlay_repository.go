@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
+)
+
+// OverlayRepository defines methods for storing the overlays that tailor compliance programs
+type OverlayRepository interface {
+	// GetOverlay returns the overlay with a name in any case, or an ErrNotFound error
+	GetOverlay(name string) (fedramp.Overlay, error)
+
+	// SaveOverlay creates or replaces the overlay with the overlay's name
+	SaveOverlay(overlay fedramp.Overlay) error
+
+	// ListOverlays returns every overlay, ordered by name
+	ListOverlays() ([]fedramp.Overlay, error)
+}
@@ -0,0 +1,8 @@
+package ports
+
+// TemplateRepository defines methods for loading the templates documents are generated from
+type TemplateRepository interface {
+	// GetTemplate returns the text of the template with a name, e.g. "policy" or "ac-policy", or
+	// an ErrNotFound error
+	GetTemplate(name string) (string, error)
+}
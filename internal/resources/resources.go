@@ -5,3 +5,9 @@ import "embed"
 
 //go:embed data/placeholder.json data/fedramp-high.json data/fedramp-moderate.json
 var Data embed.FS
+
+// Templates holds the default templates of the documents generated for control families, e.g.
+// templates/policy.md.tmpl
+//
+//go:embed templates/*.md.tmpl
+var Templates embed.FS
@@ -1,7 +1,6 @@
 package fedramp_compliance_handlers
 
 import (
-	"fmt"
 	"strings"
 
 	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
@@ -21,44 +20,63 @@ func NewControlHandler(complianceRepo ports.ComplianceRepository) *ControlHandle
 }
 
 // HandleGetControl returns a control by ID
-func (h *ControlHandler) HandleGetControl(cmd fedramp.GetControlCommand) (fedramp.Control, bool, error) {
+func (h *ControlHandler) HandleGetControl(cmd fedramp.GetControlCommand) (fedramp.Control, error) {
 	// Load the program
 	program, err := h.complianceRepo.LoadProgram(cmd.Program.Name)
 	if err != nil {
-		return fedramp.Control{}, false, err
+		return fedramp.Control{}, err
 	}
 
-	// Find the control
+	// Find the control, comparing IDs in their canonical form
+	controlID := fedramp.NormalizeControlID(cmd.ControlID)
+	var controlIDs []string
 	for _, family := range program.Families {
 		for _, control := range family.Controls {
-			if equalIgnoreCase(control.ID, cmd.ControlID) {
-				return control, true, nil
+			if fedramp.NormalizeControlID(control.ID) == controlID {
+				return control, nil
 			}
+			controlIDs = append(controlIDs, control.ID)
 		}
 	}
 
-	return fedramp.Control{}, false, nil
+	return fedramp.Control{}, fedramp.NewError(fedramp.ErrNotFound, "control %s not found in %s", fedramp.ControlLabel(cmd.ControlID), program.Name).
+		WithSuggestions(fedramp.ClosestControls(cmd.ControlID, controlIDs)...)
 }
 
-// HandleGetControlFamily returns a control family by ID
-func (h *ControlHandler) HandleGetControlFamily(cmd fedramp.GetControlFamilyCommand) (fedramp.ControlFamily, bool, error) {
+// HandleGetControls returns the controls matched by a list of selectors. The program has already
+// been loaded by the caller, so a batch of controls costs a single program load.
+func (h *ControlHandler) HandleGetControls(cmd fedramp.GetControlsCommand) (fedramp.ControlSelection, error) {
+	return fedramp.SelectControls(cmd.Program, cmd.Selectors, cmd.IncludeWithdrawn), nil
+}
+
+// HandleGetControlFamily returns a control family by ID, without its withdrawn controls unless the
+// command includes them
+func (h *ControlHandler) HandleGetControlFamily(cmd fedramp.GetControlFamilyCommand) (fedramp.ControlFamily, error) {
 	// Load the program
 	program, err := h.complianceRepo.LoadProgram(cmd.Program.Name)
 	if err != nil {
-		return fedramp.ControlFamily{}, false, err
+		return fedramp.ControlFamily{}, err
 	}
 
 	// Find the family
+	familyIDs := make([]string, 0, len(program.Families))
+	familyID := fedramp.NormalizeFamilyID(cmd.FamilyID)
 	for _, family := range program.Families {
-		if equalIgnoreCase(family.ID, cmd.FamilyID) {
-			return family, true, nil
+		if fedramp.NormalizeFamilyID(family.ID) == familyID {
+			if !cmd.IncludeWithdrawn {
+				family = fedramp.ActiveFamily(family)
+			}
+			return family, nil
 		}
+		familyIDs = append(familyIDs, family.ID)
 	}
 
-	return fedramp.ControlFamily{}, false, nil
+	return fedramp.ControlFamily{}, fedramp.NewError(fedramp.ErrNotFound, "control family %s not found in %s", strings.ToUpper(familyID), program.Name).
+		WithSuggestions(fedramp.ClosestMatches(cmd.FamilyID, familyIDs)...)
 }
 
-// HandleListControlFamilies returns a list of all control families
+// HandleListControlFamilies returns a list of all control families, without their withdrawn
+// controls unless the command includes them
 func (h *ControlHandler) HandleListControlFamilies(cmd fedramp.ListControlFamiliesCommand) ([]fedramp.ControlFamily, error) {
 	// Load the program
 	program, err := h.complianceRepo.LoadProgram(cmd.Program.Name)
@@ -66,11 +84,18 @@ func (h *ControlHandler) HandleListControlFamilies(cmd fedramp.ListControlFamili
 		return nil, err
 	}
 
-	return program.Families, nil
+	if cmd.IncludeWithdrawn {
+		return program.Families, nil
+	}
+	families := make([]fedramp.ControlFamily, 0, len(program.Families))
+	for _, family := range program.Families {
+		families = append(families, fedramp.ActiveFamily(family))
+	}
+	return families, nil
 }
 
 // HandleGetControlEvidenceGuidance returns evidence guidance for a control
-func (h *ControlHandler) HandleGetControlEvidenceGuidance(cmd fedramp.GetControlEvidenceGuidanceCommand) (string, bool, error) {
+func (h *ControlHandler) HandleGetControlEvidenceGuidance(cmd fedramp.GetControlEvidenceGuidanceCommand) (fedramp.EvidenceGuidance, error) {
 	// Create a GetControlCommand
 	getControlCmd := fedramp.GetControlCommand{
 		Program:   cmd.Program,
@@ -78,18 +103,10 @@ func (h *ControlHandler) HandleGetControlEvidenceGuidance(cmd fedramp.GetControl
 	}
 
 	// Get the control
-	control, found, err := h.HandleGetControl(getControlCmd)
+	control, err := h.HandleGetControl(getControlCmd)
 	if err != nil {
-		return "", false, err
-	}
-	if !found {
-		return "", false, fmt.Errorf("control not found: %s", cmd.ControlID)
+		return fedramp.EvidenceGuidance{}, err
 	}
 
-	return control.EvidenceGuidance, true, nil
-}
-
-// Helper function to check if two strings are equal, ignoring case
-func equalIgnoreCase(s1, s2 string) bool {
-	return strings.EqualFold(s1, s2)
+	return control.EvidenceGuidance, nil
 }
@@ -0,0 +1,118 @@
+package fedramp_compliance_handlers
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/ports"
+)
+
+// OverlayHandler handles the overlays that tailor compliance programs
+type OverlayHandler struct {
+	complianceRepo ports.ComplianceRepository // Programs overlays tailor and add controls from
+	overlayRepo    ports.OverlayRepository
+}
+
+// NewOverlayHandler creates a new overlay handler. Overlays tailor the programs of the compliance
+// repository, which should not itself include tailored programs.
+func NewOverlayHandler(complianceRepo ports.ComplianceRepository, overlayRepo ports.OverlayRepository) *OverlayHandler {
+	return &OverlayHandler{
+		complianceRepo: complianceRepo,
+		overlayRepo:    overlayRepo,
+	}
+}
+
+// HandleSaveOverlay checks that an overlay applies to its base program and saves it, with the
+// program, control and parameter IDs as they appear in the catalogs
+func (h *OverlayHandler) HandleSaveOverlay(cmd fedramp.SaveOverlayCommand) (fedramp.Overlay, error) {
+	overlay := cmd.Overlay
+	overlay.Name = strings.TrimSpace(overlay.Name)
+	overlay.Description = strings.TrimSpace(overlay.Description)
+
+	// The overlay's name must not hide a published program
+	programs, err := h.complianceRepo.ListPrograms()
+	if err != nil {
+		return fedramp.Overlay{}, err
+	}
+	if slices.ContainsFunc(programs, func(program string) bool { return strings.EqualFold(program, overlay.Name) }) {
+		return fedramp.Overlay{}, fedramp.NewError(fedramp.ErrInvalidArgument, "%s is a published program; give the overlay a name of its own", overlay.Name)
+	}
+
+	// Load the base program and the programs controls are added from
+	loaded := make(map[string]fedramp.Program)
+	load := func(name string) (fedramp.Program, error) {
+		if program, ok := loaded[strings.ToLower(name)]; ok {
+			return program, nil
+		}
+		program, err := h.complianceRepo.LoadProgram(name)
+		if err != nil {
+			return fedramp.Program{}, err
+		}
+		loaded[strings.ToLower(name)] = program
+		return program, nil
+	}
+	base, err := load(overlay.Base)
+	if err != nil {
+		return fedramp.Overlay{}, err
+	}
+	overlay.Base = base.Name
+	sources := []fedramp.Program{base}
+
+	// Normalize the tailoring; a later entry for the same control or parameter replaces an earlier one
+	var additions []fedramp.OverlayAddition
+	for _, addition := range overlay.Add {
+		source, err := load(addition.From)
+		if err != nil {
+			return fedramp.Overlay{}, err
+		}
+		if !slices.ContainsFunc(sources, func(program fedramp.Program) bool { return program.Name == source.Name }) {
+			sources = append(sources, source)
+		}
+		addition.From = source.Name
+		if control, ok := source.FindControl(addition.ControlID); ok {
+			addition.ControlID = control.ID
+		}
+		additions = slices.DeleteFunc(additions, func(existing fedramp.OverlayAddition) bool { return existing.ControlID == addition.ControlID })
+		additions = append(additions, addition)
+	}
+	overlay.Add = additions
+
+	var exclusions []fedramp.OverlayNotApplicable
+	for _, exclusion := range overlay.NotApplicable {
+		exclusion.ControlID = fedramp.NormalizeControlID(exclusion.ControlID)
+		exclusion.Justification = strings.TrimSpace(exclusion.Justification)
+		exclusions = slices.DeleteFunc(exclusions, func(existing fedramp.OverlayNotApplicable) bool { return existing.ControlID == exclusion.ControlID })
+		exclusions = append(exclusions, exclusion)
+	}
+	overlay.NotApplicable = exclusions
+
+	var parameters []fedramp.OverlayParameter
+	for _, parameter := range overlay.Parameters {
+		parameter.ParameterID = strings.ToLower(strings.TrimSpace(parameter.ParameterID))
+		parameters = slices.DeleteFunc(parameters, func(existing fedramp.OverlayParameter) bool { return existing.ParameterID == parameter.ParameterID })
+		parameters = append(parameters, parameter)
+	}
+	overlay.Parameters = parameters
+
+	// Apply the overlay to check it, then keep the values as the program validated them
+	program, err := fedramp.ApplyOverlay(base, overlay, sources)
+	if err != nil {
+		return fedramp.Overlay{}, err
+	}
+	for i, parameter := range overlay.Parameters {
+		if _, param, ok := program.FindParameter(parameter.ParameterID); ok {
+			overlay.Parameters[i] = fedramp.OverlayParameter{ParameterID: param.ID, Values: param.Values}
+		}
+	}
+
+	if err := h.overlayRepo.SaveOverlay(overlay); err != nil {
+		return fedramp.Overlay{}, err
+	}
+	return overlay, nil
+}
+
+// HandleGetOverlay returns an overlay by name
+func (h *OverlayHandler) HandleGetOverlay(cmd fedramp.GetOverlayCommand) (fedramp.Overlay, error) {
+	return h.overlayRepo.GetOverlay(cmd.Name)
+}
@@ -26,3 +26,8 @@ func (h *ProgramHandler) HandleListCompliancePrograms(cmd fedramp.ListCompliance
 func (h *ProgramHandler) HandleGetProgram(cmd fedramp.GetProgramCommand) (fedramp.Program, error) {
 	return h.complianceRepo.LoadProgram(cmd.ProgramName)
 }
+
+// HandleDiffPrograms compares the controls of two loaded programs
+func (h *ProgramHandler) HandleDiffPrograms(cmd fedramp.DiffProgramsCommand) (fedramp.ProgramDiff, error) {
+	return fedramp.DiffPrograms(cmd.From, cmd.To), nil
+}
@@ -19,7 +19,8 @@ func NewSearchHandler(complianceRepo ports.ComplianceRepository) *SearchHandler
 	}
 }
 
-// HandleSearchControls searches for controls by keyword
+// HandleSearchControls searches for controls by keyword. Withdrawn controls are skipped unless
+// the command includes them.
 func (h *SearchHandler) HandleSearchControls(cmd fedramp.SearchControlsCommand) ([]fedramp.Control, error) {
 	// Load the program
 	program, err := h.complianceRepo.LoadProgram(cmd.Program.Name)
@@ -33,6 +34,9 @@ func (h *SearchHandler) HandleSearchControls(cmd fedramp.SearchControlsCommand)
 
 	for _, family := range program.Families {
 		for _, control := range family.Controls {
+			if control.IsWithdrawn() && !cmd.IncludeWithdrawn {
+				continue
+			}
 			if contains(control.SearchIndex, query) {
 				results = append(results, control)
 			}
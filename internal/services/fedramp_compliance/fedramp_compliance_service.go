@@ -1,10 +1,13 @@
 package fedramp_compliance
 
 import (
-	"fmt"
+	"context"
+	"strings"
 
 	"github.com/grafana/hackathon-12-mcp-compliance/internal/adapters"
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/auth"
 	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/ports"
 	"github.com/grafana/hackathon-12-mcp-compliance/internal/services/fedramp_compliance/fedramp_compliance_handlers"
 )
 
@@ -13,128 +16,320 @@ type Service struct {
 	programHandler *fedramp_compliance_handlers.ProgramHandler
 	controlHandler *fedramp_compliance_handlers.ControlHandler
 	searchHandler  *fedramp_compliance_handlers.SearchHandler
+	overlayHandler *fedramp_compliance_handlers.OverlayHandler
+	overlayRepo    ports.OverlayRepository
+	policy         *auth.Policy
+}
+
+// Option configures a Service
+type Option func(*Service)
+
+// WithPolicy restricts each caller to the programs granted to them by an authorization policy.
+// The caller is identified by the identity in the context passed to each method.
+func WithPolicy(policy auth.Policy) Option {
+	return func(s *Service) {
+		s.policy = &policy
+	}
+}
+
+// WithOverlayRepository adds the programs tailored by the overlays in a repository to the
+// published programs, each under the overlay's name. Without one, SaveOverlay and GetOverlay fail.
+func WithOverlayRepository(overlayRepo ports.OverlayRepository) Option {
+	return func(s *Service) {
+		s.overlayRepo = overlayRepo
+	}
 }
 
 // NewService creates a new FedRAMP compliance service
-func NewService() *Service {
-	// Create the compliance repository
-	complianceRepo := adapters.NewEmbeddedComplianceRepository()
+func NewService(options ...Option) *Service {
+	service := &Service{}
+	for _, option := range options {
+		option(service)
+	}
+
+	// Create the compliance repository, with the tailored programs if there are overlays
+	publishedRepo := adapters.NewEmbeddedComplianceRepository()
+	var complianceRepo ports.ComplianceRepository = publishedRepo
+	if service.overlayRepo != nil {
+		complianceRepo = adapters.NewTailoredComplianceRepository(publishedRepo, service.overlayRepo)
+		service.overlayHandler = fedramp_compliance_handlers.NewOverlayHandler(publishedRepo, service.overlayRepo)
+	}
 
 	// Create handlers with the repository
-	programHandler := fedramp_compliance_handlers.NewProgramHandler(complianceRepo)
-	controlHandler := fedramp_compliance_handlers.NewControlHandler(complianceRepo)
-	searchHandler := fedramp_compliance_handlers.NewSearchHandler(complianceRepo)
+	service.programHandler = fedramp_compliance_handlers.NewProgramHandler(complianceRepo)
+	service.controlHandler = fedramp_compliance_handlers.NewControlHandler(complianceRepo)
+	service.searchHandler = fedramp_compliance_handlers.NewSearchHandler(complianceRepo)
+	return service
+}
 
-	return &Service{
-		programHandler: programHandler,
-		controlHandler: controlHandler,
-		searchHandler:  searchHandler,
+// ListCompliancePrograms returns a list of the compliance programs available to the caller
+func (s *Service) ListCompliancePrograms(ctx context.Context) ([]string, error) {
+	// Create command
+	cmd := fedramp.ListComplianceProgramsCommand{}
+
+	// Delegate to program handler
+	programs, err := s.programHandler.HandleListCompliancePrograms(cmd)
+	if err != nil || s.policy == nil {
+		return programs, err
+	}
+
+	// Only list the programs the caller may access
+	allowed := make([]string, 0, len(programs))
+	for _, program := range programs {
+		if err := s.policy.AuthorizeProgram(ctx, program); err == nil {
+			allowed = append(allowed, program)
+		}
+	}
+	return allowed, nil
+}
+
+// GetProgram returns a whole compliance program, e.g. to export it
+func (s *Service) GetProgram(ctx context.Context, programName string) (fedramp.Program, error) {
+	// Validate arguments
+	if programName == "" {
+		return fedramp.Program{}, fedramp.NewError(fedramp.ErrInvalidArgument, "program name cannot be empty")
 	}
+
+	return s.loadProgram(ctx, programName)
 }
 
-// ListCompliancePrograms returns a list of available compliance programs
-func (s *Service) ListCompliancePrograms() ([]string, error) {
+// DiffPrograms compares the controls of two programs: the controls only in each and the controls
+// whose parameter values differ
+func (s *Service) DiffPrograms(ctx context.Context, fromProgram, toProgram string) (fedramp.ProgramDiff, error) {
+	// Validate arguments
+	if fromProgram == "" || toProgram == "" {
+		return fedramp.ProgramDiff{}, fedramp.NewError(fedramp.ErrInvalidArgument, "two program names are required")
+	}
+
+	// Load both programs
+	from, err := s.loadProgram(ctx, fromProgram)
+	if err != nil {
+		return fedramp.ProgramDiff{}, err
+	}
+	to, err := s.loadProgram(ctx, toProgram)
+	if err != nil {
+		return fedramp.ProgramDiff{}, err
+	}
+
 	// Create command
-	cmd := fedramp.ListComplianceProgramsCommand{}
+	cmd := fedramp.DiffProgramsCommand{
+		From: from,
+		To:   to,
+	}
 
 	// Delegate to program handler
-	return s.programHandler.HandleListCompliancePrograms(cmd)
+	return s.programHandler.HandleDiffPrograms(cmd)
+}
+
+// SaveOverlay creates or replaces an overlay that tailors a program. The overlay is checked by
+// applying it to its base program, and the tailored program is available under the overlay's name
+// from then on.
+func (s *Service) SaveOverlay(ctx context.Context, overlay fedramp.Overlay) (fedramp.Overlay, error) {
+	// Validate arguments
+	if s.overlayHandler == nil {
+		return fedramp.Overlay{}, fedramp.NewError(fedramp.ErrInternal, "no overlay store is configured")
+	}
+	overlay.Name = strings.TrimSpace(overlay.Name)
+	if overlay.Name == "" {
+		return fedramp.Overlay{}, fedramp.NewError(fedramp.ErrInvalidArgument, "overlay name cannot be empty")
+	}
+	if strings.TrimSpace(overlay.Base) == "" {
+		return fedramp.Overlay{}, fedramp.NewError(fedramp.ErrInvalidArgument, "base program name cannot be empty")
+	}
+	for i, addition := range overlay.Add {
+		if strings.TrimSpace(addition.From) == "" {
+			return fedramp.Overlay{}, fedramp.NewError(fedramp.ErrInvalidArgument, "the program to add %s from cannot be empty", fedramp.ControlLabel(addition.ControlID))
+		}
+		overlay.Add[i].From = strings.TrimSpace(addition.From)
+	}
+
+	// Check that the caller may access the overlay and every program it draws on
+	if s.policy != nil {
+		for _, program := range append(overlay.OverlayPrograms(), overlay.Name) {
+			if err := s.policy.AuthorizeProgram(ctx, program); err != nil {
+				return fedramp.Overlay{}, err
+			}
+		}
+	}
+
+	// Create command
+	cmd := fedramp.SaveOverlayCommand{
+		Overlay: overlay,
+	}
+
+	// Delegate to overlay handler
+	saved, err := s.overlayHandler.HandleSaveOverlay(cmd)
+	if fedramp.KindOf(err) == fedramp.ErrProgramUnavailable {
+		return fedramp.Overlay{}, s.hideUnauthorizedPrograms(ctx, err)
+	}
+	return saved, err
+}
+
+// GetOverlay returns the overlay that tailors a program into the program with its name
+func (s *Service) GetOverlay(ctx context.Context, name string) (fedramp.Overlay, error) {
+	// Validate arguments
+	if s.overlayHandler == nil {
+		return fedramp.Overlay{}, fedramp.NewError(fedramp.ErrInternal, "no overlay store is configured")
+	}
+	if name == "" {
+		return fedramp.Overlay{}, fedramp.NewError(fedramp.ErrInvalidArgument, "overlay name cannot be empty")
+	}
+	if s.policy != nil {
+		if err := s.policy.AuthorizeProgram(ctx, name); err != nil {
+			return fedramp.Overlay{}, err
+		}
+	}
+
+	// Create command
+	cmd := fedramp.GetOverlayCommand{
+		Name: name,
+	}
+
+	// Delegate to overlay handler, suggesting only the overlays the caller may access
+	overlay, err := s.overlayHandler.HandleGetOverlay(cmd)
+	if err != nil {
+		return fedramp.Overlay{}, s.hideUnauthorizedPrograms(ctx, err)
+	}
+	return overlay, nil
 }
 
 // GetControl returns a control by ID
-func (s *Service) GetControl(programName, controlID string) (fedramp.Control, bool, error) {
+func (s *Service) GetControl(ctx context.Context, programName, controlID string) (fedramp.Control, error) {
 	// Validate arguments
 	if programName == "" {
-		return fedramp.Control{}, false, fmt.Errorf("program name cannot be empty")
+		return fedramp.Control{}, fedramp.NewError(fedramp.ErrInvalidArgument, "program name cannot be empty")
 	}
 	if controlID == "" {
-		return fedramp.Control{}, false, fmt.Errorf("control ID cannot be empty")
+		return fedramp.Control{}, fedramp.NewError(fedramp.ErrInvalidArgument, "control ID cannot be empty")
 	}
 
 	// Load the program
-	program, err := s.loadProgram(programName)
+	program, err := s.loadProgram(ctx, programName)
 	if err != nil {
-		return fedramp.Control{}, false, err
+		return fedramp.Control{}, err
 	}
 
 	// Create command
 	cmd := fedramp.GetControlCommand{
 		Program:   program,
-		ControlID: controlID,
+		ControlID: fedramp.NormalizeControlID(controlID),
 	}
 
 	// Delegate to control handler
 	return s.controlHandler.HandleGetControl(cmd)
 }
 
-// GetControlFamily returns a control family by ID
-func (s *Service) GetControlFamily(programName, familyID string) (fedramp.ControlFamily, bool, error) {
+// GetControls returns the controls matched by a list of control IDs and ranges, such as "AC-2",
+// "AC-2 through AC-6" or "AC-2(*)", along with the IDs and ranges that matched no control. Ranges
+// and wildcards skip withdrawn controls unless fedramp.IncludeWithdrawn is passed.
+func (s *Service) GetControls(ctx context.Context, programName string, controlIDs []string, options ...fedramp.QueryOption) (fedramp.ControlSelection, error) {
 	// Validate arguments
 	if programName == "" {
-		return fedramp.ControlFamily{}, false, fmt.Errorf("program name cannot be empty")
+		return fedramp.ControlSelection{}, fedramp.NewError(fedramp.ErrInvalidArgument, "program name cannot be empty")
+	}
+	if len(controlIDs) == 0 {
+		return fedramp.ControlSelection{}, fedramp.NewError(fedramp.ErrInvalidArgument, "at least one control ID is required")
+	}
+	selectors := make([]fedramp.ControlSelector, 0, len(controlIDs))
+	for _, controlID := range controlIDs {
+		selector, err := fedramp.ParseControlSelector(controlID)
+		if err != nil {
+			return fedramp.ControlSelection{}, err
+		}
+		selectors = append(selectors, selector)
+	}
+
+	// Load the program
+	program, err := s.loadProgram(ctx, programName)
+	if err != nil {
+		return fedramp.ControlSelection{}, err
+	}
+
+	// Create command
+	cmd := fedramp.GetControlsCommand{
+		Program:          program,
+		Selectors:        selectors,
+		IncludeWithdrawn: fedramp.NewQueryOptions(options...).IncludeWithdrawn,
+	}
+
+	// Delegate to control handler
+	return s.controlHandler.HandleGetControls(cmd)
+}
+
+// GetControlFamily returns a control family by ID. Withdrawn controls are left out unless
+// fedramp.IncludeWithdrawn is passed.
+func (s *Service) GetControlFamily(ctx context.Context, programName, familyID string, options ...fedramp.QueryOption) (fedramp.ControlFamily, error) {
+	// Validate arguments
+	if programName == "" {
+		return fedramp.ControlFamily{}, fedramp.NewError(fedramp.ErrInvalidArgument, "program name cannot be empty")
 	}
 	if familyID == "" {
-		return fedramp.ControlFamily{}, false, fmt.Errorf("family ID cannot be empty")
+		return fedramp.ControlFamily{}, fedramp.NewError(fedramp.ErrInvalidArgument, "family ID cannot be empty")
 	}
 
 	// Load the program
-	program, err := s.loadProgram(programName)
+	program, err := s.loadProgram(ctx, programName)
 	if err != nil {
-		return fedramp.ControlFamily{}, false, err
+		return fedramp.ControlFamily{}, err
 	}
 
 	// Create command
 	cmd := fedramp.GetControlFamilyCommand{
-		Program:  program,
-		FamilyID: familyID,
+		Program:          program,
+		FamilyID:         fedramp.NormalizeFamilyID(familyID),
+		IncludeWithdrawn: fedramp.NewQueryOptions(options...).IncludeWithdrawn,
 	}
 
 	// Delegate to control handler
 	return s.controlHandler.HandleGetControlFamily(cmd)
 }
 
-// ListControlFamilies returns a list of all control families
-func (s *Service) ListControlFamilies(programName string) ([]fedramp.ControlFamily, error) {
+// ListControlFamilies returns a list of all control families. Withdrawn controls are left out
+// unless fedramp.IncludeWithdrawn is passed.
+func (s *Service) ListControlFamilies(ctx context.Context, programName string, options ...fedramp.QueryOption) ([]fedramp.ControlFamily, error) {
 	// Validate arguments
 	if programName == "" {
-		return nil, fmt.Errorf("program name cannot be empty")
+		return nil, fedramp.NewError(fedramp.ErrInvalidArgument, "program name cannot be empty")
 	}
 
 	// Load the program
-	program, err := s.loadProgram(programName)
+	program, err := s.loadProgram(ctx, programName)
 	if err != nil {
 		return nil, err
 	}
 
 	// Create command
 	cmd := fedramp.ListControlFamiliesCommand{
-		Program: program,
+		Program:          program,
+		IncludeWithdrawn: fedramp.NewQueryOptions(options...).IncludeWithdrawn,
 	}
 
 	// Delegate to control handler
 	return s.controlHandler.HandleListControlFamilies(cmd)
 }
 
-// SearchControls searches for controls by keyword
-func (s *Service) SearchControls(programName, query string) ([]fedramp.Control, error) {
+// SearchControls searches for controls by keyword. Withdrawn controls are left out unless
+// fedramp.IncludeWithdrawn is passed.
+func (s *Service) SearchControls(ctx context.Context, programName, query string, options ...fedramp.QueryOption) ([]fedramp.Control, error) {
 	// Validate arguments
 	if programName == "" {
-		return nil, fmt.Errorf("program name cannot be empty")
+		return nil, fedramp.NewError(fedramp.ErrInvalidArgument, "program name cannot be empty")
 	}
 	if query == "" {
 		return []fedramp.Control{}, nil
 	}
 
 	// Load the program
-	program, err := s.loadProgram(programName)
+	program, err := s.loadProgram(ctx, programName)
 	if err != nil {
 		return nil, err
 	}
 
 	// Create command
 	cmd := fedramp.SearchControlsCommand{
-		Program: program,
-		Query:   query,
+		Program:          program,
+		Query:            query,
+		IncludeWithdrawn: fedramp.NewQueryOptions(options...).IncludeWithdrawn,
 	}
 
 	// Delegate to search handler
@@ -142,35 +337,59 @@ func (s *Service) SearchControls(programName, query string) ([]fedramp.Control,
 }
 
 // GetControlEvidenceGuidance returns evidence guidance for a control
-func (s *Service) GetControlEvidenceGuidance(programName, controlID string) (string, bool, error) {
+func (s *Service) GetControlEvidenceGuidance(ctx context.Context, programName, controlID string) (fedramp.EvidenceGuidance, error) {
 	// Validate arguments
 	if programName == "" {
-		return "", false, fmt.Errorf("program name cannot be empty")
+		return fedramp.EvidenceGuidance{}, fedramp.NewError(fedramp.ErrInvalidArgument, "program name cannot be empty")
 	}
 	if controlID == "" {
-		return "", false, fmt.Errorf("control ID cannot be empty")
+		return fedramp.EvidenceGuidance{}, fedramp.NewError(fedramp.ErrInvalidArgument, "control ID cannot be empty")
 	}
 
 	// Load the program
-	program, err := s.loadProgram(programName)
+	program, err := s.loadProgram(ctx, programName)
 	if err != nil {
-		return "", false, err
+		return fedramp.EvidenceGuidance{}, err
 	}
 
 	// Create command
 	cmd := fedramp.GetControlEvidenceGuidanceCommand{
 		Program:   program,
-		ControlID: controlID,
+		ControlID: fedramp.NormalizeControlID(controlID),
 	}
 
 	// Delegate to control handler
 	return s.controlHandler.HandleGetControlEvidenceGuidance(cmd)
 }
 
-// Helper method to load a program
-func (s *Service) loadProgram(programName string) (fedramp.Program, error) {
+// Helper method to load a program the caller may access
+func (s *Service) loadProgram(ctx context.Context, programName string) (fedramp.Program, error) {
 	cmd := fedramp.GetProgramCommand{
 		ProgramName: programName,
 	}
-	return s.programHandler.HandleGetProgram(cmd)
+	program, err := s.programHandler.HandleGetProgram(cmd)
+	if fedramp.KindOf(err) == fedramp.ErrProgramUnavailable {
+		return fedramp.Program{}, s.hideUnauthorizedPrograms(ctx, err)
+	}
+	if err != nil {
+		return fedramp.Program{}, err
+	}
+
+	if s.policy != nil {
+		if err := s.policy.AuthorizeProgram(ctx, program.Name); err != nil {
+			return fedramp.Program{}, err
+		}
+	}
+	return program, nil
+}
+
+// hideUnauthorizedPrograms removes the programs the caller may not access from the suggestions of
+// an error about an unknown program, so that the error does not reveal them
+func (s *Service) hideUnauthorizedPrograms(ctx context.Context, err error) error {
+	if s.policy == nil {
+		return err
+	}
+	return fedramp.FilterSuggestions(err, func(program string) bool {
+		return s.policy.AuthorizeProgram(ctx, program) == nil
+	})
 }
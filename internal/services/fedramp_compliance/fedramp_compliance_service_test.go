@@ -0,0 +1,37 @@
+package fedramp_compliance
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/auth"
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
+)
+
+func TestLoadProgramHidesUnauthorizedPrograms(t *testing.T) {
+	policy := auth.Policy{Rules: []auth.Rule{{Subjects: []string{"alice"}, Tools: []string{auth.Wildcard}, Programs: []string{"FedRAMP Moderate"}}}}
+	ctx := auth.WithIdentity(context.Background(), auth.Identity{Subject: "alice"})
+
+	tests := []struct {
+		name    string
+		service *Service
+		want    []string
+	}{
+		{"without a policy", NewService(), []string{"FedRAMP High", "FedRAMP Moderate"}},
+		{"with a policy", NewService(WithPolicy(policy)), []string{"FedRAMP Moderate"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.service.GetProgram(ctx, "FedRAMP Low")
+			var programErr *fedramp.Error
+			if !errors.As(err, &programErr) || programErr.Kind != fedramp.ErrProgramUnavailable {
+				t.Fatalf("GetProgram() error = %v, want an unavailable program", err)
+			}
+			if !slices.Equal(programErr.Suggestions, tt.want) {
+				t.Errorf("GetProgram() suggestions = %v, want %v", programErr.Suggestions, tt.want)
+			}
+		})
+	}
+}
@@ -1,8 +1,6 @@
 package fedramp_data_handlers
 
 import (
-	"strings"
-
 	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
 )
 
@@ -18,11 +16,11 @@ func NewControlHandler() *ControlHandler {
 
 // HandleGetControl returns a control by ID
 func (h *ControlHandler) HandleGetControl(cmd fedramp.GetControlCommand) (fedramp.Control, bool) {
-	controlID := cmd.ControlID
+	controlID := fedramp.NormalizeControlID(cmd.ControlID)
 
 	for _, family := range cmd.Program.Families {
 		for _, control := range family.Controls {
-			if equalIgnoreCase(control.ID, controlID) {
+			if fedramp.NormalizeControlID(control.ID) == controlID {
 				return control, true
 			}
 		}
@@ -33,10 +31,10 @@ func (h *ControlHandler) HandleGetControl(cmd fedramp.GetControlCommand) (fedram
 
 // HandleGetControlFamily returns a control family by ID
 func (h *ControlHandler) HandleGetControlFamily(cmd fedramp.GetControlFamilyCommand) (fedramp.ControlFamily, bool) {
-	familyID := cmd.FamilyID
+	familyID := fedramp.NormalizeFamilyID(cmd.FamilyID)
 
 	for _, family := range cmd.Program.Families {
-		if equalIgnoreCase(family.ID, familyID) {
+		if fedramp.NormalizeFamilyID(family.ID) == familyID {
 			return family, true
 		}
 	}
@@ -50,20 +48,15 @@ func (h *ControlHandler) HandleListControlFamilies(cmd fedramp.ListControlFamili
 }
 
 // HandleGetControlEvidenceGuidance returns evidence guidance for a control
-func (h *ControlHandler) HandleGetControlEvidenceGuidance(cmd fedramp.GetControlEvidenceGuidanceCommand) (string, bool) {
+func (h *ControlHandler) HandleGetControlEvidenceGuidance(cmd fedramp.GetControlEvidenceGuidanceCommand) (fedramp.EvidenceGuidance, bool) {
 	getControlCmd := fedramp.GetControlCommand{
 		Program:   cmd.Program,
 		ControlID: cmd.ControlID,
 	}
 	control, found := h.HandleGetControl(getControlCmd)
 	if !found {
-		return "", false
+		return fedramp.EvidenceGuidance{}, false
 	}
 
 	return control.EvidenceGuidance, true
 }
-
-// Helper function to check if two strings are equal, ignoring case
-func equalIgnoreCase(s1, s2 string) bool {
-	return strings.ToLower(s1) == strings.ToLower(s2)
-}
@@ -16,13 +16,17 @@ func NewSearchHandler() *SearchHandler {
 	return &SearchHandler{}
 }
 
-// HandleSearchControls searches for controls by keyword
+// HandleSearchControls searches for controls by keyword. Withdrawn controls are skipped unless
+// the command includes them.
 func (h *SearchHandler) HandleSearchControls(cmd fedramp.SearchControlsCommand) []fedramp.Control {
 	query := cmd.Query
 	var results []fedramp.Control
 
 	for _, family := range cmd.Program.Families {
 		for _, control := range family.Controls {
+			if control.IsWithdrawn() && !cmd.IncludeWithdrawn {
+				continue
+			}
 			if contains(control.SearchIndex, query) {
 				results = append(results, control)
 			}
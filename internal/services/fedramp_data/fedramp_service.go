@@ -97,7 +97,7 @@ func (s *Service) GetControl(program fedramp.Program, controlID string) (fedramp
 	// Create command
 	cmd := fedramp.GetControlCommand{
 		Program:   program,
-		ControlID: controlID,
+		ControlID: fedramp.NormalizeControlID(controlID),
 	}
 
 	// Delegate to control handler
@@ -114,7 +114,7 @@ func (s *Service) GetControlFamily(program fedramp.Program, familyID string) (fe
 	// Create command
 	cmd := fedramp.GetControlFamilyCommand{
 		Program:  program,
-		FamilyID: familyID,
+		FamilyID: fedramp.NormalizeFamilyID(familyID),
 	}
 
 	// Delegate to control handler
@@ -133,16 +133,16 @@ func (s *Service) ListControlFamilies(program fedramp.Program) []fedramp.Control
 }
 
 // GetControlEvidenceGuidance returns evidence guidance for a control
-func (s *Service) GetControlEvidenceGuidance(program fedramp.Program, controlID string) (string, bool) {
+func (s *Service) GetControlEvidenceGuidance(program fedramp.Program, controlID string) (fedramp.EvidenceGuidance, bool) {
 	// Validate arguments
 	if controlID == "" {
-		return "", false
+		return fedramp.EvidenceGuidance{}, false
 	}
 
 	// Create command
 	cmd := fedramp.GetControlEvidenceGuidanceCommand{
 		Program:   program,
-		ControlID: controlID,
+		ControlID: fedramp.NormalizeControlID(controlID),
 	}
 
 	// Delegate to control handler
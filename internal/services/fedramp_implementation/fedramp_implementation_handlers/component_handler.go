@@ -0,0 +1,199 @@
+package fedramp_implementation_handlers
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/ports"
+)
+
+// ComponentHandler handles components and the controls they implement
+type ComponentHandler struct {
+	componentRepo ports.ComponentRepository
+	now           func() time.Time
+}
+
+// NewComponentHandler creates a new component handler
+func NewComponentHandler(componentRepo ports.ComponentRepository) *ComponentHandler {
+	return &ComponentHandler{
+		componentRepo: componentRepo,
+		now:           time.Now,
+	}
+}
+
+// HandleSetComponent creates or updates a component. A new component must have a type.
+func (h *ComponentHandler) HandleSetComponent(ctx context.Context, cmd fedramp.SetComponentCommand) (fedramp.Component, error) {
+	name := strings.TrimSpace(cmd.Name)
+	if name == "" {
+		return fedramp.Component{}, fedramp.NewError(fedramp.ErrInvalidArgument, "component name cannot be empty")
+	}
+	var componentType string
+	if cmd.Update.Type != "" {
+		var err error
+		if componentType, err = fedramp.ParseComponentType(cmd.Update.Type); err != nil {
+			return fedramp.Component{}, err
+		}
+	}
+
+	// Start from the existing component, if any
+	now := h.now().UTC()
+	component, err := h.componentRepo.GetComponent(ctx, name)
+	switch {
+	case fedramp.KindOf(err) == fedramp.ErrNotFound:
+		if componentType == "" {
+			return fedramp.Component{}, fedramp.NewError(fedramp.ErrInvalidArgument, "a type is required to create component %q", name)
+		}
+		component = fedramp.Component{Name: name, CreatedAt: now}
+	case err != nil:
+		return fedramp.Component{}, err
+	}
+
+	// Apply the update
+	if componentType != "" {
+		component.Type = componentType
+	}
+	if cmd.Update.Description != nil {
+		component.Description = strings.TrimSpace(*cmd.Update.Description)
+	}
+	component.UpdatedAt = now
+
+	if err := h.componentRepo.SaveComponent(ctx, component); err != nil {
+		return fedramp.Component{}, err
+	}
+	return component, nil
+}
+
+// HandleMapComponent maps a component to a control of the program, with its narratives for the
+// control and the statements it satisfies, replacing any earlier mapping to the control
+func (h *ComponentHandler) HandleMapComponent(ctx context.Context, cmd fedramp.MapComponentCommand) (fedramp.Component, error) {
+	key, err := resolveKey(cmd.Program, fedramp.ImplementationKey{ControlID: cmd.ControlID})
+	if err != nil {
+		return fedramp.Component{}, err
+	}
+	control, _ := cmd.Program.FindControl(key.ControlID)
+	statements, err := resolveStatements(control, cmd.Statements)
+	if err != nil {
+		return fedramp.Component{}, err
+	}
+	component, err := h.componentRepo.GetComponent(ctx, cmd.Component)
+	if err != nil {
+		return fedramp.Component{}, err
+	}
+
+	if cmd.Remove {
+		component.RemoveControl(cmd.Program.Name, control.ID)
+	} else {
+		mapping := fedramp.ComponentControl{Program: cmd.Program.Name, ControlID: control.ID, Narrative: strings.TrimSpace(cmd.Narrative)}
+		if len(statements) > 0 {
+			mapping.Statements = statements
+		}
+		component.SetControl(mapping)
+	}
+	component.UpdatedAt = h.now().UTC()
+
+	if err := h.componentRepo.SaveComponent(ctx, component); err != nil {
+		return fedramp.Component{}, err
+	}
+	return component, nil
+}
+
+// HandleComponentsForControl returns the components that implement a control of the program
+func (h *ComponentHandler) HandleComponentsForControl(ctx context.Context, cmd fedramp.ComponentsForControlCommand) ([]fedramp.ComponentMapping, error) {
+	key, err := resolveKey(cmd.Program, fedramp.ImplementationKey{ControlID: cmd.ControlID})
+	if err != nil {
+		return nil, err
+	}
+	components, err := h.componentRepo.ListComponents(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	mappings := []fedramp.ComponentMapping{}
+	for _, component := range components {
+		if mapping, ok := component.FindControl(cmd.Program.Name, key.ControlID); ok {
+			mappings = append(mappings, fedramp.ComponentMapping{Component: component.Name, Type: component.Type, ComponentControl: mapping})
+		}
+	}
+	return mappings, nil
+}
+
+// HandleControlsForComponent returns a component with the controls it implements in the
+// requested programs
+func (h *ComponentHandler) HandleControlsForComponent(ctx context.Context, cmd fedramp.ControlsForComponentCommand) (fedramp.Component, error) {
+	component, err := h.componentRepo.GetComponent(ctx, cmd.Component)
+	if err != nil {
+		return fedramp.Component{}, err
+	}
+	component.Controls = inPrograms(component.Controls, cmd.Programs)
+	return component, nil
+}
+
+// HandleImportComponentDefinition records the components of an OSCAL component definition. A
+// component that already exists keeps its other mappings; the definition's mappings replace its
+// mappings to the same controls.
+func (h *ComponentHandler) HandleImportComponentDefinition(ctx context.Context, cmd fedramp.ImportComponentDefinitionCommand) (fedramp.ComponentImport, error) {
+	result := fedramp.ReadComponentDefinition(cmd.Definition, cmd.Programs, cmd.DefaultProgram)
+	result.DryRun = cmd.DryRun
+	if cmd.DryRun {
+		return result, nil
+	}
+
+	now := h.now().UTC()
+	for _, imported := range result.Imported {
+		component, err := h.componentRepo.GetComponent(ctx, imported.Name)
+		switch {
+		case fedramp.KindOf(err) == fedramp.ErrNotFound:
+			component = fedramp.Component{Name: imported.Name, CreatedAt: now}
+		case err != nil:
+			return fedramp.ComponentImport{}, err
+		}
+		component.Type = imported.Type
+		if imported.Description != "" {
+			component.Description = imported.Description
+		}
+		for _, mapping := range imported.Controls {
+			component.SetControl(mapping)
+		}
+		component.UpdatedAt = now
+		if err := h.componentRepo.SaveComponent(ctx, component); err != nil {
+			return fedramp.ComponentImport{}, fedramp.WrapError(fedramp.KindOf(err), err, "failed to import component %q", imported.Name)
+		}
+	}
+	return result, nil
+}
+
+// HandleExportComponentDefinition generates the OSCAL component definition of components, with
+// their mappings to the controls of the requested programs
+func (h *ComponentHandler) HandleExportComponentDefinition(ctx context.Context, cmd fedramp.ExportComponentDefinitionCommand) (fedramp.OSCALComponentDefinition, error) {
+	components, err := h.componentRepo.ListComponents(ctx)
+	if err != nil {
+		return fedramp.OSCALComponentDefinition{}, err
+	}
+
+	selected := components
+	if len(cmd.Components) > 0 {
+		selected = make([]fedramp.Component, 0, len(cmd.Components))
+		for _, name := range cmd.Components {
+			component, ok := fedramp.FindComponent(components, name)
+			if !ok {
+				return fedramp.OSCALComponentDefinition{}, fedramp.NewError(fedramp.ErrNotFound, "component %q not found", name).
+					WithSuggestions(fedramp.ClosestMatches(name, fedramp.ComponentNames(components))...)
+			}
+			selected = append(selected, component)
+		}
+	}
+	for i := range selected {
+		selected[i].Controls = inPrograms(selected[i].Controls, cmd.Programs)
+	}
+	return fedramp.BuildComponentDefinition(selected, h.now()), nil
+}
+
+// inPrograms returns the mappings to the controls of the programs
+func inPrograms(mappings []fedramp.ComponentControl, programs []string) []fedramp.ComponentControl {
+	return slices.DeleteFunc(slices.Clone(mappings), func(mapping fedramp.ComponentControl) bool {
+		return !slices.ContainsFunc(programs, func(program string) bool { return strings.EqualFold(program, mapping.Program) })
+	})
+}
@@ -0,0 +1,140 @@
+package fedramp_implementation_handlers
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/adapters"
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
+)
+
+// componentTestHandler returns a component handler over a store with a component mapped to the
+// controls of two programs and a component without a description
+func componentTestHandler(t *testing.T) *ComponentHandler {
+	t.Helper()
+	repo := adapters.NewJSONComponentRepository(filepath.Join(t.TempDir(), "components.json"))
+	for _, component := range []fedramp.Component{
+		{
+			Name:        "Directory",
+			Type:        "software",
+			Description: "The identity provider.",
+			Controls: []fedramp.ComponentControl{
+				{
+					Program:    "FedRAMP Moderate",
+					ControlID:  "ac-2",
+					Narrative:  "Accounts are managed in the directory.",
+					Statements: map[string]string{"ac-2_smt.c": "", "ac-2_smt.b": "Account managers are assigned in the directory."},
+				},
+				{Program: "FedRAMP High", ControlID: "ac-2", Narrative: "Accounts are managed in the directory."},
+				{Program: "FedRAMP Moderate", ControlID: "ia-2"},
+			},
+		},
+		{Name: "Runbooks", Type: "process-procedure"},
+	} {
+		if err := repo.SaveComponent(context.Background(), component); err != nil {
+			t.Fatal(err)
+		}
+	}
+	handler := NewComponentHandler(repo)
+	handler.now = func() time.Time { return time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC) }
+	return handler
+}
+
+func TestExportComponentDefinition(t *testing.T) {
+	handler := componentTestHandler(t)
+	export := func(cmd fedramp.ExportComponentDefinitionCommand) fedramp.OSCALComponentDefinitionContent {
+		t.Helper()
+		definition, err := handler.HandleExportComponentDefinition(context.Background(), cmd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return definition.ComponentDefinition
+	}
+
+	definition := export(fedramp.ExportComponentDefinitionCommand{Components: []string{"directory"}, Programs: []string{"FedRAMP Moderate"}})
+	if definition.Metadata.Title != "Directory Component Definition" || definition.Metadata.LastModified != "2025-03-01T12:00:00Z" ||
+		definition.Metadata.OSCALVersion != fedramp.OSCALVersion {
+		t.Errorf("metadata = %+v, want the component's title, the time and the OSCAL version", definition.Metadata)
+	}
+	if len(definition.Components) != 1 {
+		t.Fatalf("components = %+v, want the directory only", definition.Components)
+	}
+	component := definition.Components[0]
+	if component.Title != "Directory" || component.Type != "software" || component.Description != "The identity provider." {
+		t.Errorf("component = %+v, want the stored component", component)
+	}
+
+	// Only the requested program's mappings are exported, with its FedRAMP profile as the source
+	if len(component.ControlImplementations) != 1 {
+		t.Fatalf("control implementations = %+v, want FedRAMP Moderate only", component.ControlImplementations)
+	}
+	implementation := component.ControlImplementations[0]
+	if implementation.Source != fedramp.ProgramSource("FedRAMP Moderate") {
+		t.Errorf("source = %q, want the FedRAMP Moderate profile", implementation.Source)
+	}
+	var controlIDs []string
+	for _, requirement := range implementation.ImplementedRequirements {
+		controlIDs = append(controlIDs, requirement.ControlID)
+	}
+	if want := []string{"ac-2", "ia-2"}; !reflect.DeepEqual(controlIDs, want) {
+		t.Fatalf("implemented requirements = %v, want %v", controlIDs, want)
+	}
+
+	// Statements are sorted, and fall back to the control's narrative, then to the placeholder
+	ac2 := implementation.ImplementedRequirements[0]
+	want := []fedramp.OSCALComponentStatement{
+		{StatementID: "ac-2_smt.b", Description: "Account managers are assigned in the directory."},
+		{StatementID: "ac-2_smt.c", Description: "Accounts are managed in the directory."},
+	}
+	if len(ac2.Statements) != len(want) {
+		t.Fatalf("ac-2 statements = %+v, want %+v", ac2.Statements, want)
+	}
+	for i, statement := range ac2.Statements {
+		if statement.StatementID != want[i].StatementID || statement.Description != want[i].Description {
+			t.Errorf("ac-2 statement %d = %+v, want %+v", i, statement, want[i])
+		}
+	}
+	if ia2 := implementation.ImplementedRequirements[1]; ia2.Description != fedramp.SSPPlaceholderNarrative || len(ia2.Statements) != 0 {
+		t.Errorf("ia-2 = %+v, want the placeholder narrative and no statements", ia2)
+	}
+
+	// UUIDs are valid, unique and stable across exports
+	uuids := []string{definition.UUID, component.UUID, implementation.UUID, ac2.UUID, implementation.ImplementedRequirements[1].UUID}
+	for _, statement := range ac2.Statements {
+		uuids = append(uuids, statement.UUID)
+	}
+	seen := map[string]bool{}
+	for _, id := range uuids {
+		if _, err := uuid.Parse(id); err != nil || seen[id] {
+			t.Errorf("uuid %q is not a unique UUID", id)
+		}
+		seen[id] = true
+	}
+	if again := export(fedramp.ExportComponentDefinitionCommand{Components: []string{"Directory"}, Programs: []string{"FedRAMP Moderate"}}); again.UUID != definition.UUID ||
+		again.Components[0].ControlImplementations[0].ImplementedRequirements[0].UUID != ac2.UUID {
+		t.Error("exporting the component again changed its UUIDs")
+	}
+
+	// Every component is exported by default, and components without a description get one
+	all := export(fedramp.ExportComponentDefinitionCommand{Programs: []string{"FedRAMP High"}})
+	if all.Metadata.Title != "Component Definition" || len(all.Components) != 2 {
+		t.Fatalf("definition = %+v, want both components", all)
+	}
+	if runbooks := all.Components[1]; runbooks.Description != "Runbooks." || len(runbooks.ControlImplementations) != 0 {
+		t.Errorf("runbooks = %+v, want a description and no control implementations", runbooks)
+	}
+	if high := all.Components[0].ControlImplementations; len(high) != 1 || high[0].Source != fedramp.ProgramSource("FedRAMP High") {
+		t.Errorf("directory control implementations = %+v, want FedRAMP High only", high)
+	}
+
+	// An unknown component is reported with suggestions
+	_, err := handler.HandleExportComponentDefinition(context.Background(), fedramp.ExportComponentDefinitionCommand{Components: []string{"Directry"}})
+	if fedramp.KindOf(err) != fedramp.ErrNotFound {
+		t.Errorf("error = %v, want not found", err)
+	}
+}
@@ -0,0 +1,31 @@
+package fedramp_implementation_handlers
+
+import (
+	"context"
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/ports"
+)
+
+// GapHandler handles gap analysis operations
+type GapHandler struct {
+	implementationRepo ports.ImplementationRepository
+}
+
+// NewGapHandler creates a new gap analysis handler
+func NewGapHandler(implementationRepo ports.ImplementationRepository) *GapHandler {
+	return &GapHandler{
+		implementationRepo: implementationRepo,
+	}
+}
+
+// HandleGapAnalysis compares the system's implementation records with the program's controls
+func (h *GapHandler) HandleGapAnalysis(ctx context.Context, cmd fedramp.GapAnalysisCommand) (fedramp.GapAnalysis, error) {
+	records, err := h.implementationRepo.ListImplementations(ctx, fedramp.ImplementationFilter{
+		System:  cmd.System,
+		Program: cmd.Program.Name,
+	})
+	if err != nil {
+		return fedramp.GapAnalysis{}, err
+	}
+	return fedramp.BuildGapAnalysis(cmd.Program, records, cmd.System), nil
+}
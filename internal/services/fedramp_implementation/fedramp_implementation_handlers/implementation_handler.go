@@ -0,0 +1,286 @@
+package fedramp_implementation_handlers
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/ports"
+)
+
+// ImplementationHandler handles implementation record operations
+type ImplementationHandler struct {
+	implementationRepo ports.ImplementationRepository
+	now                func() time.Time
+}
+
+// NewImplementationHandler creates a new implementation handler
+func NewImplementationHandler(implementationRepo ports.ImplementationRepository) *ImplementationHandler {
+	return &ImplementationHandler{
+		implementationRepo: implementationRepo,
+		now:                time.Now,
+	}
+}
+
+// HandleGetImplementation returns the implementation record of a control
+func (h *ImplementationHandler) HandleGetImplementation(ctx context.Context, cmd fedramp.GetImplementationCommand) (fedramp.ImplementationRecord, error) {
+	key, err := resolveKey(cmd.Program, cmd.Key)
+	if err != nil {
+		return fedramp.ImplementationRecord{}, err
+	}
+	return h.implementationRepo.GetImplementation(ctx, key)
+}
+
+// HandleSetImplementation creates or updates the implementation record of a control. A new
+// record must have a status.
+func (h *ImplementationHandler) HandleSetImplementation(ctx context.Context, cmd fedramp.SetImplementationCommand) (fedramp.ImplementationRecord, error) {
+	return h.updateImplementation(ctx, cmd.Program, cmd.Key, cmd.Update, true)
+}
+
+// updateImplementation checks an update of the implementation record of a control against the
+// program and applies it. Without requireStatus, a record may be created without a status, and an
+// update that leaves a new record empty creates none.
+func (h *ImplementationHandler) updateImplementation(ctx context.Context, program fedramp.Program, key fedramp.ImplementationKey, update fedramp.ImplementationUpdate, requireStatus bool) (fedramp.ImplementationRecord, error) {
+	records, err := h.updateImplementations(ctx, program, []fedramp.ImplementationKey{key}, []fedramp.ImplementationUpdate{update}, requireStatus)
+	if err != nil {
+		return fedramp.ImplementationRecord{}, err
+	}
+	return records[0], nil
+}
+
+// updateImplementations checks updates of the implementation records of controls against the
+// program and applies them in one repository update, so that either every record is saved or
+// none is. See updateImplementation for requireStatus.
+func (h *ImplementationHandler) updateImplementations(ctx context.Context, program fedramp.Program, keys []fedramp.ImplementationKey, updates []fedramp.ImplementationUpdate, requireStatus bool) ([]fedramp.ImplementationRecord, error) {
+	resolved := make([]fedramp.ImplementationKey, len(keys))
+	for i, key := range keys {
+		var err error
+		if resolved[i], updates[i], err = resolveUpdate(program, key, updates[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	now := h.now().UTC()
+	return h.implementationRepo.UpdateImplementations(ctx, resolved, func(records []*fedramp.ImplementationRecord) error {
+		for i, record := range records {
+			if err := applyUpdate(record, updates[i], now, requireStatus); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// resolveUpdate checks an update against the control of a key, and returns the key and update with
+// the program, control, statement and parameter IDs as they appear in the catalog
+func resolveUpdate(program fedramp.Program, key fedramp.ImplementationKey, update fedramp.ImplementationUpdate) (fedramp.ImplementationKey, fedramp.ImplementationUpdate, error) {
+	key, err := resolveKey(program, key)
+	if err != nil {
+		return fedramp.ImplementationKey{}, fedramp.ImplementationUpdate{}, err
+	}
+	control, _ := program.FindControl(key.ControlID)
+	if update.Statements, err = resolveStatements(control, update.Statements); err != nil {
+		return fedramp.ImplementationKey{}, fedramp.ImplementationUpdate{}, err
+	}
+	if update.Parameters, err = resolveParameters(control, update.Parameters); err != nil {
+		return fedramp.ImplementationKey{}, fedramp.ImplementationUpdate{}, err
+	}
+	if update.StatementResponsibilities, err = resolveResponsibilities(control, update.Responsibility, update.StatementResponsibilities); err != nil {
+		return fedramp.ImplementationKey{}, fedramp.ImplementationUpdate{}, err
+	}
+	return key, update, nil
+}
+
+// applyUpdate applies a resolved update to an implementation record. A new record, which has no
+// CreatedAt yet, gets one unless the update leaves it empty, and must get a status if
+// requireStatus is set.
+func applyUpdate(record *fedramp.ImplementationRecord, update fedramp.ImplementationUpdate, now time.Time, requireStatus bool) error {
+	created := record.CreatedAt.IsZero()
+	if created && requireStatus && update.Status == "" {
+		return fedramp.NewError(fedramp.ErrInvalidArgument, "a status is required to record the implementation of %s", fedramp.ControlLabel(record.ControlID))
+	}
+
+	if update.Status != "" {
+		record.Status = update.Status
+	}
+	if update.ResponsibleRoles != nil {
+		record.ResponsibleRoles = normalizeNames(update.ResponsibleRoles)
+	}
+	if update.Components != nil {
+		record.Components = normalizeNames(update.Components)
+	}
+	if update.Narrative != nil {
+		record.Narrative = strings.TrimSpace(*update.Narrative)
+	}
+	for statementID, narrative := range update.Statements {
+		if narrative == "" {
+			delete(record.Statements, statementID)
+			continue
+		}
+		if record.Statements == nil {
+			record.Statements = make(map[string]string)
+		}
+		record.Statements[statementID] = narrative
+	}
+	if len(record.Statements) == 0 {
+		record.Statements = nil
+	}
+	for paramID, values := range update.Parameters {
+		values = normalizeNames(values)
+		if len(values) == 0 {
+			delete(record.Parameters, paramID)
+			continue
+		}
+		if record.Parameters == nil {
+			record.Parameters = make(map[string][]string)
+		}
+		record.Parameters[paramID] = values
+	}
+	if len(record.Parameters) == 0 {
+		record.Parameters = nil
+	}
+	if update.Responsibility != nil {
+		record.Responsibility = nil
+		if update.Responsibility.Role != "" {
+			record.Responsibility = normalizeResponsibility(*update.Responsibility)
+		}
+	}
+	for statementID, responsibility := range update.StatementResponsibilities {
+		if responsibility.Role == "" {
+			delete(record.StatementResponsibilities, statementID)
+			continue
+		}
+		if record.StatementResponsibilities == nil {
+			record.StatementResponsibilities = make(map[string]fedramp.Responsibility)
+		}
+		record.StatementResponsibilities[statementID] = *normalizeResponsibility(responsibility)
+	}
+	if len(record.StatementResponsibilities) == 0 {
+		record.StatementResponsibilities = nil
+	}
+	if created && record.IsEmpty() {
+		return nil // Nothing to record, so no record is created
+	}
+	if created {
+		record.CreatedAt = now
+	}
+	record.UpdatedAt = now
+	return nil
+}
+
+// HandleListImplementations returns the implementation records selected by a filter
+func (h *ImplementationHandler) HandleListImplementations(ctx context.Context, cmd fedramp.ListImplementationsCommand) ([]fedramp.ImplementationRecord, error) {
+	return h.implementationRepo.ListImplementations(ctx, cmd.Filter)
+}
+
+// resolveKey checks that the key names a control of the program, and returns it with the program
+// and control IDs as they appear in the catalog
+func resolveKey(program fedramp.Program, key fedramp.ImplementationKey) (fedramp.ImplementationKey, error) {
+	control, ok := program.FindControl(key.ControlID)
+	if !ok {
+		var controlIDs []string
+		for _, family := range program.Families {
+			for _, control := range family.Controls {
+				controlIDs = append(controlIDs, control.ID)
+			}
+		}
+		return fedramp.ImplementationKey{}, fedramp.NewError(fedramp.ErrNotFound, "control %s not found in %s", fedramp.ControlLabel(key.ControlID), program.Name).
+			WithSuggestions(fedramp.ClosestControls(key.ControlID, controlIDs)...)
+	}
+	return fedramp.ImplementationKey{System: key.System, Program: program.Name, ControlID: control.ID}, nil
+}
+
+// resolveStatements returns statement narratives keyed by the IDs of the control's statements,
+// accepting references such as "a" for "ac-2_smt.a"
+func resolveStatements(control fedramp.Control, narratives map[string]string) (map[string]string, error) {
+	resolved := make(map[string]string, len(narratives))
+	for ref, narrative := range narratives {
+		statement, ok := control.FindImplementationStatement(ref)
+		if !ok {
+			var statementIDs []string
+			for _, statement := range control.ImplementationStatements() {
+				statementIDs = append(statementIDs, statement.ID)
+			}
+			return nil, fedramp.NewError(fedramp.ErrNotFound, "statement %q not found in %s (valid statements: %s)", ref, fedramp.ControlLabel(control.ID), strings.Join(statementIDs, ", "))
+		}
+		resolved[statement.ID] = strings.TrimSpace(narrative)
+	}
+	return resolved, nil
+}
+
+// resolveResponsibilities validates the responsibilities for a control and its statements, and
+// returns the statement responsibilities keyed by the IDs of the control's statements
+func resolveResponsibilities(control fedramp.Control, responsibility *fedramp.Responsibility, statements map[string]fedramp.Responsibility) (map[string]fedramp.Responsibility, error) {
+	if responsibility != nil {
+		if err := responsibility.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	resolved := make(map[string]fedramp.Responsibility, len(statements))
+	for ref, responsibility := range statements {
+		statement, ok := control.FindImplementationStatement(ref)
+		if !ok {
+			var statementIDs []string
+			for _, statement := range control.ImplementationStatements() {
+				statementIDs = append(statementIDs, statement.ID)
+			}
+			return nil, fedramp.NewError(fedramp.ErrNotFound, "statement %q not found in %s (valid statements: %s)", ref, fedramp.ControlLabel(control.ID), strings.Join(statementIDs, ", "))
+		}
+		if err := responsibility.Validate(); err != nil {
+			return nil, err
+		}
+		resolved[statement.ID] = responsibility
+	}
+	return resolved, nil
+}
+
+// normalizeResponsibility trims the text of a responsibility
+func normalizeResponsibility(responsibility fedramp.Responsibility) *fedramp.Responsibility {
+	responsibility.LeveragedAuthorization = strings.TrimSpace(responsibility.LeveragedAuthorization)
+	responsibility.Description = strings.TrimSpace(responsibility.Description)
+	return &responsibility
+}
+
+// resolveParameters validates parameter values against the choices and constraints of the
+// control's parameters, and returns them keyed by the parameters' IDs, accepting the IDs of
+// earlier catalogs such as "ac-2_prm_1". Empty values clear a parameter and are not validated.
+func resolveParameters(control fedramp.Control, parameters map[string][]string) (map[string][]string, error) {
+	if parameters == nil {
+		return nil, nil
+	}
+	resolved := make(map[string][]string, len(parameters))
+	for paramID, values := range parameters {
+		param, ok := control.FindParameter(paramID)
+		if !ok {
+			var paramIDs []string
+			for _, param := range control.Parameters {
+				paramIDs = append(paramIDs, param.ID)
+			}
+			return nil, fedramp.NewError(fedramp.ErrNotFound, "parameter %q not found in %s", paramID, fedramp.ControlLabel(control.ID)).
+				WithSuggestions(fedramp.ClosestMatches(paramID, paramIDs)...)
+		}
+		values = normalizeNames(values)
+		if len(values) > 0 {
+			var err error
+			if values, err = fedramp.ValidateParameterValues(param, values); err != nil {
+				return nil, err
+			}
+		}
+		resolved[param.ID] = values
+	}
+	return resolved, nil
+}
+
+// normalizeNames trims names such as roles and drops blanks and duplicates, keeping their order
+func normalizeNames(names []string) []string {
+	normalized := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name != "" && !slices.Contains(normalized, name) {
+			normalized = append(normalized, name)
+		}
+	}
+	return normalized
+}
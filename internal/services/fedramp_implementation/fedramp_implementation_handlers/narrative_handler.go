@@ -0,0 +1,56 @@
+package fedramp_implementation_handlers
+
+import (
+	"context"
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/ports"
+)
+
+// NarrativeHandler handles narrative linting operations
+type NarrativeHandler struct {
+	implementationRepo ports.ImplementationRepository
+}
+
+// NewNarrativeHandler creates a new narrative handler
+func NewNarrativeHandler(implementationRepo ports.ImplementationRepository) *NarrativeHandler {
+	return &NarrativeHandler{
+		implementationRepo: implementationRepo,
+	}
+}
+
+// HandleLintNarrative checks a control's narrative against its statement parts. A draft is checked
+// with the parameter values and responsibilities recorded for the system, if any.
+func (h *NarrativeHandler) HandleLintNarrative(ctx context.Context, cmd fedramp.LintNarrativeCommand) (fedramp.NarrativeLint, error) {
+	key, err := resolveKey(cmd.Program, cmd.Key)
+	if err != nil {
+		return fedramp.NarrativeLint{}, err
+	}
+	control, _ := cmd.Program.FindControl(key.ControlID)
+	draft := cmd.Narrative != nil || cmd.Statements != nil
+	statements, err := resolveStatements(control, cmd.Statements)
+	if err != nil {
+		return fedramp.NarrativeLint{}, err
+	}
+
+	// Start from the recorded implementation, if any
+	record := fedramp.ImplementationRecord{ImplementationKey: key}
+	if key.System != "" {
+		record, err = h.implementationRepo.GetImplementation(ctx, key)
+		switch {
+		case fedramp.KindOf(err) == fedramp.ErrNotFound && draft:
+			record = fedramp.ImplementationRecord{ImplementationKey: key}
+		case err != nil:
+			return fedramp.NarrativeLint{}, err
+		}
+	}
+
+	// Check the draft in place of the recorded narratives
+	if draft {
+		record.Narrative = ""
+		if cmd.Narrative != nil {
+			record.Narrative = *cmd.Narrative
+		}
+		record.Statements = statements
+	}
+	return fedramp.LintNarrative(control, record), nil
+}
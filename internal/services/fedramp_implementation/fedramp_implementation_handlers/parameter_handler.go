@@ -0,0 +1,51 @@
+package fedramp_implementation_handlers
+
+import (
+	"context"
+
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/ports"
+)
+
+// ParameterHandler handles organization-defined parameter assignments
+type ParameterHandler struct {
+	implementationRepo    ports.ImplementationRepository
+	implementationHandler *ImplementationHandler
+}
+
+// NewParameterHandler creates a new parameter handler
+func NewParameterHandler(implementationRepo ports.ImplementationRepository) *ParameterHandler {
+	return &ParameterHandler{
+		implementationRepo:    implementationRepo,
+		implementationHandler: NewImplementationHandler(implementationRepo),
+	}
+}
+
+// HandleAssignParameter validates values against the parameter's choices and constraints and
+// records them in the implementation record of the parameter's control. Assigning values records
+// no implementation status, and clearing the values of a control without a record does nothing.
+func (h *ParameterHandler) HandleAssignParameter(ctx context.Context, cmd fedramp.AssignParameterCommand) (fedramp.ImplementationRecord, error) {
+	control, param, ok := cmd.Program.FindParameter(cmd.ParameterID)
+	if !ok {
+		return fedramp.ImplementationRecord{}, fedramp.NewError(fedramp.ErrNotFound, "parameter %q not found in %s", cmd.ParameterID, cmd.Program.Name).
+			WithSuggestions(fedramp.ClosestMatches(cmd.ParameterID, cmd.Program.ParameterIDs())...)
+	}
+
+	// The values are validated with those of any other update to the control
+	key := fedramp.ImplementationKey{System: cmd.System, Program: cmd.Program.Name, ControlID: control.ID}
+	update := fedramp.ImplementationUpdate{Parameters: map[string][]string{param.ID: cmd.Values}}
+	return h.implementationHandler.updateImplementation(ctx, cmd.Program, key, update, false)
+}
+
+// HandleParameterReport reports the parameters of the program's active controls that have no
+// value from either the system or the program
+func (h *ParameterHandler) HandleParameterReport(ctx context.Context, cmd fedramp.ParameterReportCommand) (fedramp.ParameterReport, error) {
+	records, err := h.implementationRepo.ListImplementations(ctx, fedramp.ImplementationFilter{
+		System:  cmd.System,
+		Program: cmd.Program.Name,
+	})
+	if err != nil {
+		return fedramp.ParameterReport{}, err
+	}
+	return fedramp.BuildParameterReport(cmd.Program, records, cmd.System), nil
+}
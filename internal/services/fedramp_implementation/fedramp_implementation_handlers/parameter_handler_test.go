@@ -0,0 +1,78 @@
+package fedramp_implementation_handlers
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/adapters"
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
+)
+
+// parameterTestProgram returns a program with a control with a parameter with choices and a
+// parameter with a constraint
+func parameterTestProgram() fedramp.Program {
+	return fedramp.Program{Name: "FedRAMP Moderate", Families: []fedramp.ControlFamily{{ID: "ac", Controls: []fedramp.Control{{
+		ID: "ac-2",
+		Parameters: []fedramp.ControlParameter{
+			{ID: "ac-02_odp.01", Select: &fedramp.ParameterSelection{Choices: []string{"disable", "notify"}}},
+			{ID: "ac-02_odp.02", Constraints: []string{"at least every 90 days"}},
+		},
+	}}}}}
+}
+
+func TestParameterValidation(t *testing.T) {
+	program := parameterTestProgram()
+	key := fedramp.ImplementationKey{System: "Acme Cloud", Program: program.Name, ControlID: "AC-2"}
+
+	// Values set with an implementation record and assigned on their own are validated alike
+	set := func(handler *ImplementationHandler, _ *ParameterHandler, paramID string, values []string) (fedramp.ImplementationRecord, error) {
+		update := fedramp.ImplementationUpdate{Status: fedramp.StatusImplemented, Parameters: map[string][]string{paramID: values}}
+		return handler.HandleSetImplementation(context.Background(), fedramp.SetImplementationCommand{Program: program, Key: key, Update: update})
+	}
+	assign := func(_ *ImplementationHandler, handler *ParameterHandler, paramID string, values []string) (fedramp.ImplementationRecord, error) {
+		return handler.HandleAssignParameter(context.Background(), fedramp.AssignParameterCommand{Program: program, System: key.System, ParameterID: paramID, Values: values})
+	}
+
+	tests := []struct {
+		name    string
+		paramID string
+		values  []string
+		want    map[string][]string // nil if the values are rejected
+		kind    fedramp.ErrorKind
+	}{
+		{"choice in another case", "ac-02_odp.01", []string{" Notify "}, map[string][]string{"ac-02_odp.01": {"notify"}}, ""},
+		{"not a choice", "ac-02_odp.01", []string{"delete"}, nil, fedramp.ErrInvalidArgument},
+		{"period within the constraint", "ac-02_odp.02", []string{"30 days"}, map[string][]string{"ac-02_odp.02": {"30 days"}}, ""},
+		{"period beyond the constraint", "ac-02_odp.02", []string{"6 months"}, nil, fedramp.ErrInvalidArgument},
+		{"earlier parameter ID", "ac-2_prm_2", []string{"monthly"}, map[string][]string{"ac-02_odp.02": {"monthly"}}, ""},
+		{"unknown parameter", "ac-02_odp.09", []string{"30 days"}, nil, fedramp.ErrNotFound},
+	}
+	for _, path := range []struct {
+		name   string
+		update func(*ImplementationHandler, *ParameterHandler, string, []string) (fedramp.ImplementationRecord, error)
+	}{{"set_implementation", set}, {"assign_parameter", assign}} {
+		for _, tt := range tests {
+			t.Run(path.name+"/"+tt.name, func(t *testing.T) {
+				repo := adapters.NewJSONImplementationRepository(filepath.Join(t.TempDir(), "implementations.json"))
+				record, err := path.update(NewImplementationHandler(repo), NewParameterHandler(repo), tt.paramID, tt.values)
+				if tt.want == nil {
+					if fedramp.KindOf(err) != tt.kind {
+						t.Errorf("error = %v, want %s", err, tt.kind)
+					}
+					if records, _ := repo.ListImplementations(context.Background(), fedramp.ImplementationFilter{}); len(records) != 0 {
+						t.Errorf("records = %+v, want none stored", records)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("returned error: %v", err)
+				}
+				if !reflect.DeepEqual(record.Parameters, tt.want) {
+					t.Errorf("parameters = %v, want %v", record.Parameters, tt.want)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package fedramp_implementation_handlers
+
+import (
+	"context"
+	"strings"
+
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/ports"
+)
+
+// PolicyHandler handles policy and procedure document operations
+type PolicyHandler struct {
+	implementationRepo ports.ImplementationRepository
+	templateRepo       ports.TemplateRepository
+}
+
+// NewPolicyHandler creates a new policy handler
+func NewPolicyHandler(implementationRepo ports.ImplementationRepository, templateRepo ports.TemplateRepository) *PolicyHandler {
+	return &PolicyHandler{
+		implementationRepo: implementationRepo,
+		templateRepo:       templateRepo,
+	}
+}
+
+// HandleGeneratePolicyDocuments generates the policy and procedure documents of control families,
+// in family order. Each document uses the template given in the command, the family's own
+// template (e.g. "ac-policy") or the template for its kind (e.g. "policy"), in that order.
+func (h *PolicyHandler) HandleGeneratePolicyDocuments(ctx context.Context, cmd fedramp.GeneratePolicyDocumentsCommand) ([]fedramp.PolicyDocument, error) {
+	families, err := policyFamilies(cmd.Program, cmd.Families)
+	if err != nil {
+		return nil, err
+	}
+
+	// Use the system's ODP assignments, if any
+	var records []fedramp.ImplementationRecord
+	if cmd.System != "" {
+		records, err = h.implementationRepo.ListImplementations(ctx, fedramp.ImplementationFilter{
+			System:  cmd.System,
+			Program: cmd.Program.Name,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	documents := []fedramp.PolicyDocument{}
+	for _, family := range families {
+		for _, kind := range cmd.Kinds {
+			data, err := fedramp.BuildPolicyTemplateData(cmd.Program, family, records, cmd.System, kind)
+			if err != nil {
+				return nil, err
+			}
+			name, text, err := h.template(family, kind, cmd.Templates)
+			if err != nil {
+				return nil, err
+			}
+			document, err := fedramp.RenderPolicyDocument(data, name, text)
+			if err != nil {
+				return nil, err
+			}
+			documents = append(documents, document)
+		}
+	}
+	return documents, nil
+}
+
+// template returns the name and text of the template for a family's document of a kind
+func (h *PolicyHandler) template(family fedramp.ControlFamily, kind fedramp.PolicyDocumentKind, templates map[fedramp.PolicyDocumentKind]string) (string, string, error) {
+	if text, ok := templates[kind]; ok {
+		return string(kind) + " (given)", text, nil
+	}
+	name := fedramp.NormalizeFamilyID(family.ID) + "-" + string(kind)
+	text, err := h.templateRepo.GetTemplate(name)
+	if fedramp.KindOf(err) == fedramp.ErrNotFound {
+		name = string(kind)
+		text, err = h.templateRepo.GetTemplate(name)
+	}
+	if err != nil {
+		return "", "", err
+	}
+	return name, text, nil
+}
+
+// policyFamilies returns the named control families of a program, or every family with a policy
+// and procedures control if none are named
+func policyFamilies(program fedramp.Program, familyIDs []string) ([]fedramp.ControlFamily, error) {
+	if len(familyIDs) == 0 {
+		var families []fedramp.ControlFamily
+		for _, family := range program.Families {
+			if _, ok := program.FindControl(fedramp.PolicyControlID(family.ID)); ok {
+				families = append(families, family)
+			}
+		}
+		return families, nil
+	}
+
+	var families []fedramp.ControlFamily
+	for _, familyID := range familyIDs {
+		found := false
+		for _, family := range program.Families {
+			if fedramp.NormalizeFamilyID(family.ID) == fedramp.NormalizeFamilyID(familyID) {
+				families = append(families, family)
+				found = true
+				break
+			}
+		}
+		if !found {
+			ids := make([]string, 0, len(program.Families))
+			for _, family := range program.Families {
+				ids = append(ids, family.ID)
+			}
+			return nil, fedramp.NewError(fedramp.ErrNotFound, "control family %s not found in %s", strings.ToUpper(strings.TrimSpace(familyID)), program.Name).
+				WithSuggestions(fedramp.ClosestMatches(familyID, ids)...)
+		}
+	}
+	return families, nil
+}
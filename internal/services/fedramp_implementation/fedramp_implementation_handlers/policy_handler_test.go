@@ -0,0 +1,106 @@
+package fedramp_implementation_handlers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/adapters"
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
+)
+
+// policyTestProgram returns a program with two families that have a policy and procedures control
+// and one that has none
+func policyTestProgram() fedramp.Program {
+	return fedramp.Program{Name: "FedRAMP Moderate", Families: []fedramp.ControlFamily{
+		{ID: "ac", Title: "Access Control", Controls: []fedramp.Control{{ID: "ac-1", Title: "Policy and Procedures"}, {ID: "ac-2", Title: "Account Management"}}},
+		{ID: "at", Title: "Awareness and Training", Controls: []fedramp.Control{{ID: "at-1", Title: "Policy and Procedures"}}},
+		{ID: "pm", Title: "Program Management", Controls: []fedramp.Control{{ID: "pm-2", Title: "Information Security Program Leadership Role"}}},
+	}}
+}
+
+func TestGeneratePolicyDocuments(t *testing.T) {
+	// The template directory overrides the AT policy and every procedure, but not the policy template
+	dir := t.TempDir()
+	for name, text := range map[string]string{
+		"at-policy.md.tmpl": "AT policy for {{.Program}}",
+		"procedure.md.tmpl": "Procedures of {{.FamilyID}}",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(text), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	repo := adapters.NewJSONImplementationRepository(filepath.Join(t.TempDir(), "implementations.json"))
+	program := policyTestProgram()
+
+	tests := []struct {
+		name      string
+		dir       string
+		families  []string
+		kinds     []fedramp.PolicyDocumentKind
+		templates map[fedramp.PolicyDocumentKind]string
+		want      []string // Template and first line of each document
+	}{
+		{
+			name:  "every family with a policy control",
+			dir:   dir,
+			kinds: []fedramp.PolicyDocumentKind{fedramp.PolicyDocumentPolicy, fedramp.PolicyDocumentProcedure},
+			want: []string{
+				"policy: # Access Control Policy",
+				"procedure: Procedures of AC",
+				"at-policy: AT policy for FedRAMP Moderate",
+				"procedure: Procedures of AT",
+			},
+		},
+		{
+			name:     "built-in templates without a directory",
+			families: []string{"AT"},
+			kinds:    []fedramp.PolicyDocumentKind{fedramp.PolicyDocumentPolicy, fedramp.PolicyDocumentProcedure},
+			want:     []string{"policy: # Awareness and Training Policy", "procedure: # Awareness and Training Procedures"},
+		},
+		{
+			name:      "given template",
+			dir:       dir,
+			families:  []string{"at", "ac"},
+			kinds:     []fedramp.PolicyDocumentKind{fedramp.PolicyDocumentPolicy},
+			templates: map[fedramp.PolicyDocumentKind]string{fedramp.PolicyDocumentPolicy: "Given {{.FamilyID}}"},
+			want:      []string{"policy (given): Given AT", "policy (given): Given AC"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewPolicyHandler(repo, adapters.NewFileTemplateRepository(tt.dir))
+			documents, err := handler.HandleGeneratePolicyDocuments(context.Background(), fedramp.GeneratePolicyDocumentsCommand{
+				Program:   program,
+				Families:  tt.families,
+				Kinds:     tt.kinds,
+				Templates: tt.templates,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, document := range documents {
+				firstLine, _, _ := strings.Cut(document.Markdown, "\n")
+				got = append(got, document.Template+": "+firstLine)
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("documents =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+
+	handler := NewPolicyHandler(repo, adapters.NewFileTemplateRepository(dir))
+	for _, families := range [][]string{{"xx"}, {"pm"}} {
+		_, err := handler.HandleGeneratePolicyDocuments(context.Background(), fedramp.GeneratePolicyDocumentsCommand{
+			Program:  program,
+			Families: families,
+			Kinds:    []fedramp.PolicyDocumentKind{fedramp.PolicyDocumentPolicy},
+		})
+		if fedramp.KindOf(err) != fedramp.ErrNotFound {
+			t.Errorf("families %v: error = %v, want not found", families, err)
+		}
+	}
+}
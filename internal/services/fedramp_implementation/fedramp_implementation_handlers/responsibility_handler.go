@@ -0,0 +1,87 @@
+package fedramp_implementation_handlers
+
+import (
+	"bytes"
+	"context"
+	"strings"
+
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/ports"
+)
+
+// ResponsibilityHandler handles control responsibilities and customer responsibility matrices
+type ResponsibilityHandler struct {
+	implementationRepo    ports.ImplementationRepository
+	implementationHandler *ImplementationHandler
+}
+
+// NewResponsibilityHandler creates a new responsibility handler
+func NewResponsibilityHandler(implementationRepo ports.ImplementationRepository) *ResponsibilityHandler {
+	return &ResponsibilityHandler{
+		implementationRepo:    implementationRepo,
+		implementationHandler: NewImplementationHandler(implementationRepo),
+	}
+}
+
+// HandleSetResponsibility records who is responsible for a control or one of its statements. An
+// empty role removes the responsibility.
+func (h *ResponsibilityHandler) HandleSetResponsibility(ctx context.Context, cmd fedramp.SetResponsibilityCommand) (fedramp.ImplementationRecord, error) {
+	var update fedramp.ImplementationUpdate
+	if statementID := strings.TrimSpace(cmd.StatementID); statementID != "" {
+		control, _ := cmd.Program.FindControl(cmd.Key.ControlID)
+		if statement, ok := control.FindImplementationStatement(statementID); ok {
+			statementID = statement.ID
+		}
+		update.StatementResponsibilities = map[string]fedramp.Responsibility{statementID: cmd.Responsibility}
+	} else {
+		update.Responsibility = &cmd.Responsibility
+	}
+	return h.setResponsibilities(ctx, cmd.Program, cmd.Key, update)
+}
+
+// HandleImportCRM records the responsibilities described by a leveraged system's customer
+// responsibility matrix, updating the system's existing records
+func (h *ResponsibilityHandler) HandleImportCRM(ctx context.Context, cmd fedramp.ImportCRMCommand) (fedramp.CRMImport, error) {
+	result, err := fedramp.ReadCRM(cmd.Program, bytes.NewReader(cmd.CRM), cmd.System, cmd.LeveragedAuthorization)
+	if err != nil {
+		return fedramp.CRMImport{}, err
+	}
+	result.DryRun = cmd.DryRun
+	if cmd.DryRun {
+		return result, nil
+	}
+
+	keys := make([]fedramp.ImplementationKey, len(result.Controls))
+	updates := make([]fedramp.ImplementationUpdate, len(result.Controls))
+	for i, imported := range result.Controls {
+		keys[i] = fedramp.ImplementationKey{System: cmd.System, Program: cmd.Program.Name, ControlID: imported.ID}
+		updates[i] = result.Updates[imported.ID]
+	}
+	if _, err := h.implementationHandler.updateImplementations(ctx, cmd.Program, keys, updates, false); err != nil {
+		return fedramp.CRMImport{}, fedramp.WrapError(fedramp.KindOf(err), err, "failed to import the CRM")
+	}
+	return result, nil
+}
+
+// HandleExportCRM writes the customer responsibility matrix of a system as CSV
+func (h *ResponsibilityHandler) HandleExportCRM(ctx context.Context, cmd fedramp.ExportCRMCommand) ([]byte, error) {
+	records, err := h.implementationRepo.ListImplementations(ctx, fedramp.ImplementationFilter{
+		System:  cmd.System,
+		Program: cmd.Program.Name,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := fedramp.WriteCRM(&buf, cmd.Program, records); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// setResponsibilities applies an update of responsibilities to the implementation record of a
+// control. A control without a record gets one without an implementation status, as who is
+// responsible for a control says nothing about how far it is implemented.
+func (h *ResponsibilityHandler) setResponsibilities(ctx context.Context, program fedramp.Program, key fedramp.ImplementationKey, update fedramp.ImplementationUpdate) (fedramp.ImplementationRecord, error) {
+	return h.implementationHandler.updateImplementation(ctx, program, key, update, false)
+}
@@ -0,0 +1,77 @@
+package fedramp_implementation_handlers
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/ports"
+)
+
+// SSPHandler handles system security plan operations
+type SSPHandler struct {
+	implementationRepo    ports.ImplementationRepository
+	componentRepo         ports.ComponentRepository
+	implementationHandler *ImplementationHandler
+	now                   func() time.Time
+}
+
+// NewSSPHandler creates a new system security plan handler. The component repository, which may
+// be nil, provides the narratives of the components that implement controls.
+func NewSSPHandler(implementationRepo ports.ImplementationRepository, componentRepo ports.ComponentRepository) *SSPHandler {
+	return &SSPHandler{
+		implementationRepo:    implementationRepo,
+		componentRepo:         componentRepo,
+		implementationHandler: NewImplementationHandler(implementationRepo),
+		now:                   time.Now,
+	}
+}
+
+// HandleExportSSP generates the OSCAL system security plan of a system from its implementation
+// records for the program
+func (h *SSPHandler) HandleExportSSP(ctx context.Context, cmd fedramp.ExportSSPCommand) (fedramp.OSCALSystemSecurityPlan, error) {
+	cmd.Options.System = strings.TrimSpace(cmd.Options.System)
+	if cmd.Options.System == "" {
+		return fedramp.OSCALSystemSecurityPlan{}, fedramp.NewError(fedramp.ErrInvalidArgument, "system cannot be empty")
+	}
+
+	records, err := h.implementationRepo.ListImplementations(ctx, fedramp.ImplementationFilter{
+		System:  cmd.Options.System,
+		Program: cmd.Program.Name,
+	})
+	if err != nil {
+		return fedramp.OSCALSystemSecurityPlan{}, err
+	}
+	if h.componentRepo != nil {
+		if cmd.Options.Components, err = h.componentRepo.ListComponents(ctx); err != nil {
+			return fedramp.OSCALSystemSecurityPlan{}, err
+		}
+	}
+	return fedramp.BuildSSP(cmd.Program, records, cmd.Options, h.now()), nil
+}
+
+// HandleImportSSP records the implementations described by an OSCAL system security plan,
+// updating the system's existing records, and reports the controls missing from the plan or the
+// program
+func (h *SSPHandler) HandleImportSSP(ctx context.Context, cmd fedramp.ImportSSPCommand) (fedramp.SSPImport, error) {
+	result := fedramp.ReadSSP(cmd.Program, cmd.SSP, strings.TrimSpace(cmd.System))
+	if result.System == "" {
+		return fedramp.SSPImport{}, fedramp.NewError(fedramp.ErrInvalidArgument, "the plan does not name its system; pass the system name")
+	}
+	result.DryRun = cmd.DryRun
+	if cmd.DryRun {
+		return result, nil
+	}
+
+	keys := make([]fedramp.ImplementationKey, len(result.Controls))
+	updates := make([]fedramp.ImplementationUpdate, len(result.Controls))
+	for i, imported := range result.Controls {
+		keys[i] = fedramp.ImplementationKey{System: result.System, Program: cmd.Program.Name, ControlID: imported.ID}
+		updates[i] = result.Updates[imported.ID]
+	}
+	if _, err := h.implementationHandler.updateImplementations(ctx, cmd.Program, keys, updates, true); err != nil {
+		return fedramp.SSPImport{}, fedramp.WrapError(fedramp.KindOf(err), err, "failed to import the plan")
+	}
+	return result, nil
+}
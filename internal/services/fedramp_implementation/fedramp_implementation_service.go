@@ -0,0 +1,764 @@
+package fedramp_implementation
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/adapters"
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/auth"
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/domain/fedramp"
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/ports"
+	"github.com/grafana/hackathon-12-mcp-compliance/internal/services/fedramp_implementation/fedramp_implementation_handlers"
+)
+
+// Service provides methods for tracking how systems implement the controls of compliance programs
+type Service struct {
+	implementationHandler *fedramp_implementation_handlers.ImplementationHandler
+	sspHandler            *fedramp_implementation_handlers.SSPHandler
+	parameterHandler      *fedramp_implementation_handlers.ParameterHandler
+	gapHandler            *fedramp_implementation_handlers.GapHandler
+	narrativeHandler      *fedramp_implementation_handlers.NarrativeHandler
+	policyHandler         *fedramp_implementation_handlers.PolicyHandler
+	responsibilityHandler *fedramp_implementation_handlers.ResponsibilityHandler
+	componentHandler      *fedramp_implementation_handlers.ComponentHandler
+	complianceRepo        ports.ComplianceRepository
+	componentRepo         ports.ComponentRepository
+	overlayRepo           ports.OverlayRepository
+	templateRepo          ports.TemplateRepository
+	policy                *auth.Policy
+}
+
+// Option configures a Service
+type Option func(*Service)
+
+// WithPolicy restricts each caller to the programs granted to them by an authorization policy.
+// The caller is identified by the identity in the context passed to each method.
+func WithPolicy(policy auth.Policy) Option {
+	return func(s *Service) {
+		s.policy = &policy
+	}
+}
+
+// WithComponentRepository keeps the components that implement controls in a repository. Without
+// one, the component methods fail and SSPs are generated without component narratives.
+func WithComponentRepository(componentRepo ports.ComponentRepository) Option {
+	return func(s *Service) {
+		s.componentRepo = componentRepo
+	}
+}
+
+// WithOverlayRepository tracks the implementation of the programs tailored by the overlays in a
+// repository, as well as the published programs
+func WithOverlayRepository(overlayRepo ports.OverlayRepository) Option {
+	return func(s *Service) {
+		s.overlayRepo = overlayRepo
+	}
+}
+
+// WithTemplateRepository reads the templates of policy and procedure documents from a repository
+// instead of using only the default templates
+func WithTemplateRepository(templateRepo ports.TemplateRepository) Option {
+	return func(s *Service) {
+		s.templateRepo = templateRepo
+	}
+}
+
+// NewService creates a new implementation tracking service that keeps its records in a repository
+func NewService(implementationRepo ports.ImplementationRepository, options ...Option) *Service {
+	service := &Service{}
+	for _, option := range options {
+		option(service)
+	}
+	service.complianceRepo = adapters.NewEmbeddedComplianceRepository()
+	if service.overlayRepo != nil {
+		service.complianceRepo = adapters.NewTailoredComplianceRepository(service.complianceRepo, service.overlayRepo)
+	}
+	service.implementationHandler = fedramp_implementation_handlers.NewImplementationHandler(implementationRepo)
+	service.sspHandler = fedramp_implementation_handlers.NewSSPHandler(implementationRepo, service.componentRepo)
+	service.parameterHandler = fedramp_implementation_handlers.NewParameterHandler(implementationRepo)
+	service.gapHandler = fedramp_implementation_handlers.NewGapHandler(implementationRepo)
+	service.narrativeHandler = fedramp_implementation_handlers.NewNarrativeHandler(implementationRepo)
+	if service.templateRepo == nil {
+		service.templateRepo = adapters.NewFileTemplateRepository("")
+	}
+	service.policyHandler = fedramp_implementation_handlers.NewPolicyHandler(implementationRepo, service.templateRepo)
+	service.responsibilityHandler = fedramp_implementation_handlers.NewResponsibilityHandler(implementationRepo)
+	if service.componentRepo != nil {
+		service.componentHandler = fedramp_implementation_handlers.NewComponentHandler(service.componentRepo)
+	}
+	return service
+}
+
+// GetImplementation returns the implementation record of a control for a system
+func (s *Service) GetImplementation(ctx context.Context, system, programName, controlID string) (fedramp.ImplementationRecord, error) {
+	// Validate arguments
+	key, err := validateKey(system, programName, controlID)
+	if err != nil {
+		return fedramp.ImplementationRecord{}, err
+	}
+
+	// Load the program
+	program, err := s.loadProgram(ctx, programName)
+	if err != nil {
+		return fedramp.ImplementationRecord{}, err
+	}
+
+	// Create command
+	cmd := fedramp.GetImplementationCommand{
+		Program: program,
+		Key:     key,
+	}
+
+	// Delegate to implementation handler
+	return s.implementationHandler.HandleGetImplementation(ctx, cmd)
+}
+
+// SetImplementation creates or updates the implementation record of a control for a system
+func (s *Service) SetImplementation(ctx context.Context, system, programName, controlID string, update fedramp.ImplementationUpdate) (fedramp.ImplementationRecord, error) {
+	// Validate arguments
+	key, err := validateKey(system, programName, controlID)
+	if err != nil {
+		return fedramp.ImplementationRecord{}, err
+	}
+
+	// Load the program
+	program, err := s.loadProgram(ctx, programName)
+	if err != nil {
+		return fedramp.ImplementationRecord{}, err
+	}
+
+	// Create command
+	cmd := fedramp.SetImplementationCommand{
+		Program: program,
+		Key:     key,
+		Update:  update,
+	}
+
+	// Delegate to implementation handler
+	return s.implementationHandler.HandleSetImplementation(ctx, cmd)
+}
+
+// ListImplementations returns the implementation records selected by a filter. Without a program
+// in the filter, only records for the programs the caller may access are returned.
+func (s *Service) ListImplementations(ctx context.Context, filter fedramp.ImplementationFilter) ([]fedramp.ImplementationRecord, error) {
+	filter.System = strings.TrimSpace(filter.System)
+	if filter.Program != "" {
+		// Load the program to check access and use its name as recorded
+		program, err := s.loadProgram(ctx, filter.Program)
+		if err != nil {
+			return nil, err
+		}
+		filter.Program = program.Name
+	}
+
+	// Create command
+	cmd := fedramp.ListImplementationsCommand{
+		Filter: filter,
+	}
+
+	// Delegate to implementation handler
+	records, err := s.implementationHandler.HandleListImplementations(ctx, cmd)
+	if err != nil || s.policy == nil || filter.Program != "" {
+		return records, err
+	}
+
+	// Only list the records of programs the caller may access
+	allowed := make([]fedramp.ImplementationRecord, 0, len(records))
+	for _, record := range records {
+		if err := s.policy.AuthorizeProgram(ctx, record.Program); err == nil {
+			allowed = append(allowed, record)
+		}
+	}
+	return allowed, nil
+}
+
+// ExportSSP generates the OSCAL system security plan of a system for a program from the system's
+// implementation records
+func (s *Service) ExportSSP(ctx context.Context, programName string, options fedramp.SSPOptions) (fedramp.OSCALSystemSecurityPlan, error) {
+	// Validate arguments
+	if strings.TrimSpace(options.System) == "" {
+		return fedramp.OSCALSystemSecurityPlan{}, fedramp.NewError(fedramp.ErrInvalidArgument, "system cannot be empty")
+	}
+	if programName == "" {
+		return fedramp.OSCALSystemSecurityPlan{}, fedramp.NewError(fedramp.ErrInvalidArgument, "program name cannot be empty")
+	}
+
+	// Load the program
+	program, err := s.loadProgram(ctx, programName)
+	if err != nil {
+		return fedramp.OSCALSystemSecurityPlan{}, err
+	}
+
+	// Create command
+	cmd := fedramp.ExportSSPCommand{
+		Program: program,
+		Options: options,
+	}
+
+	// Delegate to SSP handler
+	return s.sspHandler.HandleExportSSP(ctx, cmd)
+}
+
+// ImportSSP records the implementations described by an OSCAL system security plan in JSON for a
+// program. The system defaults to the plan's system name. With dryRun, nothing is recorded and only
+// the report is returned.
+func (s *Service) ImportSSP(ctx context.Context, programName string, data []byte, system string, dryRun bool) (fedramp.SSPImport, error) {
+	// Validate arguments
+	if programName == "" {
+		return fedramp.SSPImport{}, fedramp.NewError(fedramp.ErrInvalidArgument, "program name cannot be empty")
+	}
+	ssp, err := fedramp.ParseOSCALSSP(data)
+	if err != nil {
+		return fedramp.SSPImport{}, err
+	}
+
+	// Load the program
+	program, err := s.loadProgram(ctx, programName)
+	if err != nil {
+		return fedramp.SSPImport{}, err
+	}
+
+	// Create command
+	cmd := fedramp.ImportSSPCommand{
+		Program: program,
+		SSP:     ssp,
+		System:  system,
+		DryRun:  dryRun,
+	}
+
+	// Delegate to SSP handler
+	return s.sspHandler.HandleImportSSP(ctx, cmd)
+}
+
+// AssignParameter assigns a system's values to an organization-defined parameter of a program,
+// after checking them against the parameter's choices and constraints. No values removes the
+// assignment.
+func (s *Service) AssignParameter(ctx context.Context, system, programName, parameterID string, values []string) (fedramp.ImplementationRecord, error) {
+	// Validate arguments
+	system = strings.TrimSpace(system)
+	if system == "" {
+		return fedramp.ImplementationRecord{}, fedramp.NewError(fedramp.ErrInvalidArgument, "system cannot be empty")
+	}
+	if programName == "" {
+		return fedramp.ImplementationRecord{}, fedramp.NewError(fedramp.ErrInvalidArgument, "program name cannot be empty")
+	}
+	if strings.TrimSpace(parameterID) == "" {
+		return fedramp.ImplementationRecord{}, fedramp.NewError(fedramp.ErrInvalidArgument, "parameter ID cannot be empty")
+	}
+
+	// Load the program
+	program, err := s.loadProgram(ctx, programName)
+	if err != nil {
+		return fedramp.ImplementationRecord{}, err
+	}
+
+	// Create command
+	cmd := fedramp.AssignParameterCommand{
+		Program:     program,
+		System:      system,
+		ParameterID: parameterID,
+		Values:      values,
+	}
+
+	// Delegate to parameter handler
+	return s.parameterHandler.HandleAssignParameter(ctx, cmd)
+}
+
+// GetParameterReport reports the organization-defined parameters of a program that a system has
+// not assigned values to and the program does not set
+func (s *Service) GetParameterReport(ctx context.Context, system, programName string) (fedramp.ParameterReport, error) {
+	// Validate arguments
+	system = strings.TrimSpace(system)
+	if system == "" {
+		return fedramp.ParameterReport{}, fedramp.NewError(fedramp.ErrInvalidArgument, "system cannot be empty")
+	}
+	if programName == "" {
+		return fedramp.ParameterReport{}, fedramp.NewError(fedramp.ErrInvalidArgument, "program name cannot be empty")
+	}
+
+	// Load the program
+	program, err := s.loadProgram(ctx, programName)
+	if err != nil {
+		return fedramp.ParameterReport{}, err
+	}
+
+	// Create command
+	cmd := fedramp.ParameterReportCommand{
+		Program: program,
+		System:  system,
+	}
+
+	// Delegate to parameter handler
+	return s.parameterHandler.HandleParameterReport(ctx, cmd)
+}
+
+// GetGapAnalysis compares a system's implementation records with the controls of a program
+func (s *Service) GetGapAnalysis(ctx context.Context, system, programName string) (fedramp.GapAnalysis, error) {
+	// Validate arguments
+	system = strings.TrimSpace(system)
+	if system == "" {
+		return fedramp.GapAnalysis{}, fedramp.NewError(fedramp.ErrInvalidArgument, "system cannot be empty")
+	}
+	if programName == "" {
+		return fedramp.GapAnalysis{}, fedramp.NewError(fedramp.ErrInvalidArgument, "program name cannot be empty")
+	}
+
+	// Load the program
+	program, err := s.loadProgram(ctx, programName)
+	if err != nil {
+		return fedramp.GapAnalysis{}, err
+	}
+
+	// Create command
+	cmd := fedramp.GapAnalysisCommand{
+		Program: program,
+		System:  system,
+	}
+
+	// Delegate to gap handler
+	return s.gapHandler.HandleGapAnalysis(ctx, cmd)
+}
+
+// LintNarrative checks the implementation narrative of a control against the control's statement
+// parts, their parameter values and vague language. A draft narrative or draft statement narratives
+// are checked in place of the system's recorded ones; without a draft, a system is required.
+func (s *Service) LintNarrative(ctx context.Context, system, programName, controlID string, narrative *string, statements map[string]string) (fedramp.NarrativeLint, error) {
+	// Validate arguments
+	system = strings.TrimSpace(system)
+	if system == "" && narrative == nil && statements == nil {
+		return fedramp.NarrativeLint{}, fedramp.NewError(fedramp.ErrInvalidArgument, "a system or a narrative is required")
+	}
+	if programName == "" {
+		return fedramp.NarrativeLint{}, fedramp.NewError(fedramp.ErrInvalidArgument, "program name cannot be empty")
+	}
+	if strings.TrimSpace(controlID) == "" {
+		return fedramp.NarrativeLint{}, fedramp.NewError(fedramp.ErrInvalidArgument, "control ID cannot be empty")
+	}
+
+	// Load the program
+	program, err := s.loadProgram(ctx, programName)
+	if err != nil {
+		return fedramp.NarrativeLint{}, err
+	}
+
+	// Create command
+	cmd := fedramp.LintNarrativeCommand{
+		Program:    program,
+		Key:        fedramp.ImplementationKey{System: system, Program: programName, ControlID: controlID},
+		Narrative:  narrative,
+		Statements: statements,
+	}
+
+	// Delegate to narrative handler
+	return s.narrativeHandler.HandleLintNarrative(ctx, cmd)
+}
+
+// GeneratePolicyDocuments generates Markdown policy and procedure documents for control families
+// from templates, as the families' policy and procedures controls (e.g. AC-1) require. The
+// documents use the values a system assigns to parameters when a system is given. Without
+// families, every family with a policy and procedures control is generated, and without kinds,
+// both a policy and procedures. Templates replace the stored templates for their kinds.
+func (s *Service) GeneratePolicyDocuments(ctx context.Context, system, programName string, families []string, kinds []fedramp.PolicyDocumentKind, templates map[fedramp.PolicyDocumentKind]string) ([]fedramp.PolicyDocument, error) {
+	// Validate arguments
+	if programName == "" {
+		return nil, fedramp.NewError(fedramp.ErrInvalidArgument, "program name cannot be empty")
+	}
+	if len(kinds) == 0 {
+		kinds = []fedramp.PolicyDocumentKind{fedramp.PolicyDocumentPolicy, fedramp.PolicyDocumentProcedure}
+	}
+	for kind := range templates {
+		if !slices.Contains(kinds, kind) {
+			return nil, fedramp.NewError(fedramp.ErrInvalidArgument, "a template is given for %s documents, which are not generated", kind)
+		}
+	}
+
+	// Load the program
+	program, err := s.loadProgram(ctx, programName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create command
+	cmd := fedramp.GeneratePolicyDocumentsCommand{
+		Program:   program,
+		System:    strings.TrimSpace(system),
+		Families:  families,
+		Kinds:     kinds,
+		Templates: templates,
+	}
+
+	// Delegate to policy handler
+	return s.policyHandler.HandleGeneratePolicyDocuments(ctx, cmd)
+}
+
+// SetResponsibility records who is responsible for a control, or for one of its statements if a
+// statement is given. An empty role removes the responsibility.
+func (s *Service) SetResponsibility(ctx context.Context, system, programName, controlID, statementID string, responsibility fedramp.Responsibility) (fedramp.ImplementationRecord, error) {
+	// Validate arguments
+	key, err := validateKey(system, programName, controlID)
+	if err != nil {
+		return fedramp.ImplementationRecord{}, err
+	}
+
+	// Load the program
+	program, err := s.loadProgram(ctx, programName)
+	if err != nil {
+		return fedramp.ImplementationRecord{}, err
+	}
+
+	// Create command
+	cmd := fedramp.SetResponsibilityCommand{
+		Program:        program,
+		Key:            key,
+		StatementID:    statementID,
+		Responsibility: responsibility,
+	}
+
+	// Delegate to responsibility handler
+	return s.responsibilityHandler.HandleSetResponsibility(ctx, cmd)
+}
+
+// ImportCRM records the responsibilities described by a leveraged system's customer
+// responsibility matrix in CSV: controls it marks as inheritable are inherited from its
+// authorization. With dryRun, nothing is recorded and only the report is returned.
+func (s *Service) ImportCRM(ctx context.Context, programName string, data []byte, system, leveragedAuthorization string, dryRun bool) (fedramp.CRMImport, error) {
+	// Validate arguments
+	system = strings.TrimSpace(system)
+	if system == "" {
+		return fedramp.CRMImport{}, fedramp.NewError(fedramp.ErrInvalidArgument, "system cannot be empty")
+	}
+	if programName == "" {
+		return fedramp.CRMImport{}, fedramp.NewError(fedramp.ErrInvalidArgument, "program name cannot be empty")
+	}
+	leveragedAuthorization = strings.TrimSpace(leveragedAuthorization)
+	if leveragedAuthorization == "" {
+		return fedramp.CRMImport{}, fedramp.NewError(fedramp.ErrInvalidArgument, "leveraged authorization cannot be empty")
+	}
+
+	// Load the program
+	program, err := s.loadProgram(ctx, programName)
+	if err != nil {
+		return fedramp.CRMImport{}, err
+	}
+
+	// Create command
+	cmd := fedramp.ImportCRMCommand{
+		Program:                program,
+		CRM:                    data,
+		System:                 system,
+		LeveragedAuthorization: leveragedAuthorization,
+		DryRun:                 dryRun,
+	}
+
+	// Delegate to responsibility handler
+	return s.responsibilityHandler.HandleImportCRM(ctx, cmd)
+}
+
+// ExportCRM writes the customer responsibility matrix of a system for a program as CSV, in the
+// FedRAMP CRM workbook layout
+func (s *Service) ExportCRM(ctx context.Context, system, programName string) ([]byte, error) {
+	// Validate arguments
+	system = strings.TrimSpace(system)
+	if system == "" {
+		return nil, fedramp.NewError(fedramp.ErrInvalidArgument, "system cannot be empty")
+	}
+	if programName == "" {
+		return nil, fedramp.NewError(fedramp.ErrInvalidArgument, "program name cannot be empty")
+	}
+
+	// Load the program
+	program, err := s.loadProgram(ctx, programName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create command
+	cmd := fedramp.ExportCRMCommand{
+		Program: program,
+		System:  system,
+	}
+
+	// Delegate to responsibility handler
+	return s.responsibilityHandler.HandleExportCRM(ctx, cmd)
+}
+
+// SetComponent creates or updates a component. A new component needs a type.
+func (s *Service) SetComponent(ctx context.Context, name string, update fedramp.ComponentUpdate) (fedramp.Component, error) {
+	// Validate arguments
+	if err := s.checkComponents(); err != nil {
+		return fedramp.Component{}, err
+	}
+
+	// Create command
+	cmd := fedramp.SetComponentCommand{
+		Name:   name,
+		Update: update,
+	}
+
+	// Delegate to component handler
+	return s.componentHandler.HandleSetComponent(ctx, cmd)
+}
+
+// MapComponent maps a component to a control of a program it implements, with its narrative for
+// the control and the statements it satisfies, or removes the mapping. Statements are given by
+// reference, e.g. "a" for "ac-2_smt.a", each with the component's own narrative or an empty one.
+func (s *Service) MapComponent(ctx context.Context, component, programName, controlID, narrative string, statements map[string]string, remove bool) (fedramp.Component, error) {
+	// Validate arguments
+	if err := s.checkComponents(); err != nil {
+		return fedramp.Component{}, err
+	}
+	if strings.TrimSpace(component) == "" {
+		return fedramp.Component{}, fedramp.NewError(fedramp.ErrInvalidArgument, "component name cannot be empty")
+	}
+	if programName == "" {
+		return fedramp.Component{}, fedramp.NewError(fedramp.ErrInvalidArgument, "program name cannot be empty")
+	}
+	if strings.TrimSpace(controlID) == "" {
+		return fedramp.Component{}, fedramp.NewError(fedramp.ErrInvalidArgument, "control ID cannot be empty")
+	}
+
+	// Load the program
+	program, err := s.loadProgram(ctx, programName)
+	if err != nil {
+		return fedramp.Component{}, err
+	}
+
+	// Create command
+	cmd := fedramp.MapComponentCommand{
+		Program:    program,
+		Component:  component,
+		ControlID:  controlID,
+		Narrative:  narrative,
+		Statements: statements,
+		Remove:     remove,
+	}
+
+	// Delegate to component handler
+	return s.componentHandler.HandleMapComponent(ctx, cmd)
+}
+
+// GetComponentsForControl returns the components that implement a control of a program, with
+// their narratives for it
+func (s *Service) GetComponentsForControl(ctx context.Context, programName, controlID string) ([]fedramp.ComponentMapping, error) {
+	// Validate arguments
+	if err := s.checkComponents(); err != nil {
+		return nil, err
+	}
+	if programName == "" {
+		return nil, fedramp.NewError(fedramp.ErrInvalidArgument, "program name cannot be empty")
+	}
+	if strings.TrimSpace(controlID) == "" {
+		return nil, fedramp.NewError(fedramp.ErrInvalidArgument, "control ID cannot be empty")
+	}
+
+	// Load the program
+	program, err := s.loadProgram(ctx, programName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create command
+	cmd := fedramp.ComponentsForControlCommand{
+		Program:   program,
+		ControlID: controlID,
+	}
+
+	// Delegate to component handler
+	return s.componentHandler.HandleComponentsForControl(ctx, cmd)
+}
+
+// GetControlsForComponent returns a component with the controls it implements in a program, or in
+// every program the caller may access if no program is given
+func (s *Service) GetControlsForComponent(ctx context.Context, component, programName string) (fedramp.Component, error) {
+	// Validate arguments
+	if err := s.checkComponents(); err != nil {
+		return fedramp.Component{}, err
+	}
+	if strings.TrimSpace(component) == "" {
+		return fedramp.Component{}, fedramp.NewError(fedramp.ErrInvalidArgument, "component name cannot be empty")
+	}
+
+	// Load the programs
+	programs, err := s.loadPrograms(ctx, programName)
+	if err != nil {
+		return fedramp.Component{}, err
+	}
+
+	// Create command
+	cmd := fedramp.ControlsForComponentCommand{
+		Component: component,
+		Programs:  programNames(programs),
+	}
+
+	// Delegate to component handler
+	return s.componentHandler.HandleControlsForComponent(ctx, cmd)
+}
+
+// ImportComponentDefinition records the components of an OSCAL component definition in JSON.
+// Controls are matched to the program whose FedRAMP profile is their source, or otherwise to the
+// given program. With dryRun, nothing is recorded and only the report is returned.
+func (s *Service) ImportComponentDefinition(ctx context.Context, data []byte, programName string, dryRun bool) (fedramp.ComponentImport, error) {
+	// Validate arguments
+	if err := s.checkComponents(); err != nil {
+		return fedramp.ComponentImport{}, err
+	}
+	definition, err := fedramp.ParseOSCALComponentDefinition(data)
+	if err != nil {
+		return fedramp.ComponentImport{}, err
+	}
+
+	// Load the programs the caller may access, checking access to the given program
+	if programName != "" {
+		program, err := s.loadProgram(ctx, programName)
+		if err != nil {
+			return fedramp.ComponentImport{}, err
+		}
+		programName = program.Name
+	}
+	programs, err := s.loadPrograms(ctx, "")
+	if err != nil {
+		return fedramp.ComponentImport{}, err
+	}
+
+	// Create command
+	cmd := fedramp.ImportComponentDefinitionCommand{
+		Definition:     definition,
+		Programs:       programs,
+		DefaultProgram: programName,
+		DryRun:         dryRun,
+	}
+
+	// Delegate to component handler
+	return s.componentHandler.HandleImportComponentDefinition(ctx, cmd)
+}
+
+// ExportComponentDefinition generates the OSCAL component definition of components, or of every
+// component if none are named, with their mappings to the controls of a program, or of every
+// program the caller may access if no program is given
+func (s *Service) ExportComponentDefinition(ctx context.Context, components []string, programName string) (fedramp.OSCALComponentDefinition, error) {
+	// Validate arguments
+	if err := s.checkComponents(); err != nil {
+		return fedramp.OSCALComponentDefinition{}, err
+	}
+
+	// Load the programs
+	programs, err := s.loadPrograms(ctx, programName)
+	if err != nil {
+		return fedramp.OSCALComponentDefinition{}, err
+	}
+
+	// Create command
+	cmd := fedramp.ExportComponentDefinitionCommand{
+		Components: components,
+		Programs:   programNames(programs),
+	}
+
+	// Delegate to component handler
+	return s.componentHandler.HandleExportComponentDefinition(ctx, cmd)
+}
+
+// AssignParameters returns a control with the parameter values a system assigns in place of the
+// program's, so that its prose reads with the system's values. A system without a record for the
+// control gets the control unchanged.
+func (s *Service) AssignParameters(ctx context.Context, system, programName string, control fedramp.Control) (fedramp.Control, error) {
+	record, err := s.GetImplementation(ctx, system, programName, control.ID)
+	if fedramp.KindOf(err) == fedramp.ErrNotFound {
+		return control, nil
+	}
+	if err != nil {
+		return fedramp.Control{}, err
+	}
+	return record.AssignParameters(control), nil
+}
+
+// validateKey checks that the parts of an implementation key are present
+func validateKey(system, programName, controlID string) (fedramp.ImplementationKey, error) {
+	system = strings.TrimSpace(system)
+	if system == "" {
+		return fedramp.ImplementationKey{}, fedramp.NewError(fedramp.ErrInvalidArgument, "system cannot be empty")
+	}
+	if programName == "" {
+		return fedramp.ImplementationKey{}, fedramp.NewError(fedramp.ErrInvalidArgument, "program name cannot be empty")
+	}
+	if strings.TrimSpace(controlID) == "" {
+		return fedramp.ImplementationKey{}, fedramp.NewError(fedramp.ErrInvalidArgument, "control ID cannot be empty")
+	}
+	return fedramp.ImplementationKey{System: system, Program: programName, ControlID: controlID}, nil
+}
+
+// checkComponents checks that the service has a component repository
+func (s *Service) checkComponents() error {
+	if s.componentHandler == nil {
+		return fedramp.NewError(fedramp.ErrInternal, "no component store is configured")
+	}
+	return nil
+}
+
+// loadPrograms loads a program the caller may access or, without a program name, every program
+// the caller may access
+func (s *Service) loadPrograms(ctx context.Context, programName string) ([]fedramp.Program, error) {
+	if programName != "" {
+		program, err := s.loadProgram(ctx, programName)
+		if err != nil {
+			return nil, err
+		}
+		return []fedramp.Program{program}, nil
+	}
+
+	names, err := s.complianceRepo.ListPrograms()
+	if err != nil {
+		return nil, err
+	}
+	var programs []fedramp.Program
+	for _, name := range names {
+		program, err := s.loadProgram(ctx, name)
+		if fedramp.KindOf(err) == fedramp.ErrProgramUnavailable || errors.Is(err, auth.ErrPermissionDenied) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		programs = append(programs, program)
+	}
+	return programs, nil
+}
+
+// programNames returns the names of programs
+func programNames(programs []fedramp.Program) []string {
+	names := make([]string, 0, len(programs))
+	for _, program := range programs {
+		names = append(names, program.Name)
+	}
+	return names
+}
+
+// Helper method to load a program the caller may access
+func (s *Service) loadProgram(ctx context.Context, programName string) (fedramp.Program, error) {
+	program, err := s.complianceRepo.LoadProgram(programName)
+	if fedramp.KindOf(err) == fedramp.ErrProgramUnavailable {
+		return fedramp.Program{}, s.hideUnauthorizedPrograms(ctx, err)
+	}
+	if err != nil {
+		return fedramp.Program{}, err
+	}
+
+	if s.policy != nil {
+		if err := s.policy.AuthorizeProgram(ctx, program.Name); err != nil {
+			return fedramp.Program{}, err
+		}
+	}
+	return program, nil
+}
+
+// hideUnauthorizedPrograms removes the programs the caller may not access from the suggestions of
+// an error about an unknown program, so that the error does not reveal them
+func (s *Service) hideUnauthorizedPrograms(ctx context.Context, err error) error {
+	if s.policy == nil {
+		return err
+	}
+	return fedramp.FilterSuggestions(err, func(program string) bool {
+		return s.policy.AuthorizeProgram(ctx, program) == nil
+	})
+}